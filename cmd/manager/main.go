@@ -1,20 +1,31 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	// Embedded so maintenance window time zones resolve in the minimal image
+	_ "time/tzdata"
 
 	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
 	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/controller"
 	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/version"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/webhook"
 )
 
 var (
@@ -31,14 +42,57 @@ func main() {
 	var (
 		metricsAddr             string
 		enableLeaderElection    bool
+		leaderElectionNamespace string
+		leaseDuration           time.Duration
+		renewDeadline           time.Duration
+		retryPeriod             time.Duration
 		probeAddr               string
 		accessKeyId             string
 		accessKeySecret         string
+		credConfig              provider.CredentialConfig
 		regionId                string
 		endpoint                string
+		regionEndpoints         string
+		endpointTemplate        string
+		networkType             string
+		userAgentSuffix         string
 		maxConcurrentReconciles int
 		getListenerQPS          float64
 		createListenerQPS       float64
+		apiQPS                  float64
+		apiBurst                int
+		enableWebhooks          bool
+		webhookPort             int
+		protectedNamespaces     string
+		lbOperationTimeout      time.Duration
+		listenerOpTimeout       time.Duration
+		jobPollInterval         time.Duration
+		jobPollTimeout          time.Duration
+		lbActivePollInterval    time.Duration
+		lbActivePollTimeout     time.Duration
+		stableLBStates          string
+		requestTimeout          time.Duration
+		maxRetries              int
+		retryableErrorCodes     string
+		resyncPeriod            time.Duration
+		operatorId              string
+		defaultTags             string
+		orphanGCInterval        time.Duration
+		orphanGCDelete          bool
+		readyzAPICheckTTL       time.Duration
+		startupAPICheck         bool
+		lbAttributeCacheTTL     time.Duration
+		eventThrottleInterval   time.Duration
+		deletionTimeout         time.Duration
+		deletionRetryMaxBackoff time.Duration
+		deletionStuckAttempts   int
+		deletionStuckInterval   time.Duration
+		forceRemoveFinalizer    bool
+		honorDeletionProtection bool
+		shutdownGracePeriod     time.Duration
+		logFormat               string
+		logLevel                string
+		validatePath            string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -46,58 +100,249 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 5, "Maximum number of concurrent reconciles for NLB controller")
+	flag.StringVar(&leaderElectionNamespace, "leader-elect-namespace", "",
+		"Namespace of the leader election lease; defaults to the namespace the operator runs in")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"Duration non-leader candidates wait after observing a leadership renewal before trying to acquire leadership")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"Duration the acting leader retries refreshing leadership before giving it up; must be less than the lease duration")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"Duration leader election clients wait between attempts")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 5, "Maximum number of concurrent reconciles for each of the NLB, Listener and ServerGroup controllers")
 	flag.StringVar(&accessKeyId, "access-key-id", os.Getenv("ACCESS_KEY_ID"), "Alibaba Cloud Access Key ID")
 	flag.StringVar(&accessKeySecret, "access-key-secret", os.Getenv("ACCESS_KEY_SECRET"), "Alibaba Cloud Access Key Secret")
-	flag.StringVar(&regionId, "region-id", os.Getenv("REGION_ID"), "Alibaba Cloud Region ID")
-	flag.StringVar(&endpoint, "endpoint", "", "Alibaba Cloud NLB API endpoint")
+	flag.StringVar(&credConfig.Mode, "credential-mode", envOrDefault("CREDENTIAL_MODE", provider.CredentialModeAccessKey),
+		"Credential mode: access_key, sts, ecs_ram_role or ram_role_arn")
+	flag.StringVar(&credConfig.SecurityToken, "security-token", os.Getenv("SECURITY_TOKEN"), "STS security token (credential-mode=sts)")
+	flag.StringVar(&credConfig.RoleName, "ram-role-name", os.Getenv("RAM_ROLE_NAME"), "ECS RAM role name (credential-mode=ecs_ram_role, optional)")
+	flag.StringVar(&credConfig.RoleArn, "ram-role-arn", os.Getenv("RAM_ROLE_ARN"), "RAM role ARN to assume (credential-mode=ram_role_arn)")
+	flag.StringVar(&credConfig.RoleSessionName, "ram-role-session-name", os.Getenv("RAM_ROLE_SESSION_NAME"), "Session name used when assuming the RAM role (credential-mode=ram_role_arn)")
+	flag.StringVar(&regionId, "region-id", os.Getenv("REGION_ID"), "Default Alibaba Cloud Region ID, used for resources that do not set a region")
+	flag.StringVar(&endpoint, "endpoint", "", "Alibaba Cloud NLB API endpoint of the default region")
+	flag.StringVar(&regionEndpoints, "region-endpoints", "",
+		"Comma separated region=endpoint pairs overriding the NLB API endpoint of individual regions")
+	flag.StringVar(&endpointTemplate, "endpoint-template", "",
+		"NLB API endpoint of regions without an explicit endpoint, {region} is replaced by the region ID (e.g. nlb-vpc.{region}.aliyuncs.com)")
+	flag.StringVar(&networkType, "network-type", provider.NetworkTypePublic,
+		"Network of the default NLB API endpoints: public or vpc (nlb-vpc.<region>.aliyuncs.com)")
+	flag.StringVar(&userAgentSuffix, "user-agent-suffix", "",
+		"Appended to the alibabacloud-nlb-operator/<version> user agent of NLB OpenAPI requests, e.g. to tell clusters apart in the cloud audit logs")
 	flag.Float64Var(&getListenerQPS, "get-listener-qps", 18.0, "Local QPS limit for GetListenerAttribute API (token-bucket, burst=5)")
 	flag.Float64Var(&createListenerQPS, "create-listener-qps", 3.0, "Local QPS limit for CreateListener API (token-bucket, burst=5)")
+	flag.Float64Var(&apiQPS, "api-qps", 0, "QPS limit for all NLB OpenAPI calls of a region, shared by all reconcile workers; requests wait for a token (0 disables)")
+	flag.IntVar(&apiBurst, "api-burst", 10, "Burst of the --api-qps token bucket")
+	flag.DurationVar(&lbOperationTimeout, "lb-operation-timeout", 0, "Timeout for waiting on load balancer level async jobs (delete, attribute and security group updates); defaults to --job-poll-timeout")
+	flag.DurationVar(&listenerOpTimeout, "listener-operation-timeout", 0, "Timeout for waiting on listener level async jobs; defaults to --job-poll-timeout")
+	flag.DurationVar(&requestTimeout, "request-timeout", 30*time.Second, "Timeout for a single NLB OpenAPI request (0 disables)")
+	flag.IntVar(&maxRetries, "max-retries", 3, "Maximum client-side retries for NLB OpenAPI requests failing with a retryable error code")
+	flag.StringVar(&retryableErrorCodes, "retryable-error-codes", strings.Join(provider.DefaultRetryableErrorCodes, ","),
+		"Comma separated NLB OpenAPI error codes that are retried with exponential backoff")
+	flag.DurationVar(&jobPollInterval, "job-poll-interval", 3*time.Second, "Interval between GetJobStatus polls while waiting for async jobs")
+	flag.DurationVar(&jobPollTimeout, "job-poll-timeout", 3*time.Minute, "Default timeout for waiting on async jobs")
+	flag.DurationVar(&lbActivePollInterval, "lb-active-poll-interval", 10*time.Second, "Interval between status checks (requeues) while waiting for a load balancer to become Active")
+	flag.DurationVar(&lbActivePollTimeout, "lb-active-poll-timeout", 5*time.Minute, "Deprecated: has no effect, load balancers are checked by requeue every --lb-active-poll-interval until Active")
+	flag.StringVar(&stableLBStates, "stable-lb-states", strings.Join(provider.DefaultStableLoadBalancerStates, ","),
+		"Comma separated load balancer statuses in which updates are considered applied; the NLB is reported Ready only once it reaches one of them")
+	flag.DurationVar(&resyncPeriod, "resync-period", 5*time.Minute, "Interval at which healthy NLBs and Listeners are re-checked against the cloud for drift")
+	flag.StringVar(&operatorId, "operator-id", "nlb-operator",
+		"Identifies this operator instance in the nlb-operator/managed-by tag of the NLB instances it creates")
+	flag.StringVar(&defaultTags, "default-tags", "",
+		"Comma separated key=value tags applied to every NLB instance; tags of the NLB spec take precedence on key conflicts")
+	flag.DurationVar(&orphanGCInterval, "orphan-gc-interval", 0,
+		"Interval at which NLB instances tagged as managed by this operator but without an NLB CR are reported (0 disables)")
+	flag.BoolVar(&orphanGCDelete, "orphan-gc-delete", false,
+		"Delete orphan NLB instances found in two consecutive passes; instances with deletion protection are kept. Requires --orphan-gc-interval")
+	flag.DurationVar(&lbAttributeCacheTTL, "lb-attribute-cache-ttl", 10*time.Second,
+		"How long the attributes of an Active load balancer are reused across reconciles; mutations and force-sync bypass the cache (0 disables)")
+	flag.DurationVar(&deletionTimeout, "deletion-timeout", 30*time.Minute,
+		"How long deletion of an NLB may keep failing before a DeletionTimeout warning is raised (0 disables)")
+	flag.DurationVar(&deletionRetryMaxBackoff, "deletion-retry-max-backoff", 5*time.Minute,
+		"Maximum interval between retries of a failing NLB deletion, which grows exponentially from 5s")
+	flag.IntVar(&deletionStuckAttempts, "deletion-stuck-attempts", 10,
+		"Consecutive failed deletion attempts after which an NLB gets the DeletionStuck condition and warning event (0 disables)")
+	flag.DurationVar(&deletionStuckInterval, "deletion-stuck-retry-interval", 15*time.Minute,
+		"Retry interval of an NLB deletion once it is stuck")
+	flag.BoolVar(&forceRemoveFinalizer, "force-remove-finalizer-on-timeout", false,
+		"Remove the finalizer of an NLB whose deletion exceeded --deletion-timeout, orphaning the cloud instance")
+	flag.BoolVar(&honorDeletionProtection, "honor-deletion-protection", false,
+		"Refuse to delete NLBs whose spec enables deletion protection until it is disabled, instead of lifting the protection and deleting the instance")
+	flag.DurationVar(&shutdownGracePeriod, "shutdown-grace-period", 20*time.Second,
+		"How long async jobs in flight at shutdown are still waited for, so changes reach a consistent state (0 disables); keep it below the pod's terminationGracePeriodSeconds")
+	flag.DurationVar(&eventThrottleInterval, "event-throttle-interval", 5*time.Minute,
+		"Minimum interval between identical events of an object; a changed message is always emitted (0 disables)")
+	flag.DurationVar(&readyzAPICheckTTL, "readyz-api-check-ttl", 30*time.Second,
+		"How long the result of the NLB API connectivity check in the readiness probe is reused (0 disables the check)")
+	flag.BoolVar(&startupAPICheck, "startup-api-check", true,
+		"Call DescribeZones at startup and exit if the credentials are rejected or the endpoint does not serve --region-id")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Enable the NLB admission webhooks")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to")
+	flag.StringVar(&protectedNamespaces, "default-deletion-protection-namespaces", "",
+		"Comma separated namespace glob patterns (e.g. prod-*) whose NLBs default to deletion protection enabled. Requires --enable-webhooks")
 
-	opts := zap.Options{
-		Development: true,
-	}
+	flag.StringVar(&logFormat, "log-format", "json", "Log format: json or console")
+	flag.StringVar(&logLevel, "log-level", "info",
+		"Log level: debug, info, error or an integer verbosity (e.g. 5 also enables the NLB API request logs)")
+
+	flag.StringVar(&validatePath, "validate-file", "",
+		"Validate the NLB and Listener manifests in the given YAML file with the webhook rules and exit, without connecting to a cluster or the cloud")
+
+	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if validatePath != "" {
+		invalid, err := validateFile(validatePath, os.Stderr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if invalid > 0 {
+			fmt.Fprintf(os.Stderr, "%d invalid object(s) in %s\n", invalid, validatePath)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := applyLogFlags(&opts, logFormat, logLevel); err != nil {
+		setupLog.Error(err, "invalid logging flags")
+		os.Exit(1)
+	}
+	logger := zap.New(zap.UseFlagOptions(&opts))
+	ctrl.SetLogger(logger)
+	// Route klog (used by the provider and controllers) through the same logger
+	klog.SetLogger(logger)
 
 	// Validate required parameters
-	if accessKeyId == "" || accessKeySecret == "" || regionId == "" {
-		setupLog.Error(nil, "Missing required parameters: ACCESS_KEY_ID, ACCESS_KEY_SECRET, or REGION_ID")
+	credConfig.AccessKeyId = accessKeyId
+	credConfig.AccessKeySecret = accessKeySecret
+	if regionId == "" {
+		setupLog.Error(nil, "Missing required parameter: REGION_ID")
+		os.Exit(1)
+	}
+	if err := credConfig.Validate(); err != nil {
+		setupLog.Error(err, "Invalid credential configuration")
+		os.Exit(1)
+	}
+
+	deletionProtectionNamespaces, err := webhook.ParseNamespacePatterns(protectedNamespaces)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-deletion-protection-namespaces")
+		os.Exit(1)
+	}
+
+	operatorTags, err := parseTags(defaultTags)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-tags")
+		os.Exit(1)
+	}
+
+	if apiQPS > 0 && apiBurst < 1 {
+		setupLog.Error(nil, "--api-burst must be at least 1 when --api-qps is set", "apiBurst", apiBurst)
+		os.Exit(1)
+	}
+
+	if enableLeaderElection && renewDeadline >= leaseDuration {
+		setupLog.Error(nil, "--leader-elect-renew-deadline must be less than --leader-elect-lease-duration",
+			"renewDeadline", renewDeadline, "leaseDuration", leaseDuration)
 		os.Exit(1)
 	}
 
+	// Leave the manager a little longer than the job drain to stop its runnables
+	gracefulShutdownTimeout := shutdownGracePeriod + 5*time.Second
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "nlb-operator.alibabacloud.com",
+		Scheme:                  scheme,
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "nlb-operator.alibabacloud.com",
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		WebhookServer:           ctrlwebhook.NewServer(ctrlwebhook.Options{Port: webhookPort}),
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	// Create NLB client
-	nlbClient, err := provider.NewNLBClient(endpoint, accessKeyId, accessKeySecret, regionId)
+	// Create NLB clients. Resources in other regions get a client from the pool
+	// lazily, configured like the default one below.
+	endpointsByRegion, err := provider.ParseRegionEndpoints(regionEndpoints)
+	if err != nil {
+		setupLog.Error(err, "invalid --region-endpoints")
+		os.Exit(1)
+	}
+	clientPool, err := provider.NewClientPool(provider.EndpointConfig{
+		Endpoint:        endpoint,
+		RegionEndpoints: endpointsByRegion,
+		Template:        endpointTemplate,
+		NetworkType:     networkType,
+	}, regionId, credConfig, provider.UserAgent(userAgentSuffix))
 	if err != nil {
 		setupLog.Error(err, "unable to create NLB client")
 		os.Exit(1)
 	}
+	nlbClient := clientPool.Default()
 
 	// Initialize per-interface local rate limiter for GetListenerAttribute.
 	nlbClient.GetListenerLimiter = rate.NewLimiter(rate.Limit(getListenerQPS), 5)
 	// Initialize per-interface local rate limiter for CreateListener.
 	nlbClient.CreateListenerLimiter = rate.NewLimiter(rate.Limit(createListenerQPS), 5)
+	// Client-wide rate limiter gating every OpenAPI call.
+	if apiQPS > 0 {
+		nlbClient.APILimiter = rate.NewLimiter(rate.Limit(apiQPS), apiBurst)
+	}
+	// Per-operation async job timeouts.
+	nlbClient.LoadBalancerOperationTimeout = lbOperationTimeout
+	nlbClient.ListenerOperationTimeout = listenerOpTimeout
+	nlbClient.RequestTimeout = requestTimeout
+	// Client-side retry for throttling and other transient errors.
+	nlbClient.MaxRetries = maxRetries
+	for _, code := range strings.Split(retryableErrorCodes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			nlbClient.RetryableErrorCodes = append(nlbClient.RetryableErrorCodes, code)
+		}
+	}
+	// Async job polling.
+	nlbClient.JobPollInterval = jobPollInterval
+	nlbClient.JobPollTimeout = jobPollTimeout
+	nlbClient.AttributeCacheTTL = lbAttributeCacheTTL
+	nlbClient.ShutdownGracePeriod = shutdownGracePeriod
+
+	if startupAPICheck {
+		if err := nlbClient.CheckRegion(context.Background(), regionId); err != nil {
+			setupLog.Error(err, "NLB API startup check failed, check the credentials, --region-id and endpoint flags", "region", regionId)
+			os.Exit(1)
+		}
+	}
 
 	// Setup NLB controller
+	var stableStates []string
+	for _, state := range strings.Split(stableLBStates, ",") {
+		if state = strings.TrimSpace(state); state != "" {
+			stableStates = append(stableStates, state)
+		}
+	}
+
 	if err = (&controller.NLBReconciler{
-		Client:                  mgr.GetClient(),
-		Scheme:                  mgr.GetScheme(),
-		Recorder:                mgr.GetEventRecorderFor("nlb-controller"),
-		NLBClient:               nlbClient,
-		MaxConcurrentReconciles: maxConcurrentReconciles,
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		Recorder:                   controller.NewThrottledRecorder(mgr.GetEventRecorderFor("nlb-controller"), eventThrottleInterval),
+		NLBClient:                  nlbClient,
+		Clients:                    clientPool,
+		MaxConcurrentReconciles:    maxConcurrentReconciles,
+		ActiveCheckInterval:        lbActivePollInterval,
+		StableLoadBalancerStates:   stableStates,
+		ResyncPeriod:               resyncPeriod,
+		OperatorId:                 operatorId,
+		DefaultTags:                operatorTags,
+		DeletionTimeout:            deletionTimeout,
+		DeletionRetryMaxBackoff:    deletionRetryMaxBackoff,
+		DeletionStuckAttempts:      deletionStuckAttempts,
+		DeletionStuckRetryInterval: deletionStuckInterval,
+		ForceRemoveFinalizer:       forceRemoveFinalizer,
+		HonorDeletionProtection:    honorDeletionProtection,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NLB")
 		os.Exit(1)
@@ -107,8 +352,9 @@ func main() {
 	if err = (&controller.ServerGroupReconciler{
 		Client:                  mgr.GetClient(),
 		Scheme:                  mgr.GetScheme(),
-		Recorder:                mgr.GetEventRecorderFor("servergroup-controller"),
+		Recorder:                controller.NewThrottledRecorder(mgr.GetEventRecorderFor("servergroup-controller"), eventThrottleInterval),
 		NLBClient:               nlbClient,
+		Clients:                 clientPool,
 		MaxConcurrentReconciles: maxConcurrentReconciles,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServerGroup")
@@ -119,14 +365,44 @@ func main() {
 	if err = (&controller.ListenerReconciler{
 		Client:                  mgr.GetClient(),
 		Scheme:                  mgr.GetScheme(),
-		Recorder:                mgr.GetEventRecorderFor("listener-controller"),
+		Recorder:                controller.NewThrottledRecorder(mgr.GetEventRecorderFor("listener-controller"), eventThrottleInterval),
 		NLBClient:               nlbClient,
+		Clients:                 clientPool,
 		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ResyncPeriod:            resyncPeriod,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Listener")
 		os.Exit(1)
 	}
 
+	// Setup orphan NLB collector
+	if orphanGCInterval > 0 {
+		if err := mgr.Add(&controller.OrphanCollector{
+			Client:     mgr.GetClient(),
+			Clients:    clientPool,
+			OperatorId: operatorId,
+			Interval:   orphanGCInterval,
+			Delete:     orphanGCDelete,
+		}); err != nil {
+			setupLog.Error(err, "unable to add orphan NLB collector")
+			os.Exit(1)
+		}
+	}
+
+	// Setup NLB defaulting webhook
+	if enableWebhooks {
+		if err = (&webhook.NLBDefaulter{
+			DeletionProtectionNamespaces: deletionProtectionNamespaces,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NLB")
+			os.Exit(1)
+		}
+		if err = (&webhook.ListenerValidator{}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Listener")
+			os.Exit(1)
+		}
+	}
+
 	// Add health check
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -138,10 +414,88 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// Report not ready while the credentials are invalid or the endpoint unreachable
+	if readyzAPICheckTTL > 0 {
+		if err := mgr.AddReadyzCheck("nlb-api", nlbClient.APIReadyChecker(readyzAPICheckTTL)); err != nil {
+			setupLog.Error(err, "unable to set up NLB API ready check")
+			os.Exit(1)
+		}
+	}
 
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	setupLog.Info("starting manager", "version", version.Version)
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	if jobs := provider.InflightJobs(); len(jobs) > 0 {
+		setupLog.Info("Exiting with async jobs still in flight, the operations may be incomplete and are re-checked by the next reconcile",
+			"jobIds", jobs)
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// applyLogFlags applies --log-format and --log-level to opts and sets the klog
+// verbosity matching the level. An explicit --zap-encoder or --zap-log-level wins,
+// and with --zap-devel only explicitly given --log-* flags override its defaults.
+func applyLogFlags(opts *zap.Options, format, level string) error {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	zapFlags := flag.NewFlagSet("zap", flag.ContinueOnError)
+	opts.BindFlags(zapFlags)
+	if !explicit["zap-encoder"] && (!opts.Development || explicit["log-format"]) {
+		if err := zapFlags.Set("zap-encoder", format); err != nil {
+			return err
+		}
+	}
+	if !explicit["zap-log-level"] && (!opts.Development || explicit["log-level"]) {
+		if err := zapFlags.Set("zap-log-level", level); err != nil {
+			return err
+		}
+	}
+
+	verbosity := 0
+	if level == "debug" {
+		verbosity = 1
+	} else if n, err := strconv.Atoi(level); err == nil {
+		verbosity = n
+	}
+	klogFlags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(klogFlags)
+	return klogFlags.Set("v", strconv.Itoa(verbosity))
+}
+
+// parseTags parses a comma separated list of key=value tags. Keys with the
+// reserved provenance prefix are rejected.
+func parseTags(value string) ([]nlbv1.Tag, error) {
+	var tags []nlbv1.Tag
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", pair)
+		}
+		if strings.HasPrefix(key, nlbv1.ProvenanceTagPrefix) {
+			return nil, fmt.Errorf("tag key %q uses the reserved prefix %s", key, nlbv1.ProvenanceTagPrefix)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate tag key %q", key)
+		}
+		seen[key] = true
+		tags = append(tags, nlbv1.Tag{Key: key, Value: val})
+	}
+	return tags, nil
+}
+
+// envOrDefault returns the value of the environment variable key, or def when unset.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
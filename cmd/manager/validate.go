@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/webhook"
+)
+
+// validateFile validates the NLB and Listener objects in the multi-document YAML
+// or JSON file at path with the rules of the admission webhooks, printing one line
+// per invalid object to w. Objects of other kinds are skipped. It returns the
+// number of invalid objects.
+func validateFile(path string, w io.Writer) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		invalid   int
+		listeners []nlbv1.Listener
+	)
+	report := func(kind, namespace, name string, err error) {
+		invalid++
+		fmt.Fprintf(w, "%s: %s %s/%s: %v\n", path, kind, namespace, name, err)
+	}
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return invalid, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if obj.Object == nil || obj.GroupVersionKind().Group != nlbv1.SchemeGroupVersion.Group {
+			continue
+		}
+
+		switch obj.GetKind() {
+		case "NLB":
+			nlb := &nlbv1.NLB{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, nlb); err != nil {
+				report("NLB", obj.GetNamespace(), obj.GetName(), err)
+				continue
+			}
+			if err := webhook.ValidateNLB(nlb); err != nil {
+				report("NLB", nlb.Namespace, nlb.Name, err)
+			}
+		case "Listener":
+			lsn := nlbv1.Listener{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &lsn); err != nil {
+				report("Listener", obj.GetNamespace(), obj.GetName(), err)
+				continue
+			}
+			if err := webhook.ValidateListener(&lsn); err != nil {
+				report("Listener", lsn.Namespace, lsn.Name, err)
+				continue
+			}
+			// Ports must also be unique among the Listeners of the file
+			var sameNamespace []nlbv1.Listener
+			for _, other := range listeners {
+				if other.Namespace == lsn.Namespace {
+					sameNamespace = append(sameNamespace, other)
+				}
+			}
+			if err := webhook.PortConflicts(&lsn, sameNamespace); err != nil {
+				report("Listener", lsn.Namespace, lsn.Name, err)
+				continue
+			}
+			listeners = append(listeners, lsn)
+		}
+	}
+	return invalid, nil
+}
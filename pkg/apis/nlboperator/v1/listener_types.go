@@ -17,16 +17,72 @@ const (
 	ListenerFailed   ListenerPhase = "Failed"
 )
 
+// Listener AdminState 取值
+const (
+	ListenerAdminStateRunning = "Running"
+	ListenerAdminStateStopped = "Stopped"
+)
+
+// ALPN 协商策略取值
+const (
+	AlpnPolicyHTTP1Only      = "HTTP1Only"
+	AlpnPolicyHTTP2Only      = "HTTP2Only"
+	AlpnPolicyHTTP2Preferred = "HTTP2Preferred"
+	AlpnPolicyHTTP2Optional  = "HTTP2Optional"
+)
+
+// 监听空闲连接超时时间取值范围 (秒), UDP 监听的上限远小于 TCP / TCPSSL
+const (
+	ListenerIdleTimeoutMin    int32 = 1
+	ListenerIdleTimeoutMax    int32 = 900
+	UDPListenerIdleTimeoutMax int32 = 20
+)
+
 // ListenerFinalizer 用于清理云端 Listener 资源
 const ListenerFinalizer = "nlboperator.alibabacloud.com/listener-finalizer"
 
+// AdditionalCert 定义 TCPSSL 监听的扩展证书 (SNI)
+type AdditionalCert struct {
+	// Domain 证书对应的域名, 仅用于标识; NLB 按证书中的域名进行 SNI 匹配
+	// +optional
+	Domain string `json:"domain,omitempty"`
+	// CertificateId 证书 ID
+	CertificateId string `json:"certificateId"`
+}
+
+// ProxyProtocolV2Config 定义 Proxy Protocol v2 头部中附加的字段
+type ProxyProtocolV2Config struct {
+	// VpcIdEnabled 在头部中携带客户端所在 VPC 的 ID
+	// +optional
+	VpcIdEnabled bool `json:"vpcIdEnabled,omitempty"`
+	// PrivateLinkEpIdEnabled 在头部中携带 PrivateLink 终端节点 ID
+	// +optional
+	PrivateLinkEpIdEnabled bool `json:"privateLinkEpIdEnabled,omitempty"`
+	// PrivateLinkEpsIdEnabled 在头部中携带 PrivateLink 终端节点服务 ID
+	// +optional
+	PrivateLinkEpsIdEnabled bool `json:"privateLinkEpsIdEnabled,omitempty"`
+}
+
 // ListenerSpec defines the desired state of Listener
+// +kubebuilder:validation:XValidation:rule="!has(self.additionalCertificates) || size(self.additionalCertificates) == 0 || self.listenerProtocol == 'TCPSSL'",message="additionalCertificates is only supported for TCPSSL listeners"
+// +kubebuilder:validation:XValidation:rule="(!has(self.alpnEnabled) && !has(self.alpnPolicy)) || self.listenerProtocol == 'TCPSSL'",message="alpnEnabled and alpnPolicy are only supported for TCPSSL listeners"
+// +kubebuilder:validation:XValidation:rule="!has(self.alpnEnabled) || !self.alpnEnabled || has(self.alpnPolicy)",message="alpnEnabled requires alpnPolicy"
+// +kubebuilder:validation:XValidation:rule="!has(self.securityPolicyId) || self.listenerProtocol == 'TCPSSL'",message="securityPolicyId is only supported for TCPSSL listeners"
+// +kubebuilder:validation:XValidation:rule="(!has(self.caEnabled) && (!has(self.caCertificateIds) || size(self.caCertificateIds) == 0)) || self.listenerProtocol == 'TCPSSL'",message="caEnabled and caCertificateIds are only supported for TCPSSL listeners"
+// +kubebuilder:validation:XValidation:rule="!has(self.caEnabled) || !self.caEnabled || (has(self.caCertificateIds) && size(self.caCertificateIds) > 0)",message="caEnabled requires at least one caCertificateIds entry"
+// +kubebuilder:validation:XValidation:rule="!has(self.proxyProtocolV2Config) || (has(self.proxyProtocolEnabled) && self.proxyProtocolEnabled)",message="proxyProtocolV2Config requires proxyProtocolEnabled"
+// +kubebuilder:validation:XValidation:rule="!has(self.idleTimeout) || self.listenerProtocol != 'UDP' || self.idleTimeout <= 20",message="idleTimeout of UDP listeners must be between 1 and 20 seconds"
 type ListenerSpec struct {
 	// Region 阿里云区域
 	Region string `json:"region"`
 	// LoadBalancerRef 引用 NLB CR name (同namespace)
 	LoadBalancerRef string `json:"loadBalancerRef"`
-	// ListenerPort NLB 上的监听端口
+	// Name 监听名称, 作为云端监听描述 (ListenerDescription) 下发并保持同步;
+	// 修改端口重建云端监听时保持不变, 不设置时保留云端当前描述
+	// +optional
+	// +kubebuilder:validation:MaxLength=256
+	Name string `json:"name,omitempty"`
+	// ListenerPort NLB 上的监听端口, 修改后会在新端口重建云端监听
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
 	ListenerPort int32 `json:"listenerPort"`
@@ -35,6 +91,52 @@ type ListenerSpec struct {
 	ListenerProtocol string `json:"listenerProtocol"`
 	// ServerGroupRef 引用 ServerGroup CR name (跨 NLB 共享)
 	ServerGroupRef string `json:"serverGroupRef"`
+	// AdditionalCertificates TCPSSL 监听的扩展证书 (SNI), 按差异关联/解除关联
+	// +optional
+	AdditionalCertificates []AdditionalCert `json:"additionalCertificates,omitempty"`
+	// AdminState 期望的监听运行状态: Running / Stopped, Stopped 时暂停监听而不删除
+	// +optional
+	// +kubebuilder:validation:Enum=Running;Stopped
+	// +kubebuilder:default=Running
+	AdminState string `json:"adminState,omitempty"`
+	// AlpnEnabled TCPSSL 监听是否开启 ALPN 协议协商, 不设置时保留云端当前值
+	// +optional
+	AlpnEnabled *bool `json:"alpnEnabled,omitempty"`
+	// AlpnPolicy ALPN 协商策略: HTTP1Only / HTTP2Only / HTTP2Preferred / HTTP2Optional, 开启 ALPN 时必填
+	// +optional
+	// +kubebuilder:validation:Enum=HTTP1Only;HTTP2Only;HTTP2Preferred;HTTP2Optional
+	AlpnPolicy string `json:"alpnPolicy,omitempty"`
+	// Cps 监听在每个可用区的每秒新建连接数上限, 0 表示不限制; TCP / UDP / TCPSSL 均支持,
+	// 不设置时保留云端当前值
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000000
+	Cps *int32 `json:"cps,omitempty"`
+	// IdleTimeout 空闲连接超时时间 (秒): TCP / TCPSSL 为 1-900, UDP 为 1-20;
+	// 不设置时保留云端当前值
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=900
+	IdleTimeout *int32 `json:"idleTimeout,omitempty"`
+	// SecurityPolicyId TCPSSL 监听的 TLS 安全策略: 系统策略 (例如 tls_cipher_policy_1_2) 或自定义策略 ID,
+	// 创建和修改前校验策略存在; 不设置时保留云端当前值
+	// +optional
+	SecurityPolicyId string `json:"securityPolicyId,omitempty"`
+	// CaEnabled TCPSSL 监听是否开启双向认证 (校验客户端证书), 开启时 CaCertificateIds 必填;
+	// 不设置时保留云端当前值
+	// +optional
+	CaEnabled *bool `json:"caEnabled,omitempty"`
+	// CaCertificateIds 校验客户端证书所用的 CA 证书 ID, 仅 TCPSSL 监听支持
+	// +optional
+	CaCertificateIds []string `json:"caCertificateIds,omitempty"`
+	// ProxyProtocolEnabled 是否通过 Proxy Protocol 向后端传递客户端地址; TCP / UDP / TCPSSL 均支持,
+	// 不设置时保留云端当前值
+	// +optional
+	ProxyProtocolEnabled *bool `json:"proxyProtocolEnabled,omitempty"`
+	// ProxyProtocolV2Config Proxy Protocol v2 头部的附加字段, 需同时开启 ProxyProtocolEnabled;
+	// 不设置时保留云端当前值
+	// +optional
+	ProxyProtocolV2Config *ProxyProtocolV2Config `json:"proxyProtocolV2Config,omitempty"`
 }
 
 // ListenerStatus defines the observed state of Listener
@@ -42,12 +144,30 @@ type ListenerStatus struct {
 	// ListenerId 云端 Listener ID
 	// +optional
 	ListenerId string `json:"listenerId,omitempty"`
+	// ListenerPort 云端 Listener 当前使用的端口, 与 Spec.ListenerPort 不同时表示端口变更尚未完成
+	// +optional
+	ListenerPort int32 `json:"listenerPort,omitempty"`
 	// Phase 当前阶段
 	// +optional
 	Phase ListenerPhase `json:"phase,omitempty"`
+	// CreateTime 提交 CreateListener (或其返回 AlreadyExists) 的时间, 用于限制等待 ListListeners 返回该 Listener 的时长
+	// +optional
+	CreateTime *metav1.Time `json:"createTime,omitempty"`
+	// Status 云端 Listener 状态 (例如 Running / Stopped / Configuring)
+	// +optional
+	Status string `json:"status,omitempty"`
+	// Reason 最近一次状态变化的原因 (例如 CreateFailed)
+	// +optional
+	Reason string `json:"reason,omitempty"`
 	// Message 附加诊断信息
 	// +optional
 	Message string `json:"message,omitempty"`
+	// PlannedActions dry-run 模式下本次调和将执行的操作
+	// +optional
+	PlannedActions []string `json:"plannedActions,omitempty"`
+	// Conditions Listener 的详细状态 (Ready)
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -58,6 +178,8 @@ type ListenerStatus struct {
 // +kubebuilder:printcolumn:name="Port",type=integer,JSONPath=`.spec.listenerPort`
 // +kubebuilder:printcolumn:name="Protocol",type=string,JSONPath=`.spec.listenerProtocol`
 // +kubebuilder:printcolumn:name="ListenerId",type=string,JSONPath=`.status.listenerId`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:resource:shortName=lsn
 
@@ -10,6 +10,16 @@ func init() {
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="LoadBalancerId",type=string,JSONPath=`.status.loadBalancerId`
+// +kubebuilder:printcolumn:name="DNSName",type=string,JSONPath=`.status.dnsName`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.loadBalancerStatus`
+// +kubebuilder:printcolumn:name="CrossZone",type=boolean,JSONPath=`.status.crossZoneEnabled`
+// +kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.status.regionId`,priority=1
+// +kubebuilder:printcolumn:name="CreateTime",type=string,JSONPath=`.status.createTime`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // NLB is the Schema for the nlbs API
 type NLB struct {
@@ -21,6 +31,7 @@ type NLB struct {
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
 
 // NLBList contains a list of NLB
 type NLBList struct {
@@ -30,14 +41,35 @@ type NLBList struct {
 }
 
 // NLBSpec defines the desired state of NLB
+// +kubebuilder:validation:XValidation:rule="self.addressIpVersion == 'DualStack' || (!has(self.ipv6AddressType) && self.zoneMappings.all(z, !has(z.ipv6Address)))",message="ipv6AddressType and zoneMappings[].ipv6Address require addressIpVersion DualStack"
+// +kubebuilder:validation:XValidation:rule="!has(self.bandwidthPackageId) || self.addressType == 'Internet'",message="bandwidthPackageId requires addressType Internet"
+// +kubebuilder:validation:XValidation:rule="!has(self.tags) || self.tags.all(t, !t.key.startsWith('nlb-operator/'))",message="tag keys prefixed with nlb-operator/ are reserved for the operator"
 type NLBSpec struct {
 	// LoadBalancerName is the name of the NLB instance
 	// +optional
 	LoadBalancerName string `json:"loadBalancerName,omitempty"`
 
-	// AddressType is the network type of the NLB instance
+	// RegionId is the region to create the NLB instance in. Defaults to the
+	// operator's --region-id when empty
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="regionId is immutable"
+	// +optional
+	RegionId string `json:"regionId,omitempty"`
+
+	// LoadBalancerId binds this CR to an existing NLB instance instead of creating one.
+	// The adopted instance is managed (and deleted) like one created by the operator.
+	// +optional
+	LoadBalancerId string `json:"loadBalancerId,omitempty"`
+
+	// AdoptExistingByName adopts an existing NLB named LoadBalancerName in VpcId,
+	// if one exists, instead of creating a new instance
+	// +optional
+	AdoptExistingByName bool `json:"adoptExistingByName,omitempty"`
+
+	// AddressType is the network type of the NLB instance. Changing it is not
+	// supported by this operator; recreate the NLB to switch the network type
 	// Valid values: Internet, Intranet
 	// +kubebuilder:validation:Enum=Internet;Intranet
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="changing addressType is not supported by this operator, recreate the NLB to change the network type"
 	// +kubebuilder:default=Internet
 	AddressType string `json:"addressType"`
 
@@ -48,6 +80,12 @@ type NLBSpec struct {
 	// +optional
 	AddressIpVersion string `json:"addressIpVersion,omitempty"`
 
+	// Ipv6AddressType is the network type of the IPv6 address, only valid for DualStack
+	// Valid values: Internet, Intranet
+	// +kubebuilder:validation:Enum=Internet;Intranet
+	// +optional
+	Ipv6AddressType string `json:"ipv6AddressType,omitempty"`
+
 	// VpcId is the VPC ID where the NLB instance resides
 	VpcId string `json:"vpcId"`
 
@@ -55,7 +93,8 @@ type NLBSpec struct {
 	// +kubebuilder:validation:MinItems=2
 	ZoneMappings []ZoneMapping `json:"zoneMappings"`
 
-	// ResourceGroupId is the resource group ID
+	// ResourceGroupId is the resource group ID. Changing it moves the instance to
+	// the new resource group; unset leaves the live group alone
 	// +optional
 	ResourceGroupId string `json:"resourceGroupId,omitempty"`
 
@@ -63,10 +102,28 @@ type NLBSpec struct {
 	// +optional
 	SecurityGroupIds []string `json:"securityGroupIds,omitempty"`
 
-	// BandwidthPackageId is the bandwidth package ID for Internet NLB
+	// BandwidthPackageId is the bandwidth package ID for Internet NLB. It can be
+	// attached, changed or removed after creation
 	// +optional
 	BandwidthPackageId string `json:"bandwidthPackageId,omitempty"`
 
+	// Capacity configures the capacity limits of the NLB instance. It is applied
+	// once the instance is Active and kept in sync afterwards
+	// +optional
+	Capacity *CapacityConfig `json:"capacity,omitempty"`
+
+	// CrossZoneEnabled specifies whether cross-zone load balancing is enabled, i.e.
+	// whether each zone forwards to backends in all zones. Unset leaves the live
+	// value alone
+	// +optional
+	CrossZoneEnabled *bool `json:"crossZoneEnabled,omitempty"`
+
+	// BillingConfig configures the billing of the NLB instance. It is only applied
+	// at creation and cannot be changed afterwards
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="billingConfig is immutable"
+	// +optional
+	BillingConfig *BillingConfig `json:"billingConfig,omitempty"`
+
 	// DeletionProtection specifies whether to enable deletion protection
 	// +optional
 	DeletionProtection *DeletionProtectionConfig `json:"deletionProtection,omitempty"`
@@ -78,8 +135,71 @@ type NLBSpec struct {
 	// Tags are the tags to be added to the NLB instance
 	// +optional
 	Tags []Tag `json:"tags,omitempty"`
+
+	// TagMode controls how tags added outside the operator are treated
+	// Valid values: additive (keep external tags), exclusive (remove any tag not in Tags)
+	// +kubebuilder:validation:Enum=additive;exclusive
+	// +kubebuilder:default=additive
+	// +optional
+	TagMode string `json:"tagMode,omitempty"`
+
+	// DeleteOrphanListeners deletes listeners that are not backed by a Listener CR
+	// (e.g. created in the console) before deleting the NLB instance
+	// +optional
+	DeleteOrphanListeners bool `json:"deleteOrphanListeners,omitempty"`
+
+	// MaintenanceWindow restricts when changes to an existing instance are applied.
+	// Outside the window the needed changes are only recorded in
+	// Status.PendingChanges; creation and deletion are not deferred
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
+const (
+	// TagModeAdditive only manages tags listed in Spec.Tags and keeps external tags
+	TagModeAdditive = "additive"
+	// TagModeExclusive makes Spec.Tags the complete set of user tags on the instance
+	TagModeExclusive = "exclusive"
+)
+
+// DryRunAnnotation, when set to "true" on an NLB or Listener, makes the operator
+// compute the actions a reconcile would take and record them in
+// Status.PlannedActions without calling any mutating NLB API
+const DryRunAnnotation = "nlboperator.alibabacloud.com/dry-run"
+
+// ResyncPeriodAnnotation overrides the operator's --resync-period for a single NLB
+// or Listener, e.g. "1m" for objects that reference frequently changing resources
+const ResyncPeriodAnnotation = "nlboperator.alibabacloud.com/resync-period"
+
+// Provenance tags link a cloud NLB instance back to the operator and CR managing it.
+// Keys with the ProvenanceTagPrefix are reserved and cannot be set in Spec.Tags
+const (
+	ProvenanceTagPrefix = "nlb-operator/"
+	// ManagedByTagKey holds the operator's --operator-id
+	ManagedByTagKey = ProvenanceTagPrefix + "managed-by"
+	// CRNamespaceTagKey holds the namespace of the NLB CR
+	CRNamespaceTagKey = ProvenanceTagPrefix + "cr-namespace"
+	// CRNameTagKey holds the name of the NLB CR
+	CRNameTagKey = ProvenanceTagPrefix + "cr-name"
+)
+
+// ForceSyncAnnotation triggers an immediate full drift reconcile of an NLB and its
+// Listeners whenever its value changes, e.g. set to a timestamp after console edits.
+// The value is echoed into Status.ObservedForceSync once the reconcile succeeds
+const ForceSyncAnnotation = "nlboperator.alibabacloud.com/force-sync"
+
+// OrphanedLoadBalancerAnnotation records the ID of the cloud NLB instance left
+// behind when the operator force-removed the finalizer after --deletion-timeout
+const OrphanedLoadBalancerAnnotation = "nlboperator.alibabacloud.com/orphaned-load-balancer-id"
+
+// PausedAnnotation, when set to "true" on an NLB, stops the operator from
+// creating, updating or deleting its cloud instance until it is removed
+const PausedAnnotation = "nlboperator.alibabacloud.com/paused"
+
+// PauseDeletionAnnotation, when set to "true" together with PausedAnnotation,
+// also holds back the deletion of the NLB; by default a paused NLB can be deleted
+const PauseDeletionAnnotation = "nlboperator.alibabacloud.com/pause-deletion"
+
 // ZoneMapping defines the zone and vSwitch configuration
 type ZoneMapping struct {
 	// ZoneId is the zone ID
@@ -95,6 +215,11 @@ type ZoneMapping struct {
 	// PrivateIPv4Address is the private IP address
 	// +optional
 	PrivateIPv4Address string `json:"privateIPv4Address,omitempty"`
+
+	// Ipv6Address is the IPv6 address to assign in this zone, only valid for DualStack.
+	// It is only applied when the instance is created.
+	// +optional
+	Ipv6Address string `json:"ipv6Address,omitempty"`
 }
 
 // DeletionProtectionConfig defines the deletion protection configuration
@@ -128,6 +253,44 @@ type Tag struct {
 	Value string `json:"value"`
 }
 
+// CapacityConfig defines the capacity limits of an NLB instance
+type CapacityConfig struct {
+	// Cps is the maximum number of new connections per second of each zone
+	// (virtual IP address); 0 means unlimited. Unset leaves the live value alone
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	Cps *int32 `json:"cps,omitempty"`
+}
+
+// PayTypePostPay is the pay-as-you-go billing method, the only one NLB supports
+const PayTypePostPay = "PostPay"
+
+// BillingConfig defines the billing configuration of an NLB instance
+type BillingConfig struct {
+	// PayType is the billing method
+	// Valid values: PostPay (pay-as-you-go)
+	// +kubebuilder:validation:Enum=PostPay
+	// +kubebuilder:default=PostPay
+	PayType string `json:"payType"`
+}
+
+// MaintenanceWindow is a daily time window. An End before Start spans midnight
+type MaintenanceWindow struct {
+	// Start is the time of day the window opens, in HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End is the time of day the window closes, in HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// TimeZone is the IANA time zone of Start and End, e.g. Asia/Shanghai
+	// +kubebuilder:default=UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // LegacyListenerSpec defines the listener configuration
 // Retained for SDK compatibility - used by NLBPool Operator
 type LegacyListenerSpec struct {
@@ -176,8 +339,24 @@ type LegacyListenerSpec struct {
 	ProxyProtocolEnabled *bool `json:"proxyProtocolEnabled,omitempty"`
 }
 
+// NLBPhase is a summary of the NLB lifecycle derived from its conditions
+type NLBPhase string
+
+const (
+	NLBPending      NLBPhase = "Pending"
+	NLBProvisioning NLBPhase = "Provisioning"
+	NLBActive       NLBPhase = "Active"
+	NLBDeleting     NLBPhase = "Deleting"
+	NLBFailed       NLBPhase = "Failed"
+)
+
 // NLBStatus defines the observed state of NLB
 type NLBStatus struct {
+	// Phase summarizes the lifecycle of the NLB for display; see Conditions for details
+	// Valid values: Pending, Provisioning, Active, Deleting, Failed
+	// +optional
+	Phase NLBPhase `json:"phase,omitempty"`
+
 	// LoadBalancerId is the ID of the NLB instance
 	// +optional
 	LoadBalancerId string `json:"loadBalancerId,omitempty"`
@@ -187,19 +366,211 @@ type NLBStatus struct {
 	DNSName string `json:"dnsName,omitempty"`
 
 	// LoadBalancerStatus is the status of the NLB instance
-	// Valid values: Provisioning, Active, Failed
+	// Valid values: Provisioning, Active, Failed, CreateFailed. CreateFailed means
+	// creation hit a non-retryable error and is retried only after a spec change
 	// +optional
 	LoadBalancerStatus string `json:"loadBalancerStatus,omitempty"`
 
+	// FailureReason is the machine-readable reason of the last failed create, e.g.
+	// QuotaExceeded or InvalidParameter; the human message is in the Error condition.
+	// Cleared once the instance is created
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// FailureCode is the NLB OpenAPI error code of the last failed create, empty
+	// when the failure was detected by the operator before calling the API
+	// +optional
+	FailureCode string `json:"failureCode,omitempty"`
+
+	// FailureRequestId is the RequestId of the last failed create request, for
+	// Alibaba Cloud support
+	// +optional
+	FailureRequestId string `json:"failureRequestId,omitempty"`
+
+	// RegionId is the region where the NLB instance resides
+	// +optional
+	RegionId string `json:"regionId,omitempty"`
+
+	// CreateTime is the time when the NLB instance was created, as reported by the cloud
+	// +optional
+	CreateTime string `json:"createTime,omitempty"`
+
+	// CrossZoneEnabled is whether cross-zone load balancing is enabled on the instance
+	// +optional
+	CrossZoneEnabled *bool `json:"crossZoneEnabled,omitempty"`
+
+	// AddressIpVersion is the IP version of the instance, ipv4 or DualStack
+	// +optional
+	AddressIpVersion string `json:"addressIpVersion,omitempty"`
+
+	// Ipv6AddressType is the network type of the IPv6 address of a DualStack
+	// instance, Internet or Intranet
+	// +optional
+	Ipv6AddressType string `json:"ipv6AddressType,omitempty"`
+
+	// SecurityGroupIds are the security groups the instance is associated with,
+	// sorted, including ones joined outside the operator
+	// +optional
+	SecurityGroupIds []string `json:"securityGroupIds,omitempty"`
+
+	// ObservedGeneration is the generation last fully reconciled by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastReconcileTime is when the operator last fully reconciled the NLB; a value
+	// older than the resync period indicates a stuck reconcile
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// ObservedForceSync is the ForceSyncAnnotation value of the last successful reconcile
+	// +optional
+	ObservedForceSync string `json:"observedForceSync,omitempty"`
+
+	// ManagedTagKeys are the tag keys last applied by the operator, used to
+	// remove tags dropped from Spec.Tags without touching external tags
+	// +optional
+	ManagedTagKeys []string `json:"managedTagKeys,omitempty"`
+
+	// ManagedSecurityGroupIds are the security groups last joined by the operator,
+	// used to leave groups dropped from Spec.SecurityGroupIds
+	// +optional
+	ManagedSecurityGroupIds []string `json:"managedSecurityGroupIds,omitempty"`
+
+	// ManagedBandwidthPackageId is the bandwidth package last attached by the operator,
+	// used to detach it once removed from Spec.BandwidthPackageId
+	// +optional
+	ManagedBandwidthPackageId string `json:"managedBandwidthPackageId,omitempty"`
+
+	// PlannedActions lists the actions the last dry-run reconcile would have taken,
+	// populated only while the dry-run annotation is set
+	// +optional
+	PlannedActions []string `json:"plannedActions,omitempty"`
+
+	// PendingChanges lists the changes deferred until Spec.MaintenanceWindow opens,
+	// refreshed on every reconcile outside the window
+	// +optional
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+
+	// PendingJobs are the async cloud jobs started by the operator and not yet
+	// seen finished. Recorded before waiting on a job, so a reconcile after a
+	// restart awaits them before issuing new operations
+	// +optional
+	PendingJobs []PendingJob `json:"pendingJobs,omitempty"`
+
 	// Eips contains the EIP information for each zone
 	// +optional
 	Eips []EIPInfo `json:"eips,omitempty"`
 
+	// ZoneMappingStatus contains the vSwitch and addresses allocated in each zone
+	// +optional
+	ZoneMappingStatus []ZoneMappingStatus `json:"zoneMappingStatus,omitempty"`
+
+	// ActiveZones lists the zones whose status is Active, i.e. whose addresses
+	// are serving traffic. Zones that are stopped or shifted away are omitted.
+	// +optional
+	ActiveZones []string `json:"activeZones,omitempty"`
+
+	// BackendHealth reports the health of the backend servers behind each listener
+	// of the instance, refreshed on every reconcile
+	// +optional
+	BackendHealth []ListenerBackendHealth `json:"backendHealth,omitempty"`
+
 	// Conditions represent the latest available observations of the NLB's state
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// PendingJob is an async cloud job the operator is waiting for
+type PendingJob struct {
+	// JobId is the ID of the async job
+	JobId string `json:"jobId"`
+
+	// Operation is the API call that started the job, e.g. UpdateLoadBalancerAttribute
+	// +optional
+	Operation string `json:"operation,omitempty"`
+
+	// StartTime is when the operator started waiting for the job
+	// +optional
+	StartTime metav1.Time `json:"startTime,omitempty"`
+}
+
+// ZoneMappingStatus defines the observed vSwitch and addresses of a zone
+type ZoneMappingStatus struct {
+	// ZoneId is the zone ID
+	ZoneId string `json:"zoneId"`
+
+	// VSwitchId is the vSwitch ID
+	// +optional
+	VSwitchId string `json:"vSwitchId,omitempty"`
+
+	// PrivateIPv4Address is the private IPv4 address allocated in the zone
+	// +optional
+	PrivateIPv4Address string `json:"privateIPv4Address,omitempty"`
+
+	// PublicIPv4Address is the public IPv4 address for Internet NLB
+	// +optional
+	PublicIPv4Address string `json:"publicIPv4Address,omitempty"`
+
+	// Ipv6Address is the IPv6 address allocated in the zone for DualStack instances
+	// +optional
+	Ipv6Address string `json:"ipv6Address,omitempty"`
+
+	// AllocationId is the EIP allocation ID for Internet NLB
+	// +optional
+	AllocationId string `json:"allocationId,omitempty"`
+
+	// Status is the zone status reported by the cloud, e.g. Active, Stopped,
+	// Shifted, Starting or Stopping
+	// +optional
+	Status string `json:"status,omitempty"`
+}
+
+// ListenerBackendHealth defines the backend health of one server group of a listener
+type ListenerBackendHealth struct {
+	// ListenerId is the listener ID
+	ListenerId string `json:"listenerId"`
+
+	// ListenerPort is the listening port
+	// +optional
+	ListenerPort int32 `json:"listenerPort,omitempty"`
+
+	// ServerGroupId is the server group ID
+	// +optional
+	ServerGroupId string `json:"serverGroupId,omitempty"`
+
+	// HealthyCount is the number of healthy backend servers
+	HealthyCount int32 `json:"healthyCount"`
+
+	// UnhealthyCount is the number of backend servers in any other state
+	UnhealthyCount int32 `json:"unhealthyCount"`
+
+	// Servers lists the backend servers, abnormal ones first. Truncated for large
+	// server groups; the counts always cover every server
+	// +optional
+	Servers []BackendServerHealth `json:"servers,omitempty"`
+}
+
+// BackendServerHealth defines the health of a backend server
+type BackendServerHealth struct {
+	// ServerId is the ECS instance, ENI or ECI ID, or the IP of an Ip server group
+	ServerId string `json:"serverId"`
+
+	// ServerIp is the IP address of the server
+	// +optional
+	ServerIp string `json:"serverIp,omitempty"`
+
+	// Port is the backend port
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Status is the health status: Healthy, Unhealthy, Initial or Unavailable
+	Status string `json:"status"`
+
+	// Reason is the reason code reported for an abnormal status
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
 // EIPInfo defines the EIP information for a zone
 type EIPInfo struct {
 	// ZoneId is the zone ID
@@ -207,4 +578,12 @@ type EIPInfo struct {
 
 	// IP is the EIP address
 	IP string `json:"ip"`
+
+	// PrivateIPv4Address is the private IPv4 address allocated in the zone
+	// +optional
+	PrivateIPv4Address string `json:"privateIPv4Address,omitempty"`
+
+	// Ipv6Address is the IPv6 address allocated in the zone for DualStack instances
+	// +optional
+	Ipv6Address string `json:"ipv6Address,omitempty"`
 }
@@ -20,7 +20,22 @@ const (
 // ServerGroupFinalizer 用于清理云端 ServerGroup 资源
 const ServerGroupFinalizer = "nlboperator.alibabacloud.com/servergroup-finalizer"
 
+// Scheduler 调度算法取值
+const (
+	// SchedulerWrr 加权轮询, 权重越高的后端被分配的概率越高
+	SchedulerWrr = "Wrr"
+	// SchedulerRr 轮询
+	SchedulerRr = "Rr"
+	// SchedulerSch 按源 IP 一致性哈希, 同一源 IP 调度到同一后端
+	SchedulerSch = "Sch"
+	// SchedulerTch 按四元组 (源 IP、目的 IP、源端口、目的端口) 一致性哈希
+	SchedulerTch = "Tch"
+	// SchedulerQch 按 QUIC Connection ID 一致性哈希, 仅 UDP 服务器组支持
+	SchedulerQch = "Qch"
+)
+
 // ServerGroupSpec defines the desired state of ServerGroup
+// +kubebuilder:validation:XValidation:rule="!has(self.scheduler) || self.scheduler != 'Qch' || self.protocol == 'UDP'",message="scheduler Qch is only supported for UDP server groups"
 type ServerGroupSpec struct {
 	// Region 阿里云区域
 	Region string `json:"region"`
@@ -32,12 +47,27 @@ type ServerGroupSpec struct {
 	ServerGroupType string `json:"serverGroupType"`
 	// Protocol SG级别协议: TCP / UDP / TCPSSL
 	Protocol string `json:"protocol"`
-	// Scheduler 调度算法: Wrr / Rr / Sch / Tch
+	// Scheduler 调度算法: Wrr / Rr / Sch / Tch / Qch, 一致性哈希的哈希字段由算法决定
+	// (Sch 源 IP, Tch 四元组, Qch QUIC Connection ID); 创建后修改会同步到云端,
+	// 不设置时使用云端默认值 (Wrr) 且不做同步
 	// +optional
+	// +kubebuilder:validation:Enum=Wrr;Rr;Sch;Tch;Qch
 	Scheduler string `json:"scheduler,omitempty"`
 	// HealthCheck 健康检查配置
 	// +optional
 	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty"`
+	// ConnectionDrainEnabled 是否开启连接优雅中断; 开启后删除引用该 ServerGroup 的
+	// Listener 前会等待 ConnectionDrainTimeout
+	// +optional
+	ConnectionDrainEnabled bool `json:"connectionDrainEnabled,omitempty"`
+	// ConnectionDrainTimeout 连接优雅中断超时时间(秒)
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=900
+	ConnectionDrainTimeout int32 `json:"connectionDrainTimeout,omitempty"`
+	// PreserveClientIpEnabled 是否保留客户端源 IP; 不设置时使用云端默认值且不做同步
+	// +optional
+	PreserveClientIpEnabled *bool `json:"preserveClientIpEnabled,omitempty"`
 }
 
 // HealthCheckConfig 健康检查配置
@@ -72,6 +102,9 @@ type ServerGroupStatus struct {
 	// Message 附加诊断信息
 	// +optional
 	Message string `json:"message,omitempty"`
+	// Scheduler 云端服务器组当前生效的调度算法
+	// +optional
+	Scheduler string `json:"scheduler,omitempty"`
 }
 
 // +genclient
@@ -80,6 +113,7 @@ type ServerGroupStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="ServerGroupId",type=string,JSONPath=`.status.serverGroupId`
+// +kubebuilder:printcolumn:name="Scheduler",type=string,JSONPath=`.status.scheduler`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:resource:shortName=sg
 
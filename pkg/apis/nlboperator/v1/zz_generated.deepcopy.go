@@ -10,6 +10,71 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalCert) DeepCopyInto(out *AdditionalCert) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalCert.
+func (in *AdditionalCert) DeepCopy() *AdditionalCert {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalCert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BillingConfig) DeepCopyInto(out *BillingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BillingConfig.
+func (in *BillingConfig) DeepCopy() *BillingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BillingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityConfig) DeepCopyInto(out *CapacityConfig) {
+	*out = *in
+	if in.Cps != nil {
+		in, out := &in.Cps, &out.Cps
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityConfig.
+func (in *CapacityConfig) DeepCopy() *CapacityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeletionProtectionConfig) DeepCopyInto(out *DeletionProtectionConfig) {
 	*out = *in
@@ -162,6 +227,26 @@ func (in *NLBSpec) DeepCopyInto(out *NLBSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(CapacityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrossZoneEnabled != nil {
+		in, out := &in.CrossZoneEnabled, &out.CrossZoneEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BillingConfig != nil {
+		in, out := &in.BillingConfig, &out.BillingConfig
+		*out = new(BillingConfig)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
 	if in.DeletionProtection != nil {
 		in, out := &in.DeletionProtection, &out.DeletionProtection
 		*out = new(DeletionProtectionConfig)
@@ -192,11 +277,69 @@ func (in *NLBSpec) DeepCopy() *NLBSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NLBStatus) DeepCopyInto(out *NLBStatus) {
 	*out = *in
+	if in.CrossZoneEnabled != nil {
+		in, out := &in.CrossZoneEnabled, &out.CrossZoneEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecurityGroupIds != nil {
+		in, out := &in.SecurityGroupIds, &out.SecurityGroupIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ManagedTagKeys != nil {
+		in, out := &in.ManagedTagKeys, &out.ManagedTagKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedSecurityGroupIds != nil {
+		in, out := &in.ManagedSecurityGroupIds, &out.ManagedSecurityGroupIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingJobs != nil {
+		in, out := &in.PendingJobs, &out.PendingJobs
+		*out = make([]PendingJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Eips != nil {
 		in, out := &in.Eips, &out.Eips
 		*out = make([]EIPInfo, len(*in))
 		copy(*out, *in)
 	}
+	if in.ZoneMappingStatus != nil {
+		in, out := &in.ZoneMappingStatus, &out.ZoneMappingStatus
+		*out = make([]ZoneMappingStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActiveZones != nil {
+		in, out := &in.ActiveZones, &out.ActiveZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendHealth != nil {
+		in, out := &in.BackendHealth, &out.BackendHealth
+		*out = make([]ListenerBackendHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -216,6 +359,21 @@ func (in *NLBStatus) DeepCopy() *NLBStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocolV2Config) DeepCopyInto(out *ProxyProtocolV2Config) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocolV2Config.
+func (in *ProxyProtocolV2Config) DeepCopy() *ProxyProtocolV2Config {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyProtocolV2Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tag) DeepCopyInto(out *Tag) {
 	*out = *in
@@ -246,6 +404,72 @@ func (in *ZoneMapping) DeepCopy() *ZoneMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneMappingStatus) DeepCopyInto(out *ZoneMappingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneMappingStatus.
+func (in *ZoneMappingStatus) DeepCopy() *ZoneMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingJob) DeepCopyInto(out *PendingJob) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingJob.
+func (in *PendingJob) DeepCopy() *PendingJob {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListenerBackendHealth) DeepCopyInto(out *ListenerBackendHealth) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]BackendServerHealth, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerBackendHealth.
+func (in *ListenerBackendHealth) DeepCopy() *ListenerBackendHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenerBackendHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendServerHealth) DeepCopyInto(out *BackendServerHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendServerHealth.
+func (in *BackendServerHealth) DeepCopy() *BackendServerHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendServerHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheckConfig) DeepCopyInto(out *HealthCheckConfig) {
 	*out = *in
@@ -269,6 +493,11 @@ func (in *ServerGroupSpec) DeepCopyInto(out *ServerGroupSpec) {
 		*out = new(HealthCheckConfig)
 		**out = **in
 	}
+	if in.PreserveClientIpEnabled != nil {
+		in, out := &in.PreserveClientIpEnabled, &out.PreserveClientIpEnabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerGroupSpec.
@@ -358,6 +587,46 @@ func (in *ServerGroupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerSpec) DeepCopyInto(out *ListenerSpec) {
 	*out = *in
+	if in.AdditionalCertificates != nil {
+		in, out := &in.AdditionalCertificates, &out.AdditionalCertificates
+		*out = make([]AdditionalCert, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlpnEnabled != nil {
+		in, out := &in.AlpnEnabled, &out.AlpnEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Cps != nil {
+		in, out := &in.Cps, &out.Cps
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CaEnabled != nil {
+		in, out := &in.CaEnabled, &out.CaEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CaCertificateIds != nil {
+		in, out := &in.CaCertificateIds, &out.CaCertificateIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProxyProtocolEnabled != nil {
+		in, out := &in.ProxyProtocolEnabled, &out.ProxyProtocolEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProxyProtocolV2Config != nil {
+		in, out := &in.ProxyProtocolV2Config, &out.ProxyProtocolV2Config
+		*out = new(ProxyProtocolV2Config)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerSpec.
@@ -373,6 +642,22 @@ func (in *ListenerSpec) DeepCopy() *ListenerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerStatus) DeepCopyInto(out *ListenerStatus) {
 	*out = *in
+	if in.CreateTime != nil {
+		in, out := &in.CreateTime, &out.CreateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerStatus.
@@ -390,8 +675,8 @@ func (in *Listener) DeepCopyInto(out *Listener) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Listener.
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+// maxReportedBackends caps the servers listed per server group in
+// Status.BackendHealth so large server groups do not bloat the NLB object.
+const maxReportedBackends = 20
+
+// refreshBackendHealth reports the backend health of every listener on the
+// instance in Status.BackendHealth. Health is informational, so a failing API
+// call keeps the previous report rather than failing the reconcile.
+func (r *NLBReconciler) refreshBackendHealth(ctx context.Context, nlb *nlbv1.NLB) {
+	log := klog.FromContext(ctx)
+
+	listeners, err := r.NLBClient.ListLoadBalancerListeners(ctx, nlb.Status.LoadBalancerId)
+	if err != nil {
+		log.Error(err, "Failed to list listeners for backend health")
+		return
+	}
+
+	var report []nlbv1.ListenerBackendHealth
+	for _, lsn := range listeners {
+		groups, err := r.NLBClient.GetListenerBackendHealth(ctx, lsn.ListenerId)
+		if err != nil {
+			log.Error(err, "Failed to get backend health", "listenerId", lsn.ListenerId)
+			return
+		}
+		for _, g := range groups {
+			report = append(report, listenerBackendHealth(lsn, g))
+		}
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].ListenerPort != report[j].ListenerPort {
+			return report[i].ListenerPort < report[j].ListenerPort
+		}
+		return report[i].ServerGroupId < report[j].ServerGroupId
+	})
+	nlb.Status.BackendHealth = report
+}
+
+// listenerBackendHealth converts the health of a server group of lsn into its
+// status representation, listing abnormal servers first.
+func listenerBackendHealth(lsn provider.ListenerAttribute, g provider.ServerGroupHealth) nlbv1.ListenerBackendHealth {
+	h := nlbv1.ListenerBackendHealth{
+		ListenerId:    lsn.ListenerId,
+		ListenerPort:  lsn.ListenerPort,
+		ServerGroupId: g.ServerGroupId,
+	}
+
+	servers := append([]provider.BackendServerHealth(nil), g.Servers...)
+	sort.SliceStable(servers, func(i, j int) bool {
+		hi, hj := servers[i].Status == provider.BackendStatusHealthy, servers[j].Status == provider.BackendStatusHealthy
+		if hi != hj {
+			return !hi
+		}
+		return servers[i].ServerId < servers[j].ServerId
+	})
+	for _, s := range servers {
+		if s.Status == provider.BackendStatusHealthy {
+			h.HealthyCount++
+		} else {
+			h.UnhealthyCount++
+		}
+		if len(h.Servers) < maxReportedBackends {
+			h.Servers = append(h.Servers, nlbv1.BackendServerHealth{
+				ServerId: s.ServerId,
+				ServerIp: s.ServerIp,
+				Port:     s.Port,
+				Status:   s.Status,
+				Reason:   s.Reason,
+			})
+		}
+	}
+	return h
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+const (
+	// defaultErrorBackoffBase is the requeue interval after the first failed reconcile.
+	defaultErrorBackoffBase = 5 * time.Second
+	// defaultErrorBackoffMax caps the requeue interval of an object that keeps failing.
+	defaultErrorBackoffMax = 5 * time.Minute
+)
+
+// requeueBackoff tracks consecutive reconcile failures per object and turns them
+// into an exponentially growing requeue interval, so a persistently failing object
+// does not hammer the API while a transient failure is retried quickly.
+type requeueBackoff struct {
+	base, max time.Duration
+
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+func newRequeueBackoff(base, max time.Duration) *requeueBackoff {
+	return &requeueBackoff{
+		base:     base,
+		max:      max,
+		failures: make(map[types.NamespacedName]int),
+	}
+}
+
+// next records a failure of key and returns the interval to wait before retrying.
+func (b *requeueBackoff) next(key types.NamespacedName) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := b.failures[key]
+	b.failures[key] = n + 1
+	d := b.base
+	for i := 0; i < n && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	return d
+}
+
+// attempts returns the number of consecutive failures recorded for key.
+func (b *requeueBackoff) attempts(key types.NamespacedName) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures[key]
+}
+
+// reset forgets the failures of key.
+func (b *requeueBackoff) reset(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, key)
+}
+
+// result converts the outcome of a reconcile of key into the result returned to
+// controller-runtime: a success resets the backoff, a terminal cloud error stops
+// retrying until the object changes, an update conflict is retried right away and
+// any other error is requeued after the next backoff interval.
+func (b *requeueBackoff) result(ctx context.Context, key types.NamespacedName, result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil {
+		b.reset(key)
+		return result, nil
+	}
+
+	log := klog.FromContext(ctx)
+	switch {
+	case provider.IsTerminalError(err):
+		b.reset(key)
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	case errors.IsConflict(err):
+		log.V(1).Info("Object changed during reconcile, retrying", "error", err.Error())
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	d := b.next(key)
+	log.Error(err, "Reconcile failed, backing off", "requeueAfter", d)
+	return ctrl.Result{RequeueAfter: d}, nil
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// ConditionTypeDrifted is True when the last drift reconcile found the cloud
+// instance changed outside the operator, listing the drifted fields.
+const ConditionTypeDrifted = "Drifted"
+
+// driftRecorder collects the spec fields found drifted during one reconcile pass.
+type driftRecorder struct {
+	mu     sync.Mutex
+	fields map[string]bool
+}
+
+type driftRecorderKey struct{}
+
+// withDriftRecorder returns a context carrying a fresh driftRecorder. Only a spec
+// that was already fully reconciled (Status.ObservedGeneration == Generation) can
+// drift; differences after a spec change are a rollout, so no recorder is attached.
+func withDriftRecorder(ctx context.Context, nlb *nlbv1.NLB) (context.Context, *driftRecorder) {
+	if nlb.Status.ObservedGeneration != nlb.Generation {
+		return ctx, nil
+	}
+	rec := &driftRecorder{fields: make(map[string]bool)}
+	return context.WithValue(ctx, driftRecorderKey{}, rec), rec
+}
+
+// recordDrift notes that field of the live instance differs from the spec.
+func recordDrift(ctx context.Context, field string) {
+	rec, _ := ctx.Value(driftRecorderKey{}).(*driftRecorder)
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.fields[field] = true
+}
+
+// recordDriftCorrection records the drift of field like recordDrift and, when it is
+// drift rather than a rollout of a spec change, logs the correction about to be
+// applied and counts it in nlb_drift_corrections_total.
+func recordDriftCorrection(ctx context.Context, field string, live, desired any) {
+	recordDrift(ctx, field)
+	if rec, _ := ctx.Value(driftRecorderKey{}).(*driftRecorder); rec == nil {
+		return
+	}
+	driftCorrectionsTotal.WithLabelValues(field).Inc()
+	klog.FromContext(ctx).Info("Correcting drift of live instance", "field", field, "live", live, "desired", desired)
+}
+
+// Fields returns the sorted drifted fields.
+func (d *driftRecorder) Fields() []string {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fields := make([]string, 0, len(d.fields))
+	for f := range d.fields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// updateDriftCondition sets the Drifted condition from the fields found drifted in
+// this pass. Without a recorder (the spec changed) the condition is left as is.
+func (r *NLBReconciler) updateDriftCondition(nlb *nlbv1.NLB, rec *driftRecorder) {
+	if rec == nil {
+		return
+	}
+	fields := rec.Fields()
+	if len(fields) == 0 {
+		r.updateCondition(nlb, ConditionTypeDrifted, metav1.ConditionFalse, "NoDrift", "Live instance matches spec")
+		return
+	}
+	msg := fmt.Sprintf("Live instance was changed outside the operator: %s", strings.Join(fields, ", "))
+	r.Recorder.Event(nlb, "Warning", "DriftDetected", msg)
+	r.updateCondition(nlb, ConditionTypeDrifted, metav1.ConditionTrue, "DriftDetected", msg)
+}
@@ -0,0 +1,349 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+// isDryRun reports whether obj carries the dry-run annotation.
+func isDryRun(obj metav1.Object) bool {
+	return obj.GetAnnotations()[nlbv1.DryRunAnnotation] == "true"
+}
+
+// handleDryRun records the actions a reconcile of nlb would take in
+// Status.PlannedActions and an event, issuing only read-only NLB API calls.
+func (r *NLBReconciler) handleDryRun(ctx context.Context, nlb *nlbv1.NLB) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+
+	plan, err := r.planNLB(ctx, nlb)
+	if err != nil {
+		r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to compute dry-run plan: %v", err))
+		return ctrl.Result{}, err
+	}
+
+	if !slices.Equal(plan, nlb.Status.PlannedActions) {
+		r.Recorder.Event(nlb, "Normal", "DryRun", dryRunMessage(plan))
+	}
+	nlb.Status.PlannedActions = plan
+	if err := r.updateStatus(ctx, nlb); err != nil {
+		log.Error(err, "Failed to update NLB status")
+		return ctrl.Result{}, err
+	}
+	// Re-plan on the resync period so the reported actions follow cloud-side drift
+	return ctrl.Result{RequeueAfter: r.resyncPeriod(nlb)}, nil
+}
+
+// planNLB mirrors handleCreateOrUpdate and handleDeletion without mutating anything.
+func (r *NLBReconciler) planNLB(ctx context.Context, nlb *nlbv1.NLB) ([]string, error) {
+	lbId := nlb.Status.LoadBalancerId
+
+	if !nlb.ObjectMeta.DeletionTimestamp.IsZero() {
+		if lbId == "" {
+			return []string{"Remove finalizer (NLB was never created)"}, nil
+		}
+		return []string{fmt.Sprintf("DeleteLoadBalancer %s", lbId)}, nil
+	}
+
+	if lbId == "" {
+		if nlb.Spec.LoadBalancerId != "" {
+			lb, err := r.NLBClient.GetLoadBalancer(ctx, nlb.Spec.LoadBalancerId)
+			if err != nil {
+				return nil, err
+			}
+			if lb == nil {
+				return []string{fmt.Sprintf("Fail adoption: NLB %s does not exist", nlb.Spec.LoadBalancerId)}, nil
+			}
+			return []string{fmt.Sprintf("Adopt NLB %s", nlb.Spec.LoadBalancerId)}, nil
+		}
+		if nlb.Spec.AdoptExistingByName && nlb.Spec.LoadBalancerName != "" {
+			existing, err := r.NLBClient.FindLoadBalancerByName(ctx, nlb.Spec.VpcId, nlb.Spec.LoadBalancerName)
+			if err != nil {
+				return nil, err
+			}
+			if existing != "" {
+				return []string{fmt.Sprintf("Adopt NLB %s by name %q", existing, nlb.Spec.LoadBalancerName)}, nil
+			}
+		}
+		if msg := r.validateZones(ctx, nlb); msg != "" {
+			return []string{fmt.Sprintf("Fail creation: %s", msg)}, nil
+		}
+		return []string{fmt.Sprintf("CreateLoadBalancer name=%q vpc=%s zones=%d",
+			nlb.Spec.LoadBalancerName, nlb.Spec.VpcId, len(nlb.Spec.ZoneMappings))}, nil
+	}
+
+	lb, err := r.NLBClient.GetLoadBalancer(ctx, lbId)
+	if err != nil {
+		return nil, err
+	}
+	if lb == nil {
+		return []string{fmt.Sprintf("CreateLoadBalancer name=%q (NLB %s no longer exists)", nlb.Spec.LoadBalancerName, lbId)}, nil
+	}
+
+	if tea.StringValue(lb.LoadBalancerStatus) == provider.LoadBalancerStatusConfiguring {
+		return []string{"Wait for NLB to leave Configuring state"}, nil
+	}
+
+	var plan []string
+	current, err := r.NLBClient.ListTagResources(ctx, lbId)
+	if err != nil {
+		return nil, err
+	}
+	toAdd, toRemove := diffTags(nlb, r.desiredTags(nlb), current, r.provenanceTags(nlb))
+	if len(toAdd) > 0 {
+		keys := make([]string, 0, len(toAdd))
+		for _, t := range toAdd {
+			keys = append(keys, t.Key)
+		}
+		plan = append(plan, fmt.Sprintf("TagResources %v", keys))
+	}
+	if len(toRemove) > 0 {
+		plan = append(plan, fmt.Sprintf("UntagResources %v", toRemove))
+	}
+
+	if tea.StringValue(lb.LoadBalancerStatus) != provider.LoadBalancerStatusActive {
+		return append(plan, fmt.Sprintf("Wait for NLB to become Active (status: %s)", tea.StringValue(lb.LoadBalancerStatus))), nil
+	}
+
+	if dp := nlb.Spec.DeletionProtection; dp != nil {
+		live := lb.DeletionProtectionConfig != nil && tea.BoolValue(lb.DeletionProtectionConfig.Enabled)
+		if live != dp.Enabled {
+			plan = append(plan, fmt.Sprintf("UpdateLoadBalancerProtection deletionProtection %t -> %t", live, dp.Enabled))
+		}
+	}
+	if mp := nlb.Spec.ModificationProtection; mp != nil {
+		var live string
+		if lb.ModificationProtectionConfig != nil {
+			live = tea.StringValue(lb.ModificationProtectionConfig.Status)
+		}
+		if live != mp.Status {
+			plan = append(plan, fmt.Sprintf("UpdateLoadBalancerProtection modificationProtection %q -> %q", live, mp.Status))
+		}
+	}
+	if liveName := tea.StringValue(lb.LoadBalancerName); nlb.Spec.LoadBalancerName != "" && nlb.Spec.LoadBalancerName != liveName {
+		plan = append(plan, fmt.Sprintf("UpdateLoadBalancerAttribute name %q -> %q", liveName, nlb.Spec.LoadBalancerName))
+	}
+	if cps := desiredCps(nlb); cps != nil && *cps != tea.Int32Value(lb.Cps) {
+		plan = append(plan, fmt.Sprintf("UpdateLoadBalancerAttribute cps %d -> %d", tea.Int32Value(lb.Cps), *cps))
+	}
+	if want, live := nlb.Spec.ResourceGroupId, tea.StringValue(lb.ResourceGroupId); want != "" && want != live {
+		plan = append(plan, fmt.Sprintf("MoveResourceGroup %s -> %s", live, want))
+	}
+	if want := nlb.Spec.CrossZoneEnabled; want != nil && *want != tea.BoolValue(lb.CrossZoneEnabled) {
+		plan = append(plan, fmt.Sprintf("UpdateLoadBalancerAttribute crossZoneEnabled %t -> %t", tea.BoolValue(lb.CrossZoneEnabled), *want))
+	}
+	if toDetach, toAttach := diffBandwidthPackage(nlb, lb); toDetach != "" || toAttach != "" {
+		if toDetach != "" {
+			plan = append(plan, fmt.Sprintf("DetachCommonBandwidthPackageFromLoadBalancer %s", toDetach))
+		}
+		if toAttach != "" {
+			plan = append(plan, fmt.Sprintf("AttachCommonBandwidthPackageToLoadBalancer %s", toAttach))
+		}
+	}
+	if want, live := nlb.Spec.Ipv6AddressType, tea.StringValue(lb.Ipv6AddressType); want != "" && nlb.Spec.AddressIpVersion == provider.AddressIpVersionDualStack && want != live {
+		op := "DisableLoadBalancerIpv6Internet"
+		if want == "Internet" {
+			op = "EnableLoadBalancerIpv6Internet"
+		}
+		plan = append(plan, fmt.Sprintf("%s ipv6AddressType %q -> %q", op, live, want))
+	}
+	if live, inSync := liveZoneMappings(nlb, lb); !inSync {
+		plan = append(plan, fmt.Sprintf("UpdateLoadBalancerZones %d -> %d zones", len(live), len(nlb.Spec.ZoneMappings)))
+	}
+	toJoin, toLeave := diffSecurityGroups(nlb, lb)
+	if len(toJoin) > 0 {
+		plan = append(plan, fmt.Sprintf("LoadBalancerJoinSecurityGroup %v", toJoin))
+	}
+	if len(toLeave) > 0 {
+		plan = append(plan, fmt.Sprintf("LoadBalancerLeaveSecurityGroup %v", toLeave))
+	}
+	return plan, nil
+}
+
+// handleDryRun records the actions a reconcile of lsn would take in
+// Status.PlannedActions and an event, issuing only read-only NLB API calls.
+func (r *ListenerReconciler) handleDryRun(ctx context.Context, lsn *nlbv1.Listener) (ctrl.Result, error) {
+	plan, err := r.planListener(ctx, lsn)
+	if err != nil {
+		if provider.IsLocalRateLimited(err) {
+			return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+		r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "DryRunFailed", "Failed to compute dry-run plan: %v", err)
+		return r.requeueOnAPIError(err), nil
+	}
+
+	if !slices.Equal(plan, lsn.Status.PlannedActions) {
+		r.Recorder.Event(lsn, corev1.EventTypeNormal, "DryRun", dryRunMessage(plan))
+	}
+	lsn.Status.PlannedActions = plan
+	if err := r.Status().Update(ctx, lsn); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: resyncPeriodFor(lsn, r.ResyncPeriod)}, nil
+}
+
+// planListener mirrors handleCreateOrSync and handleDeletion without mutating anything.
+func (r *ListenerReconciler) planListener(ctx context.Context, lsn *nlbv1.Listener) ([]string, error) {
+	lsnId := lsn.Status.ListenerId
+
+	if !lsn.ObjectMeta.DeletionTimestamp.IsZero() {
+		if lsnId == "" {
+			return []string{"Remove finalizer (Listener was never created)"}, nil
+		}
+		return []string{fmt.Sprintf("DeleteListener %s", lsnId)}, nil
+	}
+
+	if len(lsn.Spec.AdditionalCertificates) > 0 && lsn.Spec.ListenerProtocol != listenerProtocolTCPSSL {
+		return []string{fmt.Sprintf("Reject spec: additionalCertificates is only supported for TCPSSL listeners, got %s",
+			lsn.Spec.ListenerProtocol)}, nil
+	}
+
+	if msg, err := r.regionMismatch(ctx, lsn); err != nil {
+		return nil, err
+	} else if msg != "" {
+		return []string{fmt.Sprintf("Reject spec: %s", msg)}, nil
+	}
+
+	if lsnId != "" {
+		attr, err := r.NLBClient.GetListenerAttribute(ctx, lsnId)
+		if err != nil {
+			return nil, err
+		}
+		if attr != nil {
+			return r.planRunningListener(ctx, lsn, attr)
+		}
+	}
+
+	nlbId, sgId, ready, msg, err := r.resolveRefs(ctx, lsn)
+	if err != nil {
+		return nil, err
+	}
+	if !ready {
+		return []string{fmt.Sprintf("Wait for dependencies: %s", msg)}, nil
+	}
+	existing, err := r.NLBClient.ListListeners(ctx, nlbId, lsn.Spec.ListenerPort)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" {
+		return []string{fmt.Sprintf("Adopt Listener %s on port %d", existing, lsn.Spec.ListenerPort)}, nil
+	}
+	plan := []string{fmt.Sprintf("CreateListener nlb=%s port=%d protocol=%s serverGroup=%s",
+		nlbId, lsn.Spec.ListenerPort, lsn.Spec.ListenerProtocol, sgId)}
+	if desiredAdminState(lsn) == nlbv1.ListenerAdminStateStopped {
+		plan = append(plan, "StopListener (adminState Stopped)")
+	}
+	if lsn.Spec.ListenerProtocol == listenerProtocolTCPSSL && len(lsn.Spec.AdditionalCertificates) > 0 {
+		toAssociate, _ := diffAdditionalCertificates(lsn, nil)
+		plan = append(plan, fmt.Sprintf("AssociateAdditionalCertificatesWithListener %v", toAssociate))
+	}
+	return plan, nil
+}
+
+// planRunningListener mirrors the update handlers of the Running phase of
+// handleCreateOrSync for the existing cloud listener attr.
+func (r *ListenerReconciler) planRunningListener(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) ([]string, error) {
+	lsnId := lsn.Status.ListenerId
+
+	if attr.ListenerPort != 0 && attr.ListenerPort != lsn.Spec.ListenerPort {
+		owner, err := r.portOwner(ctx, lsn)
+		if err != nil {
+			return nil, err
+		}
+		if owner != nil {
+			return []string{fmt.Sprintf("Keep port %d: port %d is already used by Listener %s",
+				attr.ListenerPort, lsn.Spec.ListenerPort, owner.Name)}, nil
+		}
+		return []string{fmt.Sprintf("DeleteListener %s and recreate it on port %d (port %d -> %d)",
+			lsnId, lsn.Spec.ListenerPort, attr.ListenerPort, lsn.Spec.ListenerPort)}, nil
+	}
+
+	var plan []string
+	switch desiredAdminState(lsn) {
+	case nlbv1.ListenerAdminStateStopped:
+		if attr.ListenerStatus == cloudListenerStatusRunning {
+			plan = append(plan, fmt.Sprintf("StopListener %s", lsnId))
+		}
+	case nlbv1.ListenerAdminStateRunning:
+		if attr.ListenerStatus == cloudListenerStatusStopped {
+			plan = append(plan, fmt.Sprintf("StartListener %s", lsnId))
+		}
+	}
+
+	sg := &nlbv1.ServerGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: lsn.Namespace, Name: lsn.Spec.ServerGroupRef}, sg); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		return append(plan, fmt.Sprintf("Wait for ServerGroup %s to exist", lsn.Spec.ServerGroupRef)), nil
+	}
+	if sgId := sg.Status.ServerGroupId; sgId != "" && sgId != attr.ServerGroupId {
+		switch {
+		case sg.Status.Phase != nlbv1.ServerGroupActive:
+			return append(plan, fmt.Sprintf("Wait for ServerGroup %s to become Active before switching", sg.Name)), nil
+		case !serverGroupProtocolCompatible(lsn.Spec.ListenerProtocol, sg.Spec.Protocol):
+			return append(plan, fmt.Sprintf("Reject serverGroupRef: ServerGroup %s uses protocol %s, which %s listeners cannot forward to",
+				sg.Name, sg.Spec.Protocol, lsn.Spec.ListenerProtocol)), nil
+		}
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute serverGroup %s -> %s", attr.ServerGroupId, sgId))
+	}
+
+	if lsn.Spec.Name != "" && lsn.Spec.Name != attr.ListenerDescription {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute description %q -> %q", attr.ListenerDescription, lsn.Spec.Name))
+	}
+	if lsn.Spec.Cps != nil && *lsn.Spec.Cps != attr.Cps {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute cps %d -> %d", attr.Cps, *lsn.Spec.Cps))
+	}
+	if lsn.Spec.IdleTimeout != nil && *lsn.Spec.IdleTimeout != attr.IdleTimeout {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute idleTimeout %ds -> %ds", attr.IdleTimeout, *lsn.Spec.IdleTimeout))
+	}
+	if desired := desiredProxyProtocol(lsn); !proxyProtocolInSync(desired, attr) {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute proxyProtocol enabled=%t", desired.Enabled))
+	}
+
+	if lsn.Spec.ListenerProtocol != listenerProtocolTCPSSL {
+		return plan, nil
+	}
+	if desired := desiredAlpn(lsn); !alpnInSync(desired, attr) {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute alpn enabled=%t policy=%s", desired.Enabled, desired.Policy))
+	}
+	current, err := r.NLBClient.ListAdditionalCertificates(ctx, lsnId)
+	if err != nil {
+		return nil, err
+	}
+	toAssociate, toDissociate := diffAdditionalCertificates(lsn, current)
+	if len(toAssociate) > 0 {
+		plan = append(plan, fmt.Sprintf("AssociateAdditionalCertificatesWithListener %v", toAssociate))
+	}
+	if len(toDissociate) > 0 {
+		plan = append(plan, fmt.Sprintf("DisassociateAdditionalCertificatesWithListener %v", toDissociate))
+	}
+	if lsn.Spec.SecurityPolicyId != "" && lsn.Spec.SecurityPolicyId != attr.SecurityPolicyId {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute securityPolicy %s -> %s", attr.SecurityPolicyId, lsn.Spec.SecurityPolicyId))
+	}
+	if desired := desiredCA(lsn); !caInSync(desired, attr) {
+		plan = append(plan, fmt.Sprintf("UpdateListenerAttribute ca enabled=%t caCertificates=%v", desired.Enabled, desired.CertificateIds))
+	}
+	return plan, nil
+}
+
+// dryRunMessage renders a plan for an event message.
+func dryRunMessage(plan []string) string {
+	if len(plan) == 0 {
+		return "Dry-run: no changes planned"
+	}
+	return fmt.Sprintf("Dry-run: %d action(s) planned: %s", len(plan), strings.Join(plan, "; "))
+}
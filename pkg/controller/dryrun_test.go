@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+func TestPlanNLB(t *testing.T) {
+	tests := []struct {
+		name string
+		spec func(*nlbv1.NLBSpec)
+		live func(*nlbsdk.GetLoadBalancerAttributeResponseBody)
+		want []string
+	}{
+		{name: "in sync"},
+		{
+			name: "enable IPv6 internet access",
+			spec: func(s *nlbv1.NLBSpec) {
+				s.AddressIpVersion = provider.AddressIpVersionDualStack
+				s.Ipv6AddressType = "Internet"
+			},
+			live: func(lb *nlbsdk.GetLoadBalancerAttributeResponseBody) { lb.Ipv6AddressType = tea.String("Intranet") },
+			want: []string{`EnableLoadBalancerIpv6Internet ipv6AddressType "Intranet" -> "Internet"`},
+		},
+		{
+			name: "disable IPv6 internet access",
+			spec: func(s *nlbv1.NLBSpec) {
+				s.AddressIpVersion = provider.AddressIpVersionDualStack
+				s.Ipv6AddressType = "Intranet"
+			},
+			live: func(lb *nlbsdk.GetLoadBalancerAttributeResponseBody) { lb.Ipv6AddressType = tea.String("Internet") },
+			want: []string{`DisableLoadBalancerIpv6Internet ipv6AddressType "Internet" -> "Intranet"`},
+		},
+		{
+			name: "IPv6 address type of an ipv4 instance",
+			spec: func(s *nlbv1.NLBSpec) { s.Ipv6AddressType = "Internet" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlb := testNLB()
+			nlb.Status.LoadBalancerId = "nlb-1"
+			if tt.spec != nil {
+				tt.spec(&nlb.Spec)
+			}
+			r, nlbClient, nlb := newTestReconciler(t, nlb)
+
+			lb := &nlbsdk.GetLoadBalancerAttributeResponseBody{
+				LoadBalancerId:     tea.String("nlb-1"),
+				LoadBalancerName:   tea.String(nlb.Spec.LoadBalancerName),
+				LoadBalancerStatus: tea.String(provider.LoadBalancerStatusActive),
+			}
+			for _, zm := range nlb.Spec.ZoneMappings {
+				lb.ZoneMappings = append(lb.ZoneMappings, &nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappings{
+					ZoneId:    tea.String(zm.ZoneId),
+					VSwitchId: tea.String(zm.VSwitchId),
+				})
+			}
+			if tt.live != nil {
+				tt.live(lb)
+			}
+			nlbClient.LoadBalancers["nlb-1"] = lb
+			tags := make(map[string]string)
+			for _, tag := range append(r.desiredTags(nlb), r.provenanceTags(nlb)...) {
+				tags[tag.Key] = tag.Value
+			}
+			nlbClient.Tags["nlb-1"] = tags
+
+			plan, err := r.planNLB(context.Background(), nlb)
+			if err != nil {
+				t.Fatalf("planNLB: %v", err)
+			}
+			if !slices.Equal(plan, tt.want) {
+				t.Errorf("got plan %q, want %q", plan, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanRunningListener(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		spec     func(*nlbv1.ListenerSpec)
+		live     func(*provider.ListenerAttribute)
+		want     []string
+	}{
+		{name: "in sync"},
+		{
+			name: "port change",
+			spec: func(s *nlbv1.ListenerSpec) { s.ListenerPort = 8443 },
+			want: []string{"DeleteListener lsn-1 and recreate it on port 8443 (port 443 -> 8443)"},
+		},
+		{
+			name: "server group switch",
+			live: func(a *provider.ListenerAttribute) { a.ServerGroupId = "sgp-old" },
+			want: []string{"UpdateListenerAttribute serverGroup sgp-old -> sgp-1"},
+		},
+		{
+			name: "description",
+			spec: func(s *nlbv1.ListenerSpec) { s.Name = "https" },
+			want: []string{`UpdateListenerAttribute description "" -> "https"`},
+		},
+		{
+			name: "cps",
+			spec: func(s *nlbv1.ListenerSpec) { s.Cps = tea.Int32(100) },
+			want: []string{"UpdateListenerAttribute cps 0 -> 100"},
+		},
+		{
+			name: "idle timeout",
+			spec: func(s *nlbv1.ListenerSpec) { s.IdleTimeout = tea.Int32(60) },
+			live: func(a *provider.ListenerAttribute) { a.IdleTimeout = 900 },
+			want: []string{"UpdateListenerAttribute idleTimeout 900s -> 60s"},
+		},
+		{
+			name: "proxy protocol",
+			spec: func(s *nlbv1.ListenerSpec) { s.ProxyProtocolEnabled = tea.Bool(true) },
+			want: []string{"UpdateListenerAttribute proxyProtocol enabled=true"},
+		},
+		{
+			name: "stop",
+			spec: func(s *nlbv1.ListenerSpec) { s.AdminState = nlbv1.ListenerAdminStateStopped },
+			want: []string{"StopListener lsn-1"},
+		},
+		{
+			name:     "alpn",
+			protocol: listenerProtocolTCPSSL,
+			spec: func(s *nlbv1.ListenerSpec) {
+				s.AlpnEnabled = tea.Bool(true)
+				s.AlpnPolicy = "HTTP2Preferred"
+			},
+			want: []string{"UpdateListenerAttribute alpn enabled=true policy=HTTP2Preferred"},
+		},
+		{
+			name: "alpn of a TCP listener",
+			spec: func(s *nlbv1.ListenerSpec) { s.AlpnEnabled = tea.Bool(true) },
+		},
+		{
+			name:     "additional certificates",
+			protocol: listenerProtocolTCPSSL,
+			spec: func(s *nlbv1.ListenerSpec) {
+				s.AdditionalCertificates = []nlbv1.AdditionalCert{{CertificateId: "cert-1"}}
+			},
+			want: []string{"AssociateAdditionalCertificatesWithListener [cert-1]"},
+		},
+		{
+			name:     "security policy",
+			protocol: listenerProtocolTCPSSL,
+			spec:     func(s *nlbv1.ListenerSpec) { s.SecurityPolicyId = "tls_cipher_policy_1_2" },
+			live:     func(a *provider.ListenerAttribute) { a.SecurityPolicyId = "tls_cipher_policy_1_0" },
+			want:     []string{"UpdateListenerAttribute securityPolicy tls_cipher_policy_1_0 -> tls_cipher_policy_1_2"},
+		},
+		{
+			name:     "CA certificates",
+			protocol: listenerProtocolTCPSSL,
+			spec: func(s *nlbv1.ListenerSpec) {
+				s.CaEnabled = tea.Bool(true)
+				s.CaCertificateIds = []string{"ca-2"}
+			},
+			live: func(a *provider.ListenerAttribute) {
+				a.CaEnabled = true
+				a.CaCertificateIds = []string{"ca-1"}
+			},
+			want: []string{"UpdateListenerAttribute ca enabled=true caCertificates=[ca-2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lsn := testListener()
+			if tt.protocol != "" {
+				lsn.Spec.ListenerProtocol = tt.protocol
+			}
+			if tt.spec != nil {
+				tt.spec(&lsn.Spec)
+			}
+			lsn.Status = nlbv1.ListenerStatus{ListenerId: "lsn-1", Phase: nlbv1.ListenerRunning}
+			r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+
+			attr := provider.ListenerAttribute{
+				ListenerId:       "lsn-1",
+				ListenerStatus:   cloudListenerStatusRunning,
+				ListenerPort:     443,
+				ListenerProtocol: lsn.Spec.ListenerProtocol,
+				LoadBalancerId:   "nlb-1",
+				ServerGroupId:    "sgp-1",
+			}
+			if tt.live != nil {
+				tt.live(&attr)
+			}
+			nlbClient.Listeners["nlb-1"] = []provider.ListenerAttribute{attr}
+
+			plan, err := r.planListener(context.Background(), lsn)
+			if err != nil {
+				t.Fatalf("planListener: %v", err)
+			}
+			if !slices.Equal(plan, tt.want) {
+				t.Errorf("got plan %q, want %q", plan, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+// requestIdSuffix matches the RequestId appended to cloud API errors, which differs
+// on every call and would otherwise defeat deduplication of the same failure.
+var requestIdSuffix = regexp.MustCompile(` \(RequestId: [^)]*\)`)
+
+// throttledRecorder is a record.EventRecorder that drops an event when the same
+// object emitted the same type, reason and message within the interval. A changed
+// message is emitted right away, so only repetitions of a persisting state are
+// suppressed, e.g. the Warning of every requeue of a failing NLB.
+type throttledRecorder struct {
+	inner    record.EventRecorder
+	interval time.Duration
+
+	mu        sync.Mutex
+	last      map[eventKey]lastEvent
+	lastSweep time.Time
+}
+
+type eventKey struct {
+	uid       types.UID
+	eventtype string
+	reason    string
+}
+
+type lastEvent struct {
+	message string
+	at      time.Time
+}
+
+// NewThrottledRecorder wraps inner so an object repeating an event within interval
+// is only recorded once. An interval of zero or less disables throttling.
+func NewThrottledRecorder(inner record.EventRecorder, interval time.Duration) record.EventRecorder {
+	if interval <= 0 {
+		return inner
+	}
+	return &throttledRecorder{
+		inner:    inner,
+		interval: interval,
+		last:     make(map[eventKey]lastEvent),
+	}
+}
+
+func (t *throttledRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if t.allow(object, eventtype, reason, message) {
+		t.inner.Event(object, eventtype, reason, message)
+	}
+}
+
+func (t *throttledRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	t.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (t *throttledRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if t.allow(object, eventtype, reason, message) {
+		t.inner.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// allow reports whether the event should be recorded and remembers it if so.
+func (t *throttledRecorder) allow(object runtime.Object, eventtype, reason, message string) bool {
+	accessor, err := meta.Accessor(object)
+	if err != nil || accessor.GetUID() == "" {
+		return true
+	}
+	key := eventKey{uid: accessor.GetUID(), eventtype: eventtype, reason: reason}
+	message = requestIdSuffix.ReplaceAllString(message, "")
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(now)
+	if prev, ok := t.last[key]; ok && prev.message == message && now.Sub(prev.at) < t.interval {
+		return false
+	}
+	t.last[key] = lastEvent{message: message, at: now}
+	return true
+}
+
+// sweep forgets events older than the interval, at most once per interval, so
+// entries of deleted objects do not accumulate. Callers must hold t.mu.
+func (t *throttledRecorder) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < t.interval {
+		return
+	}
+	for key, ev := range t.last {
+		if now.Sub(ev.at) >= t.interval {
+			delete(t.last, key)
+		}
+	}
+	t.lastSweep = now
+}
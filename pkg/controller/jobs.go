@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+// pendingJobRetry is how long a reconcile waits before checking again on async
+// jobs left in Status.PendingJobs by a previous run of the operator.
+const pendingJobRetry = 5 * time.Second
+
+// jobObserver records the async jobs started while reconciling nlb in
+// Status.PendingJobs. The status is persisted as soon as a job starts, so a
+// restart during the wait leaves a trace for awaitPendingJobs. Jobs may be
+// waited for concurrently, e.g. by deleteListeners, so nlb is only touched with
+// mu held.
+func (r *NLBReconciler) jobObserver(ctx context.Context, nlb *nlbv1.NLB) provider.JobObserver {
+	log := klog.FromContext(ctx)
+	var mu sync.Mutex
+	persist := func() {
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			log.Error(err, "Failed to record pending jobs in NLB status")
+		}
+	}
+	return provider.JobObserver{
+		Started: func(jobId, operation string) {
+			mu.Lock()
+			defer mu.Unlock()
+			nlb.Status.PendingJobs = append(nlb.Status.PendingJobs, nlbv1.PendingJob{
+				JobId:     jobId,
+				Operation: operation,
+				StartTime: metav1.Now(),
+			})
+			persist()
+		},
+		Finished: func(jobId string) {
+			mu.Lock()
+			defer mu.Unlock()
+			for i, job := range nlb.Status.PendingJobs {
+				if job.JobId == jobId {
+					nlb.Status.PendingJobs = append(nlb.Status.PendingJobs[:i], nlb.Status.PendingJobs[i+1:]...)
+					persist()
+					return
+				}
+			}
+		},
+	}
+}
+
+// awaitPendingJobs checks on the jobs in Status.PendingJobs, dropping the ones that
+// finished. It returns done false with the result to return while any of them is
+// still running, so no new operation conflicts with it.
+func (r *NLBReconciler) awaitPendingJobs(ctx context.Context, nlb *nlbv1.NLB) (result ctrl.Result, done bool, err error) {
+	log := klog.FromContext(ctx)
+
+	var running []nlbv1.PendingJob
+	for _, job := range nlb.Status.PendingJobs {
+		status, err := r.NLBClient.GetJobStatus(ctx, job.JobId)
+		if err != nil && !provider.IsNotFoundError(err) {
+			return ctrl.Result{}, false, err
+		}
+		switch {
+		case err != nil:
+			log.Info("Pending job no longer exists, dropping it", "jobId", job.JobId, "operation", job.Operation)
+		case status == provider.JobStatusFailed:
+			r.Recorder.Event(nlb, "Warning", "JobFailed",
+				fmt.Sprintf("Async job %s of %s failed", job.JobId, job.Operation))
+		case status == provider.JobStatusSucceeded:
+			log.V(1).Info("Pending job succeeded", "jobId", job.JobId, "operation", job.Operation)
+		default:
+			running = append(running, job)
+		}
+	}
+	if len(running) == len(nlb.Status.PendingJobs) {
+		if len(running) == 0 {
+			return ctrl.Result{}, true, nil
+		}
+		log.Info("Waiting for pending jobs before issuing new operations", "jobs", len(running))
+		return ctrl.Result{RequeueAfter: pendingJobRetry}, false, nil
+	}
+
+	nlb.Status.PendingJobs = running
+	if err := r.updateStatus(ctx, nlb); err != nil {
+		log.Error(err, "Failed to update NLB status")
+		return ctrl.Result{}, false, err
+	}
+	if len(running) > 0 {
+		return ctrl.Result{RequeueAfter: pendingJobRetry}, false, nil
+	}
+	return ctrl.Result{}, true, nil
+}
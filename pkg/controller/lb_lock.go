@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// loadBalancerLockRetry is how long a reconcile waits before retrying when another
+// reconcile holds the lock of the same load balancer.
+const loadBalancerLockRetry = 2 * time.Second
+
+// loadBalancerLocks serializes mutations of one cloud NLB instance across CRs and
+// controllers. Changes applied concurrently to the same instance are rejected by
+// the API while it is Configuring, so a reconcile that finds the instance locked
+// requeues instead of blocking a worker for the length of the other's async job.
+var loadBalancerLocks = newKeyedLock()
+
+// keyedLock is a set of non-blocking locks keyed by ID, each remembering its holder.
+type keyedLock struct {
+	mu      sync.Mutex
+	holders map[string]string
+}
+
+func newKeyedLock() *keyedLock {
+	return &keyedLock{holders: make(map[string]string)}
+}
+
+// tryLock acquires the lock of key for holder. It returns the unlock function and
+// true on success, or the current holder and false when the lock is taken.
+func (l *keyedLock) tryLock(key, holder string) (unlock func(), current string, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current, held := l.holders[key]; held {
+		return nil, current, false
+	}
+	l.holders[key] = holder
+	return func() {
+		l.mu.Lock()
+		delete(l.holders, key)
+		l.mu.Unlock()
+	}, holder, true
+}
@@ -7,14 +7,22 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
 	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
@@ -25,7 +33,18 @@ const (
 	listenerRequeueThrottling = 60 * time.Second
 	listenerRequeueError      = 5 * time.Second
 
+	// listenerListTimeout bounds how long a provisioned listener waits in Creating,
+	// or a create rejected as AlreadyExists waits to adopt, for ListListeners to
+	// return it, measured from Status.CreateTime
+	listenerListTimeout = 2 * time.Minute
+
 	cloudListenerStatusRunning = "Running"
+	cloudListenerStatusStopped = "Stopped"
+	listenerProtocolTCPSSL     = "TCPSSL"
+
+	// ConditionTypeDraining is set on a deleting Listener while it waits for the
+	// referenced ServerGroup's connection drain timeout
+	ConditionTypeDraining = "Draining"
 )
 
 // ListenerReconciler reconciles a Listener CR with its cloud counterpart.
@@ -33,8 +52,16 @@ type ListenerReconciler struct {
 	client.Client
 	Scheme                  *runtime.Scheme
 	Recorder                record.EventRecorder
-	NLBClient               *provider.NLBClient
+	NLBClient               provider.ListenerClientInterface
 	MaxConcurrentReconciles int
+
+	// Clients, when set, selects the NLBClient for the region of each resource;
+	// NLBClient is then only used for resources in the default region.
+	Clients *provider.ClientPool
+
+	// ResyncPeriod is how long to wait before re-verifying a Running listener.
+	// Defaults to defaultResyncPeriod when zero.
+	ResyncPeriod time.Duration
 }
 
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=listeners,verbs=get;list;watch;create;update;patch;delete
@@ -43,10 +70,25 @@ type ListenerReconciler struct {
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=nlbs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=servergroups,verbs=get;list;watch
 
+// forRegion returns a shallow copy of r whose NLBClient targets region, or r
+// itself when no client pool is configured.
+func (r *ListenerReconciler) forRegion(region string) (*ListenerReconciler, error) {
+	client, err := regionalClient(r.Clients, region)
+	if client == nil {
+		return r, err
+	}
+	regional := *r
+	regional.NLBClient = client
+	return &regional, nil
+}
+
 // Reconcile handles Listener lifecycle.
 func (r *ListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := klog.FromContext(ctx).WithValues("listener", req.NamespacedName)
 
+	ctx, apiCalls := provider.WithAPICallCounter(ctx)
+	defer observeAPICalls(log, "listener", apiCalls)
+
 	lsn := &nlbv1.Listener{}
 	if err := r.Get(ctx, req.NamespacedName, lsn); err != nil {
 		if errors.IsNotFound(err) {
@@ -56,6 +98,17 @@ func (r *ListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	r, err := r.forRegion(lsn.Spec.Region)
+	if err != nil {
+		r.Recorder.Event(lsn, corev1.EventTypeWarning, "RegionClientFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if isDryRun(lsn) {
+		return r.handleDryRun(ctx, lsn)
+	}
+	lsn.Status.PlannedActions = nil
+
 	if !lsn.ObjectMeta.DeletionTimestamp.IsZero() {
 		return r.handleDeletion(ctx, lsn)
 	}
@@ -76,6 +129,33 @@ func (r *ListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.Listener) (ctrl.Result, error) {
 	log := klog.FromContext(ctx)
 
+	// SNI certificates only exist on TCPSSL listeners; the CRD rejects this too,
+	// but objects admitted before that rule existed must not reach the cloud API.
+	if len(lsn.Spec.AdditionalCertificates) > 0 && lsn.Spec.ListenerProtocol != listenerProtocolTCPSSL {
+		msg := fmt.Sprintf("additionalCertificates is only supported for TCPSSL listeners, got %s", lsn.Spec.ListenerProtocol)
+		r.Recorder.Event(lsn, corev1.EventTypeWarning, "InvalidSpec", msg)
+		setListenerReady(lsn, metav1.ConditionFalse, "InvalidSpec", msg)
+		if err := r.Status().Update(ctx, lsn); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// A listener is created in the region of its own client, which must be the
+	// region of the NLB instance and server group it attaches to.
+	if msg, err := r.regionMismatch(ctx, lsn); err != nil {
+		return ctrl.Result{}, err
+	} else if msg != "" {
+		if lsn.Status.Reason != ReasonRegionMismatch || lsn.Status.Message != msg {
+			r.Recorder.Event(lsn, corev1.EventTypeWarning, ReasonRegionMismatch, msg)
+		}
+		setListenerReady(lsn, metav1.ConditionFalse, ReasonRegionMismatch, msg)
+		if err := r.Status().Update(ctx, lsn); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	switch lsn.Status.Phase {
 	case "", nlbv1.ListenerPending:
 		// Validate prerequisites: NLB CR Active and ServerGroup CR Active.
@@ -86,11 +166,31 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 		if !ready {
 			r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "WaitingForDependencies", msg)
 			lsn.Status.Phase = nlbv1.ListenerPending
-			lsn.Status.Message = msg
+			setListenerReady(lsn, metav1.ConditionFalse, "WaitingForDependencies", msg)
 			_ = r.Status().Update(ctx, lsn)
 			return ctrl.Result{RequeueAfter: listenerRequeueShort}, nil
 		}
 
+		// Two Listener CRs on the same NLB port would otherwise both bind to one cloud
+		// listener through the AlreadyExists adopt fallback below.
+		if lsn.Status.ListenerId == "" {
+			owner, err := r.portOwner(ctx, lsn)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if owner != nil {
+				msg := fmt.Sprintf("port %d of NLB %s is already used by Listener %s",
+					lsn.Spec.ListenerPort, lsn.Spec.LoadBalancerRef, owner.Name)
+				r.Recorder.Event(lsn, corev1.EventTypeWarning, "PortConflict", msg)
+				lsn.Status.Phase = nlbv1.ListenerPending
+				setListenerReady(lsn, metav1.ConditionFalse, "PortConflict", msg)
+				if err := r.Status().Update(ctx, lsn); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: resyncPeriodFor(lsn, r.ResyncPeriod)}, nil
+			}
+		}
+
 		// ID short-circuit: if ListenerId already known from a previous Create,
 		// skip ListListeners and Create — go straight to GetListenerAttribute.
 		if lsn.Status.ListenerId != "" {
@@ -102,14 +202,16 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 				// Cloud listener disappeared (GetListenerAttribute returns nil for NotFound).
 				log.Info("Cloud listener disappeared, will recreate", "listenerId", lsn.Status.ListenerId)
 				lsn.Status.ListenerId = ""
-				lsn.Status.Message = "Cloud listener disappeared, will recreate"
+				setListenerReady(lsn, metav1.ConditionFalse, "ListenerDisappeared", "Cloud listener disappeared, will recreate")
 				_ = r.Status().Update(ctx, lsn)
 				return ctrl.Result{Requeue: true}, nil
 			}
 			// Found on cloud — transition based on status.
-			if attr.ListenerStatus == cloudListenerStatusRunning {
+			if listenerProvisioned(attr.ListenerStatus) {
 				lsn.Status.Phase = nlbv1.ListenerRunning
-				lsn.Status.Message = "Listener is running"
+				lsn.Status.CreateTime = nil
+				lsn.Status.Status = attr.ListenerStatus
+				setListenerReady(lsn, metav1.ConditionTrue, "Running", "Listener is running")
 				if err := r.Status().Update(ctx, lsn); err != nil {
 					return ctrl.Result{}, err
 				}
@@ -119,18 +221,27 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 			}
 			// Still creating on cloud side.
 			lsn.Status.Phase = nlbv1.ListenerCreating
-			lsn.Status.Message = "Listener is being created"
+			setListenerReady(lsn, metav1.ConditionFalse, "Creating", "Listener is being created")
 			if err := r.Status().Update(ctx, lsn); err != nil {
 				return ctrl.Result{}, err
 			}
 			return ctrl.Result{RequeueAfter: listenerRequeueShort}, nil
 		}
 
+		// Creating a listener puts the NLB into Configuring, so it must not overlap
+		// with operations of the NLB reconciler on the same instance.
+		unlock, holder, ok := loadBalancerLocks.tryLock(nlbId, "Listener "+client.ObjectKeyFromObject(lsn).String())
+		if !ok {
+			log.V(1).Info("Load balancer is locked by another reconcile, retrying", "nlbId", nlbId, "holder", holder)
+			return ctrl.Result{RequeueAfter: loadBalancerLockRetry}, nil
+		}
+		defer unlock()
+
 		// Optimistic create: directly call CreateNLBListener without prior ListListeners.
 		log.Info("Creating cloud Listener (optimistic)", "nlbId", nlbId, "port", lsn.Spec.ListenerPort,
 			"protocol", lsn.Spec.ListenerProtocol)
 		newId, err := r.NLBClient.CreateNLBListener(ctx, nlbId, sgId,
-			lsn.Spec.ListenerPort, lsn.Spec.ListenerProtocol)
+			lsn.Spec.ListenerPort, lsn.Spec.ListenerProtocol, listenerOptions(lsn))
 		if err != nil {
 			// Local rate limit: requeue quickly without cloud call.
 			if provider.IsLocalRateLimited(err) {
@@ -144,32 +255,57 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 				if listErr != nil {
 					return r.requeueOnAPIError(listErr), nil
 				}
-				if existingId != "" {
-					log.Info("Adopted existing cloud Listener after AlreadyExists error", "listenerId", existingId)
-					lsn.Status.ListenerId = existingId
-					lsn.Status.Phase = nlbv1.ListenerRunning
-					lsn.Status.Message = "Adopted existing cloud Listener"
+				if existingId == "" {
+					// ListListeners is eventually consistent with CreateListener, look
+					// again on the next pass rather than creating it once more.
+					if lsn.Status.CreateTime == nil {
+						now := metav1.Now()
+						lsn.Status.CreateTime = &now
+						setListenerReady(lsn, metav1.ConditionFalse, "AlreadyExists", "Listener already exists on cloud, waiting for ListListeners to adopt it")
+						if err := r.Status().Update(ctx, lsn); err != nil {
+							return ctrl.Result{}, err
+						}
+					}
+					if time.Since(lsn.Status.CreateTime.Time) < listenerListTimeout {
+						log.Info("Existing cloud Listener not listed yet, retrying adopt", "nlbId", nlbId, "port", lsn.Spec.ListenerPort)
+						return ctrl.Result{RequeueAfter: listenerRequeueError}, nil
+					}
+					r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "NotListed",
+						"Listener on port %d already exists on cloud but ListListeners has not returned it after %s", lsn.Spec.ListenerPort, listenerListTimeout)
+					setListenerReady(lsn, metav1.ConditionFalse, "NotListed",
+						fmt.Sprintf("listener on port %d already exists on cloud but is not listed after %s", lsn.Spec.ListenerPort, listenerListTimeout))
 					if err := r.Status().Update(ctx, lsn); err != nil {
 						return ctrl.Result{}, err
 					}
-					r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Adopted",
-						"Adopted existing cloud Listener %s", existingId)
-					return ctrl.Result{}, nil
+					return ctrl.Result{RequeueAfter: listenerRequeueShort}, nil
+				}
+				log.Info("Adopted existing cloud Listener after AlreadyExists error", "listenerId", existingId)
+				lsn.Status.ListenerId = existingId
+				lsn.Status.CreateTime = nil
+				lsn.Status.ListenerPort = lsn.Spec.ListenerPort
+				lsn.Status.Phase = nlbv1.ListenerRunning
+				setListenerReady(lsn, metav1.ConditionTrue, "Adopted", "Adopted existing cloud Listener")
+				if err := r.Status().Update(ctx, lsn); err != nil {
+					return ctrl.Result{}, err
 				}
+				r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Adopted",
+					"Adopted existing cloud Listener %s", existingId)
+				return ctrl.Result{}, nil
 			}
 			r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "CreateFailed",
 				"Failed to create Listener: %v", err)
 			lsn.Status.Phase = nlbv1.ListenerPending
-			lsn.Status.Message = fmt.Sprintf("create failed: %v", err)
+			setListenerReady(lsn, metav1.ConditionFalse, "CreateFailed", fmt.Sprintf("create failed: %v", err))
 			_ = r.Status().Update(ctx, lsn)
 			return r.requeueOnAPIError(err), nil
 		}
 
-		// Create succeeded — record ID and transition to Creating.
-		lsn.Status.ListenerId = newId
-		lsn.Status.Phase = nlbv1.ListenerCreating
-		lsn.Status.Message = "Listener creation submitted"
-		if err := r.Status().Update(ctx, lsn); err != nil {
+		// Create succeeded — record ID and transition to Creating. The Creating phase
+		// waits for the listener to show up in ListListeners.
+		if err := r.recordCreatedListener(ctx, lsn, newId); err != nil {
+			log.Error(err, "Failed to record created Listener in status", "listenerId", newId)
+			r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "StatusUpdateFailed",
+				"Created cloud Listener %s but failed to record it in status, it will be adopted on retry: %v", newId, err)
 			return ctrl.Result{}, err
 		}
 		r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Creating",
@@ -198,15 +334,43 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 				"listenerId", lsn.Status.ListenerId)
 			lsn.Status.ListenerId = ""
 			lsn.Status.Phase = nlbv1.ListenerPending
-			lsn.Status.Message = "Cloud Listener disappeared, will recreate"
+			setListenerReady(lsn, metav1.ConditionFalse, "ListenerDisappeared", "Cloud Listener disappeared, will recreate")
 			if err := r.Status().Update(ctx, lsn); err != nil {
 				return ctrl.Result{}, err
 			}
 			return ctrl.Result{Requeue: true}, nil
 		}
-		if attr.ListenerStatus == cloudListenerStatusRunning {
+		if listenerProvisioned(attr.ListenerStatus) {
+			// ListListeners lags behind CreateListener; leaving Creating before the new
+			// listener is listed would let a later adopt lookup miss it and create a
+			// duplicate.
+			listedId, err := r.NLBClient.ListListeners(ctx, attr.LoadBalancerId, attr.ListenerPort)
+			if err != nil {
+				return r.requeueOnAPIError(err), nil
+			}
+			if listedId != lsn.Status.ListenerId {
+				if lsn.Status.CreateTime == nil {
+					// Created before CreateTime was recorded, start the wait now
+					now := metav1.Now()
+					lsn.Status.CreateTime = &now
+					if err := r.Status().Update(ctx, lsn); err != nil {
+						return ctrl.Result{}, err
+					}
+				}
+				if time.Since(lsn.Status.CreateTime.Time) < listenerListTimeout {
+					log.V(2).Info("Listener not yet listed", "listenerId", lsn.Status.ListenerId)
+					return ctrl.Result{RequeueAfter: listenerRequeueError}, nil
+				}
+				// The ID in status keeps later passes from creating the listener again
+				log.Info("Listener still not listed, treating it as created", "listenerId", lsn.Status.ListenerId,
+					"waited", listenerListTimeout)
+				r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "NotListed",
+					"Listener %s is provisioned but ListListeners has not returned it after %s", lsn.Status.ListenerId, listenerListTimeout)
+			}
 			lsn.Status.Phase = nlbv1.ListenerRunning
-			lsn.Status.Message = "Listener is running"
+			lsn.Status.CreateTime = nil
+			lsn.Status.Status = attr.ListenerStatus
+			setListenerReady(lsn, metav1.ConditionTrue, "Running", "Listener is running")
 			if err := r.Status().Update(ctx, lsn); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -223,8 +387,115 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 			_ = r.Status().Update(ctx, lsn)
 			return ctrl.Result{Requeue: true}, nil
 		}
-		// Reconcile complete: no further requeue, no health check.
-		return ctrl.Result{}, nil
+		// Verify the cached listener ID still exists on the cloud so an external
+		// deletion self-heals instead of leaving a phantom Running phase.
+		attr, err := r.NLBClient.GetListenerAttribute(ctx, lsn.Status.ListenerId)
+		if err != nil {
+			if provider.IsLocalRateLimited(err) {
+				return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+			}
+			return r.requeueOnAPIError(err), nil
+		}
+		if attr == nil {
+			log.Info("Cloud Listener disappeared while Running, resetting to Pending to recreate",
+				"listenerId", lsn.Status.ListenerId)
+			r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "ListenerDisappeared",
+				"Cloud Listener %s no longer exists, will recreate", lsn.Status.ListenerId)
+			lsn.Status.ListenerId = ""
+			lsn.Status.Phase = nlbv1.ListenerPending
+			setListenerReady(lsn, metav1.ConditionFalse, "ListenerDisappeared", "Cloud Listener disappeared, will recreate")
+			if err := r.Status().Update(ctx, lsn); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+		// A listener of an instance the NLB reconciler has since replaced still
+		// answers GetListenerAttribute until the old instance is gone, but is
+		// missing from the instance the NLB now points at.
+		currentId, err := r.currentLoadBalancerId(ctx, lsn)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if currentId != "" && attr.LoadBalancerId != "" && currentId != attr.LoadBalancerId {
+			log.Info("Cloud Listener belongs to a replaced NLB instance, resetting to Pending to recreate",
+				"listenerId", lsn.Status.ListenerId, "listenerNlbId", attr.LoadBalancerId, "nlbId", currentId)
+			r.Recorder.Eventf(lsn, corev1.EventTypeWarning, "ListenerDisappeared",
+				"Cloud Listener %s belongs to replaced NLB instance %s, will recreate on %s",
+				lsn.Status.ListenerId, attr.LoadBalancerId, currentId)
+			lsn.Status.ListenerId = ""
+			lsn.Status.Phase = nlbv1.ListenerPending
+			setListenerReady(lsn, metav1.ConditionFalse, "ListenerDisappeared",
+				"Cloud Listener belongs to a replaced NLB instance, will recreate")
+			if err := r.Status().Update(ctx, lsn); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+		// Every update below puts the NLB into Configuring, so none of them may
+		// overlap with operations of other reconciles on the same instance.
+		lbId := attr.LoadBalancerId
+		if lbId == "" {
+			lbId = currentId
+		}
+		if lbId != "" {
+			unlock, holder, ok := loadBalancerLocks.tryLock(lbId, "Listener "+client.ObjectKeyFromObject(lsn).String())
+			if !ok {
+				log.V(1).Info("Load balancer is locked by another reconcile, retrying", "nlbId", lbId, "holder", holder)
+				return ctrl.Result{RequeueAfter: loadBalancerLockRetry}, nil
+			}
+			defer unlock()
+		}
+		if result, waiting, err := r.reconcilePort(ctx, lsn, attr); waiting || err != nil {
+			return result, err
+		}
+		if result, waiting, err := r.reconcileAdminState(ctx, lsn, attr); waiting || err != nil {
+			return result, err
+		}
+		if result, waiting, err := r.reconcileServerGroup(ctx, lsn, attr); waiting || err != nil {
+			return result, err
+		}
+		if err := r.reconcileDescription(ctx, lsn, attr); err != nil {
+			return r.updateFailed(ctx, lsn, "NameUpdateFailed", err,
+				"Failed to update description of Listener %s: %v", lsn.Status.ListenerId, err)
+		}
+		if err := r.reconcileCps(ctx, lsn, attr); err != nil {
+			return r.updateFailed(ctx, lsn, "CpsUpdateFailed", err,
+				"Failed to update CPS limit of Listener %s: %v", lsn.Status.ListenerId, err)
+		}
+		if err := r.reconcileIdleTimeout(ctx, lsn, attr); err != nil {
+			return r.updateFailed(ctx, lsn, "IdleTimeoutUpdateFailed", err,
+				"Failed to update idle timeout of Listener %s: %v", lsn.Status.ListenerId, err)
+		}
+		if err := r.reconcileProxyProtocol(ctx, lsn, attr); err != nil {
+			return r.updateFailed(ctx, lsn, "ProxyProtocolUpdateFailed", err,
+				"Failed to update proxy protocol of Listener %s: %v", lsn.Status.ListenerId, err)
+		}
+		if lsn.Spec.ListenerProtocol == listenerProtocolTCPSSL {
+			if err := r.reconcileAlpn(ctx, lsn, attr); err != nil {
+				return r.updateFailed(ctx, lsn, "AlpnUpdateFailed", err,
+					"Failed to update ALPN of Listener %s: %v", lsn.Status.ListenerId, err)
+			}
+			if err := r.reconcileAdditionalCertificates(ctx, lsn); err != nil {
+				return r.updateFailed(ctx, lsn, "CertificatesUpdateFailed", err,
+					"Failed to update additional certificates of Listener %s: %v", lsn.Status.ListenerId, err)
+			}
+			if err := r.reconcileSecurityPolicy(ctx, lsn, attr); err != nil {
+				return r.updateFailed(ctx, lsn, "SecurityPolicyUpdateFailed", err,
+					"Failed to update TLS security policy of Listener %s: %v", lsn.Status.ListenerId, err)
+			}
+			if err := r.reconcileCA(ctx, lsn, attr); err != nil {
+				return r.updateFailed(ctx, lsn, "CAUpdateFailed", err,
+					"Failed to update mutual TLS of Listener %s: %v", lsn.Status.ListenerId, err)
+			}
+		}
+		// Clear a failure reported by an earlier pass now that every update applied
+		if !meta.IsStatusConditionTrue(lsn.Status.Conditions, ConditionTypeReady) {
+			setListenerReady(lsn, metav1.ConditionTrue, "Running", "Listener is running")
+			if err := r.Status().Update(ctx, lsn); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: resyncPeriodFor(lsn, r.ResyncPeriod)}, nil
 
 	default:
 		log.Info("Resetting Listener to Pending from unknown phase", "phase", lsn.Status.Phase)
@@ -236,6 +507,331 @@ func (r *ListenerReconciler) handleCreateOrSync(ctx context.Context, lsn *nlbv1.
 	}
 }
 
+// recordCreatedListener persists the ID of a just created cloud listener,
+// retrying on conflicts with a fresh copy of lsn. The ID must not be lost to a
+// concurrent write of the CR, or the next reconcile would create the listener
+// again instead of tracking it.
+func (r *ListenerReconciler) recordCreatedListener(ctx context.Context, lsn *nlbv1.Listener, listenerId string) error {
+	now := metav1.Now()
+	apply := func(l *nlbv1.Listener) {
+		l.Status.ListenerId = listenerId
+		l.Status.CreateTime = &now
+		l.Status.ListenerPort = l.Spec.ListenerPort
+		l.Status.Phase = nlbv1.ListenerCreating
+		setListenerReady(l, metav1.ConditionFalse, "Creating", "Listener creation submitted")
+	}
+	apply(lsn)
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			latest := &nlbv1.Listener{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(lsn), latest); err != nil {
+				return err
+			}
+			apply(latest)
+			latest.DeepCopyInto(lsn)
+		}
+		first = false
+		return r.Status().Update(ctx, lsn)
+	})
+}
+
+// reconcilePort handles an edit of Spec.ListenerPort. The NLB API cannot change
+// the port of a listener, so the cloud listener is deleted and the Listener goes
+// back to Pending to be created on the new port; the CR, and with it Spec.Name,
+// keeps its identity. The caller holds the lock of the load balancer. waiting
+// reports whether the caller must return result/err.
+func (r *ListenerReconciler) reconcilePort(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) (result ctrl.Result, waiting bool, err error) {
+	log := klog.FromContext(ctx)
+
+	if attr.ListenerPort == 0 || attr.ListenerPort == lsn.Spec.ListenerPort {
+		if lsn.Status.ListenerPort != attr.ListenerPort {
+			lsn.Status.ListenerPort = attr.ListenerPort
+			if err := r.Status().Update(ctx, lsn); err != nil {
+				return ctrl.Result{}, true, err
+			}
+		}
+		return ctrl.Result{}, false, nil
+	}
+
+	// Keep serving on the old port until the new one is free
+	owner, err := r.portOwner(ctx, lsn)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+	if owner != nil {
+		msg := fmt.Sprintf("cannot move to port %d of NLB %s, it is already used by Listener %s; still listening on port %d",
+			lsn.Spec.ListenerPort, lsn.Spec.LoadBalancerRef, owner.Name, attr.ListenerPort)
+		if lsn.Status.Message != msg {
+			r.Recorder.Event(lsn, corev1.EventTypeWarning, "PortConflict", msg)
+		}
+		setListenerReady(lsn, metav1.ConditionFalse, "PortConflict", msg)
+		if err := r.Status().Update(ctx, lsn); err != nil {
+			return ctrl.Result{}, true, err
+		}
+		return ctrl.Result{RequeueAfter: resyncPeriodFor(lsn, r.ResyncPeriod)}, true, nil
+	}
+
+	log.Info("Recreating Listener on new port", "listenerId", lsn.Status.ListenerId,
+		"from", attr.ListenerPort, "to", lsn.Spec.ListenerPort)
+	if err := r.NLBClient.DeleteNLBListener(ctx, lsn.Status.ListenerId); err != nil {
+		result, err := r.updateFailed(ctx, lsn, "DeleteFailed", err,
+			"Failed to delete Listener %s to change its port: %v", lsn.Status.ListenerId, err)
+		return result, true, err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "PortChanged",
+		"Deleted Listener %s on port %d, recreating it on port %d", lsn.Status.ListenerId, attr.ListenerPort, lsn.Spec.ListenerPort)
+	lsn.Status.ListenerId = ""
+	lsn.Status.ListenerPort = 0
+	lsn.Status.Phase = nlbv1.ListenerPending
+	setListenerReady(lsn, metav1.ConditionFalse, "PortChanged",
+		fmt.Sprintf("Recreating listener on port %d", lsn.Spec.ListenerPort))
+	if err := r.Status().Update(ctx, lsn); err != nil {
+		return ctrl.Result{}, true, err
+	}
+	return ctrl.Result{RequeueAfter: listenerRequeueShort}, true, nil
+}
+
+// reconcileDescription keeps the description of the cloud listener at Spec.Name.
+// An empty Name leaves the live description alone.
+func (r *ListenerReconciler) reconcileDescription(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	if lsn.Spec.Name == "" || lsn.Spec.Name == attr.ListenerDescription {
+		return nil
+	}
+	klog.FromContext(ctx).Info("Updating Listener description", "listenerId", lsn.Status.ListenerId,
+		"from", attr.ListenerDescription, "to", lsn.Spec.Name)
+	if err := r.NLBClient.UpdateListenerDescription(ctx, lsn.Status.ListenerId, lsn.Spec.Name); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "NameUpdated",
+		"Updated description of Listener %s to %q", lsn.Status.ListenerId, lsn.Spec.Name)
+	return nil
+}
+
+// reconcileCps keeps the new connections per second limit of the cloud listener
+// at Spec.Cps. An unset Cps leaves the live limit alone.
+func (r *ListenerReconciler) reconcileCps(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	if lsn.Spec.Cps == nil || *lsn.Spec.Cps == attr.Cps {
+		return nil
+	}
+	klog.FromContext(ctx).Info("Updating Listener CPS limit", "listenerId", lsn.Status.ListenerId,
+		"from", attr.Cps, "to", *lsn.Spec.Cps)
+	if err := r.NLBClient.UpdateListenerCps(ctx, lsn.Status.ListenerId, *lsn.Spec.Cps); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "CpsUpdated",
+		"Updated CPS limit of Listener %s from %d to %d", lsn.Status.ListenerId, attr.Cps, *lsn.Spec.Cps)
+	return nil
+}
+
+// listenerOptions returns the optional settings of Spec sent with CreateListener.
+func listenerOptions(lsn *nlbv1.Listener) provider.ListenerOptions {
+	opts := provider.ListenerOptions{
+		Description:   lsn.Spec.Name,
+		Cps:           lsn.Spec.Cps,
+		IdleTimeout:   lsn.Spec.IdleTimeout,
+		ProxyProtocol: desiredProxyProtocol(lsn),
+		Alpn:          desiredAlpn(lsn),
+		CA:            desiredCA(lsn),
+	}
+	if lsn.Spec.ListenerProtocol == listenerProtocolTCPSSL {
+		opts.SecurityPolicyId = lsn.Spec.SecurityPolicyId
+	}
+	return opts
+}
+
+// reconcileIdleTimeout keeps the idle connection timeout of the cloud listener at
+// Spec.IdleTimeout. An unset IdleTimeout leaves the live timeout alone.
+func (r *ListenerReconciler) reconcileIdleTimeout(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	if lsn.Spec.IdleTimeout == nil || *lsn.Spec.IdleTimeout == attr.IdleTimeout {
+		return nil
+	}
+	klog.FromContext(ctx).Info("Updating Listener idle timeout", "listenerId", lsn.Status.ListenerId,
+		"from", attr.IdleTimeout, "to", *lsn.Spec.IdleTimeout)
+	if err := r.NLBClient.UpdateListenerIdleTimeout(ctx, lsn.Status.ListenerId, lsn.Spec.ListenerProtocol, *lsn.Spec.IdleTimeout); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "IdleTimeoutUpdated",
+		"Updated idle timeout of Listener %s from %ds to %ds", lsn.Status.ListenerId, attr.IdleTimeout, *lsn.Spec.IdleTimeout)
+	return nil
+}
+
+// desiredProxyProtocol returns the proxy protocol configuration of Spec, or nil
+// when ProxyProtocolEnabled is unset.
+func desiredProxyProtocol(lsn *nlbv1.Listener) *provider.ListenerProxyProtocol {
+	if lsn.Spec.ProxyProtocolEnabled == nil {
+		return nil
+	}
+	return &provider.ListenerProxyProtocol{Enabled: *lsn.Spec.ProxyProtocolEnabled, V2: lsn.Spec.ProxyProtocolV2Config}
+}
+
+// proxyProtocolInSync reports whether the cloud listener attr matches the proxy
+// protocol configuration desired, which leaves it alone when nil.
+func proxyProtocolInSync(desired *provider.ListenerProxyProtocol, attr *provider.ListenerAttribute) bool {
+	return desired == nil || desired.Enabled == attr.ProxyProtocolEnabled && (desired.V2 == nil || *desired.V2 == attr.ProxyProtocolV2)
+}
+
+// reconcileProxyProtocol converges the proxy protocol configuration of the cloud
+// listener with Spec.ProxyProtocolEnabled and Spec.ProxyProtocolV2Config. Unset
+// ProxyProtocolEnabled leaves it alone, and unset ProxyProtocolV2Config leaves the
+// live v2 header fields alone.
+func (r *ListenerReconciler) reconcileProxyProtocol(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	desired := desiredProxyProtocol(lsn)
+	if proxyProtocolInSync(desired, attr) {
+		return nil
+	}
+
+	klog.FromContext(ctx).Info("Updating Listener proxy protocol", "listenerId", lsn.Status.ListenerId,
+		"enabled", desired.Enabled, "v2", desired.V2)
+	if err := r.NLBClient.UpdateListenerProxyProtocol(ctx, lsn.Status.ListenerId, *desired); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "ProxyProtocolUpdated",
+		"Updated proxy protocol of Listener %s: enabled=%t", lsn.Status.ListenerId, desired.Enabled)
+	return nil
+}
+
+// desiredAlpn returns the ALPN configuration of Spec, or nil when it is unset or
+// the listener is not TCPSSL.
+func desiredAlpn(lsn *nlbv1.Listener) *provider.ListenerAlpn {
+	if lsn.Spec.AlpnEnabled == nil || lsn.Spec.ListenerProtocol != listenerProtocolTCPSSL {
+		return nil
+	}
+	return &provider.ListenerAlpn{Enabled: *lsn.Spec.AlpnEnabled, Policy: lsn.Spec.AlpnPolicy}
+}
+
+// alpnInSync reports whether the cloud listener attr matches the ALPN
+// configuration desired, which leaves it alone when nil.
+func alpnInSync(desired *provider.ListenerAlpn, attr *provider.ListenerAttribute) bool {
+	return desired == nil || desired.Enabled == attr.AlpnEnabled && (!desired.Enabled || desired.Policy == attr.AlpnPolicy)
+}
+
+// reconcileAlpn converges the ALPN configuration of the cloud listener with
+// Spec.AlpnEnabled and Spec.AlpnPolicy. Unset AlpnEnabled leaves it alone.
+func (r *ListenerReconciler) reconcileAlpn(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	desired := desiredAlpn(lsn)
+	if alpnInSync(desired, attr) {
+		return nil
+	}
+
+	klog.FromContext(ctx).Info("Updating Listener ALPN", "listenerId", lsn.Status.ListenerId,
+		"enabled", desired.Enabled, "policy", desired.Policy)
+	if err := r.NLBClient.UpdateListenerAlpn(ctx, lsn.Status.ListenerId, *desired); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "AlpnUpdated",
+		"Updated ALPN of Listener %s: enabled=%t policy=%s", lsn.Status.ListenerId, desired.Enabled, desired.Policy)
+	return nil
+}
+
+// reconcileSecurityPolicy keeps the TLS security policy of the cloud TCPSSL
+// listener at Spec.SecurityPolicyId. An empty SecurityPolicyId leaves the live
+// policy alone.
+func (r *ListenerReconciler) reconcileSecurityPolicy(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	if lsn.Spec.SecurityPolicyId == "" || lsn.Spec.SecurityPolicyId == attr.SecurityPolicyId {
+		return nil
+	}
+	klog.FromContext(ctx).Info("Updating Listener TLS security policy", "listenerId", lsn.Status.ListenerId,
+		"from", attr.SecurityPolicyId, "to", lsn.Spec.SecurityPolicyId)
+	if err := r.NLBClient.UpdateListenerSecurityPolicy(ctx, lsn.Status.ListenerId, lsn.Spec.SecurityPolicyId); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "SecurityPolicyUpdated",
+		"Updated TLS security policy of Listener %s from %s to %s", lsn.Status.ListenerId, attr.SecurityPolicyId, lsn.Spec.SecurityPolicyId)
+	return nil
+}
+
+// desiredCA returns the mutual TLS configuration of Spec, or nil when CaEnabled is
+// unset or the listener is not TCPSSL.
+func desiredCA(lsn *nlbv1.Listener) *provider.ListenerCA {
+	if lsn.Spec.CaEnabled == nil || lsn.Spec.ListenerProtocol != listenerProtocolTCPSSL {
+		return nil
+	}
+	return &provider.ListenerCA{Enabled: *lsn.Spec.CaEnabled, CertificateIds: lsn.Spec.CaCertificateIds}
+}
+
+// caInSync reports whether the cloud listener attr matches the mutual TLS
+// configuration desired, which leaves it alone when nil.
+func caInSync(desired *provider.ListenerCA, attr *provider.ListenerAttribute) bool {
+	return desired == nil || desired.Enabled == attr.CaEnabled &&
+		(len(desired.CertificateIds) == 0 || sets.New(desired.CertificateIds...).Equal(sets.New(attr.CaCertificateIds...)))
+}
+
+// reconcileCA converges the mutual TLS configuration of the cloud listener with
+// Spec.CaEnabled and Spec.CaCertificateIds. Unset CaEnabled leaves it alone, and
+// empty CaCertificateIds leaves the live CA certificates alone.
+func (r *ListenerReconciler) reconcileCA(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) error {
+	desired := desiredCA(lsn)
+	if caInSync(desired, attr) {
+		return nil
+	}
+
+	klog.FromContext(ctx).Info("Updating Listener mutual TLS", "listenerId", lsn.Status.ListenerId,
+		"enabled", desired.Enabled, "caCertificateIds", desired.CertificateIds)
+	if err := r.NLBClient.UpdateListenerCA(ctx, lsn.Status.ListenerId, *desired); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "CAUpdated",
+		"Updated mutual TLS of Listener %s: enabled=%t caCertificates=%v", lsn.Status.ListenerId, desired.Enabled, desired.CertificateIds)
+	return nil
+}
+
+// reconcileAdditionalCertificates converges the SNI certificates on the cloud
+// listener with Spec.AdditionalCertificates. The spec is authoritative: any
+// additional certificate not listed there is dissociated.
+func (r *ListenerReconciler) reconcileAdditionalCertificates(ctx context.Context, lsn *nlbv1.Listener) error {
+	log := klog.FromContext(ctx)
+
+	current, err := r.NLBClient.ListAdditionalCertificates(ctx, lsn.Status.ListenerId)
+	if err != nil {
+		return err
+	}
+	toAssociate, toDissociate := diffAdditionalCertificates(lsn, current)
+
+	if len(toAssociate) > 0 {
+		log.Info("Associating additional certificates", "certificateIds", toAssociate)
+		if err := r.NLBClient.AssociateAdditionalCertificates(ctx, lsn.Status.ListenerId, toAssociate); err != nil {
+			return err
+		}
+	}
+	if len(toDissociate) > 0 {
+		log.Info("Dissociating additional certificates", "certificateIds", toDissociate)
+		if err := r.NLBClient.DissociateAdditionalCertificates(ctx, lsn.Status.ListenerId, toDissociate); err != nil {
+			return err
+		}
+	}
+	if len(toAssociate) > 0 || len(toDissociate) > 0 {
+		r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "CertificatesUpdated",
+			"Associated %v, dissociated %v", toAssociate, toDissociate)
+	}
+	return nil
+}
+
+// diffAdditionalCertificates returns the certificate IDs to associate and dissociate
+// so that the listener carries exactly Spec.AdditionalCertificates.
+func diffAdditionalCertificates(lsn *nlbv1.Listener, current []string) (toAssociate, toDissociate []string) {
+	live := make(map[string]bool, len(current))
+	for _, id := range current {
+		live[id] = true
+	}
+	desired := make(map[string]bool, len(lsn.Spec.AdditionalCertificates))
+	for _, cert := range lsn.Spec.AdditionalCertificates {
+		if desired[cert.CertificateId] {
+			continue
+		}
+		desired[cert.CertificateId] = true
+		if !live[cert.CertificateId] {
+			toAssociate = append(toAssociate, cert.CertificateId)
+		}
+	}
+	for _, id := range current {
+		if !desired[id] {
+			toDissociate = append(toDissociate, id)
+		}
+	}
+	return toAssociate, toDissociate
+}
+
 // resolveRefs reads referenced NLB and ServerGroup CRs and returns
 // the resolved cloud IDs. ready=false means we should requeue.
 func (r *ListenerReconciler) resolveRefs(ctx context.Context, lsn *nlbv1.Listener) (string, string, bool, string, error) {
@@ -270,6 +866,47 @@ func (r *ListenerReconciler) resolveRefs(ctx context.Context, lsn *nlbv1.Listene
 	return nlb.Status.LoadBalancerId, sg.Status.ServerGroupId, true, "", nil
 }
 
+// regionMismatch returns a message naming the referenced NLB or ServerGroup CR
+// whose region differs from Spec.Region, or "" when they agree. Empty regions
+// stand for the default region; references that do not exist yet and regions
+// that cannot be resolved are skipped.
+func (r *ListenerReconciler) regionMismatch(ctx context.Context, lsn *nlbv1.Listener) (string, error) {
+	region := resolveRegion(r.Clients, lsn.Spec.Region)
+
+	nlb := &nlbv1.NLB{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: lsn.Namespace, Name: lsn.Spec.LoadBalancerRef}, nlb); err == nil {
+		if nlbRegion := resolveRegion(r.Clients, nlb.Spec.RegionId); nlbRegion != "" && region != "" && nlbRegion != region {
+			return fmt.Sprintf("region %s does not match region %s of NLB %s", region, nlbRegion, nlb.Name), nil
+		}
+	} else if !errors.IsNotFound(err) {
+		return "", err
+	}
+
+	sg := &nlbv1.ServerGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: lsn.Namespace, Name: lsn.Spec.ServerGroupRef}, sg); err == nil {
+		if sgRegion := resolveRegion(r.Clients, sg.Spec.Region); sgRegion != "" && region != "" && sgRegion != region {
+			return fmt.Sprintf("region %s does not match region %s of ServerGroup %s", region, sgRegion, sg.Name), nil
+		}
+	} else if !errors.IsNotFound(err) {
+		return "", err
+	}
+	return "", nil
+}
+
+// currentLoadBalancerId returns the instance ID the referenced NLB CR points at,
+// empty when the CR is missing or has no instance yet.
+func (r *ListenerReconciler) currentLoadBalancerId(ctx context.Context, lsn *nlbv1.Listener) (string, error) {
+	nlb := &nlbv1.NLB{}
+	nlbKey := types.NamespacedName{Namespace: lsn.Namespace, Name: lsn.Spec.LoadBalancerRef}
+	if err := r.Get(ctx, nlbKey, nlb); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return nlb.Status.LoadBalancerId, nil
+}
+
 // handleDeletion drives the Listener removal flow.
 func (r *ListenerReconciler) handleDeletion(ctx context.Context, lsn *nlbv1.Listener) (ctrl.Result, error) {
 	log := klog.FromContext(ctx)
@@ -306,10 +943,15 @@ func (r *ListenerReconciler) handleDeletion(ctx context.Context, lsn *nlbv1.List
 		return ctrl.Result{}, nil
 	}
 
-	// 4. Mark Deleting and call DeleteListener.
+	// 4. Let in-flight connections complete if the ServerGroup drains connections.
+	if result, waiting, err := r.waitForConnectionDrain(ctx, lsn); waiting || err != nil {
+		return result, err
+	}
+
+	// 5. Mark Deleting and call DeleteListener.
 	if lsn.Status.Phase != nlbv1.ListenerDeleting {
 		lsn.Status.Phase = nlbv1.ListenerDeleting
-		lsn.Status.Message = "Submitting DeleteListener"
+		setListenerReady(lsn, metav1.ConditionFalse, "Deleting", "Submitting DeleteListener")
 		if err := r.Status().Update(ctx, lsn); err != nil {
 			log.Error(err, "Failed to update Listener status to Deleting")
 		}
@@ -324,10 +966,230 @@ func (r *ListenerReconciler) handleDeletion(ctx context.Context, lsn *nlbv1.List
 	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Deleting",
 		"Submitted DeleteListener for %s", lsn.Status.ListenerId)
 
-	// 5. Requeue to confirm cloud-side completion before removing finalizer.
+	// 6. Requeue to confirm cloud-side completion before removing finalizer.
 	return ctrl.Result{RequeueAfter: listenerRequeueShort}, nil
 }
 
+// waitForConnectionDrain delays DeleteListener by the referenced ServerGroup's
+// connection drain timeout, measured from when the Draining condition was first set.
+// waiting reports whether the caller must return result/err instead of deleting.
+func (r *ListenerReconciler) waitForConnectionDrain(ctx context.Context, lsn *nlbv1.Listener) (result ctrl.Result, waiting bool, err error) {
+	sg := &nlbv1.ServerGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: lsn.Namespace, Name: lsn.Spec.ServerGroupRef}, sg); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, false, nil
+		}
+		return ctrl.Result{}, true, err
+	}
+	if !sg.Spec.ConnectionDrainEnabled || sg.Spec.ConnectionDrainTimeout <= 0 {
+		return ctrl.Result{}, false, nil
+	}
+
+	timeout := time.Duration(sg.Spec.ConnectionDrainTimeout) * time.Second
+	cond := meta.FindStatusCondition(lsn.Status.Conditions, ConditionTypeDraining)
+	if cond == nil {
+		meta.SetStatusCondition(&lsn.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeDraining,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ConnectionDrain",
+			Message:            fmt.Sprintf("Waiting %s for connections to drain before deleting", timeout),
+			ObservedGeneration: lsn.Generation,
+		})
+		if err := r.Status().Update(ctx, lsn); err != nil {
+			return ctrl.Result{}, true, err
+		}
+		r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Draining",
+			"Waiting %s for ServerGroup %s connection drain before deleting Listener %s",
+			timeout, sg.Name, lsn.Status.ListenerId)
+		return ctrl.Result{RequeueAfter: timeout}, true, nil
+	}
+
+	if remaining := timeout - time.Since(cond.LastTransitionTime.Time); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, true, nil
+	}
+	return ctrl.Result{}, false, nil
+}
+
+// listenerProvisioned reports whether the cloud listener has finished provisioning.
+// Stopped counts as provisioned since it is the result of AdminState Stopped.
+func listenerProvisioned(cloudStatus string) bool {
+	return cloudStatus == cloudListenerStatusRunning || cloudStatus == cloudListenerStatusStopped
+}
+
+// desiredAdminState returns Spec.AdminState, defaulting to Running.
+func desiredAdminState(lsn *nlbv1.Listener) string {
+	if lsn.Spec.AdminState == "" {
+		return nlbv1.ListenerAdminStateRunning
+	}
+	return lsn.Spec.AdminState
+}
+
+// reconcileAdminState starts or stops the cloud listener to match Spec.AdminState
+// and mirrors the live state into Status.Status. waiting reports whether the caller
+// must return result/err, e.g. while the listener is busy with another change.
+func (r *ListenerReconciler) reconcileAdminState(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) (result ctrl.Result, waiting bool, err error) {
+	log := klog.FromContext(ctx)
+	live := attr.ListenerStatus
+
+	switch desired := desiredAdminState(lsn); {
+	case desired == nlbv1.ListenerAdminStateStopped && live == cloudListenerStatusRunning:
+		log.Info("Stopping Listener", "listenerId", lsn.Status.ListenerId)
+		if err := r.NLBClient.StopListener(ctx, lsn.Status.ListenerId); err != nil {
+			result, err := r.updateFailed(ctx, lsn, "StopFailed", err, "Failed to stop Listener %s: %v", lsn.Status.ListenerId, err)
+			return result, true, err
+		}
+		live = cloudListenerStatusStopped
+		r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Stopped", "Stopped Listener %s", lsn.Status.ListenerId)
+	case desired == nlbv1.ListenerAdminStateRunning && live == cloudListenerStatusStopped:
+		log.Info("Starting Listener", "listenerId", lsn.Status.ListenerId)
+		if err := r.NLBClient.StartListener(ctx, lsn.Status.ListenerId); err != nil {
+			result, err := r.updateFailed(ctx, lsn, "StartFailed", err, "Failed to start Listener %s: %v", lsn.Status.ListenerId, err)
+			return result, true, err
+		}
+		live = cloudListenerStatusRunning
+		r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "Started", "Started Listener %s", lsn.Status.ListenerId)
+	}
+
+	if lsn.Status.Status != live {
+		lsn.Status.Status = live
+		if err := r.Status().Update(ctx, lsn); err != nil {
+			return ctrl.Result{}, true, err
+		}
+	}
+	if !listenerProvisioned(live) {
+		log.V(2).Info("Listener busy, waiting before converging admin state", "cloudStatus", live)
+		return ctrl.Result{RequeueAfter: listenerRequeueShort}, true, nil
+	}
+	return ctrl.Result{}, false, nil
+}
+
+// reconcileServerGroup switches the cloud listener to the server group of
+// Spec.ServerGroupRef when it points elsewhere, e.g. after the ref was edited.
+// waiting reports whether the caller must return result/err.
+func (r *ListenerReconciler) reconcileServerGroup(ctx context.Context, lsn *nlbv1.Listener, attr *provider.ListenerAttribute) (result ctrl.Result, waiting bool, err error) {
+	log := klog.FromContext(ctx)
+
+	sg := &nlbv1.ServerGroup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: lsn.Namespace, Name: lsn.Spec.ServerGroupRef}, sg); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Referenced ServerGroup not found, keeping current server group", "serverGroupRef", lsn.Spec.ServerGroupRef)
+			return ctrl.Result{RequeueAfter: listenerRequeueShort}, true, nil
+		}
+		return ctrl.Result{}, true, err
+	}
+	if sg.Status.ServerGroupId == "" {
+		return ctrl.Result{}, false, nil
+	}
+	if sg.Status.ServerGroupId == attr.ServerGroupId {
+		return r.clearIncompatibleServerGroup(ctx, lsn)
+	}
+	if sg.Status.Phase != nlbv1.ServerGroupActive {
+		log.Info("Waiting for ServerGroup to become Active before switching", "serverGroup", sg.Name, "phase", sg.Status.Phase)
+		return ctrl.Result{RequeueAfter: listenerRequeueShort}, true, nil
+	}
+
+	if !serverGroupProtocolCompatible(lsn.Spec.ListenerProtocol, sg.Spec.Protocol) {
+		msg := fmt.Sprintf("ServerGroup %s uses protocol %s, which %s listeners cannot forward to",
+			sg.Name, sg.Spec.Protocol, lsn.Spec.ListenerProtocol)
+		r.Recorder.Event(lsn, corev1.EventTypeWarning, "IncompatibleServerGroup", msg)
+		setListenerReady(lsn, metav1.ConditionFalse, "IncompatibleServerGroup", msg)
+		if err := r.Status().Update(ctx, lsn); err != nil {
+			return ctrl.Result{}, true, err
+		}
+		return ctrl.Result{RequeueAfter: resyncPeriodFor(lsn, r.ResyncPeriod)}, true, nil
+	}
+
+	log.Info("Switching Listener server group", "listenerId", lsn.Status.ListenerId,
+		"from", attr.ServerGroupId, "to", sg.Status.ServerGroupId)
+	if err := r.NLBClient.UpdateListenerServerGroup(ctx, lsn.Status.ListenerId, sg.Status.ServerGroupId); err != nil {
+		result, err := r.updateFailed(ctx, lsn, "ServerGroupSwitchFailed", err,
+			"Failed to switch Listener %s to server group %s: %v", lsn.Status.ListenerId, sg.Status.ServerGroupId, err)
+		return result, true, err
+	}
+	r.Recorder.Eventf(lsn, corev1.EventTypeNormal, "ServerGroupSwitched",
+		"Switched Listener %s from server group %s to %s (%s)", lsn.Status.ListenerId, attr.ServerGroupId, sg.Status.ServerGroupId, sg.Name)
+	return r.clearIncompatibleServerGroup(ctx, lsn)
+}
+
+// clearIncompatibleServerGroup marks lsn Ready again once the listener forwards to
+// the server group of its spec after an IncompatibleServerGroup rejection.
+func (r *ListenerReconciler) clearIncompatibleServerGroup(ctx context.Context, lsn *nlbv1.Listener) (ctrl.Result, bool, error) {
+	cond := meta.FindStatusCondition(lsn.Status.Conditions, ConditionTypeReady)
+	if cond == nil || cond.Reason != "IncompatibleServerGroup" {
+		return ctrl.Result{}, false, nil
+	}
+	setListenerReady(lsn, metav1.ConditionTrue, "Running", "Listener is running")
+	if err := r.Status().Update(ctx, lsn); err != nil {
+		return ctrl.Result{}, true, err
+	}
+	return ctrl.Result{}, false, nil
+}
+
+// serverGroupProtocolCompatible reports whether a listener of listenerProtocol can
+// forward to a server group of sgProtocol: TCP and UDP listeners need a server
+// group of the same protocol, TCPSSL listeners a TCP or TCPSSL one.
+func serverGroupProtocolCompatible(listenerProtocol, sgProtocol string) bool {
+	if listenerProtocol == listenerProtocolTCPSSL {
+		return sgProtocol == "TCP" || sgProtocol == listenerProtocolTCPSSL
+	}
+	return listenerProtocol == sgProtocol
+}
+
+// portOwner returns the Listener that holds the port lsn wants on the same NLB,
+// or nil when lsn may use it. A Listener with a cloud ListenerId always holds
+// its port; among the others the oldest (then lowest name) wins.
+func (r *ListenerReconciler) portOwner(ctx context.Context, lsn *nlbv1.Listener) (*nlbv1.Listener, error) {
+	list := &nlbv1.ListenerList{}
+	if err := r.List(ctx, list, client.InNamespace(lsn.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Name == lsn.Name || other.DeletionTimestamp != nil ||
+			other.Spec.LoadBalancerRef != lsn.Spec.LoadBalancerRef || other.Spec.ListenerPort != lsn.Spec.ListenerPort {
+			continue
+		}
+		if other.Status.ListenerId != "" {
+			return other, nil
+		}
+		if other.CreationTimestamp.Before(&lsn.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&lsn.CreationTimestamp) && other.Name < lsn.Name) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// setListenerReady records the outcome of the last reconcile step in Reason/Message
+// and the Ready condition, so `kubectl describe` shows why a listener is not up.
+func setListenerReady(lsn *nlbv1.Listener, status metav1.ConditionStatus, reason, message string) {
+	lsn.Status.Reason = reason
+	lsn.Status.Message = message
+	meta.SetStatusCondition(&lsn.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: lsn.Generation,
+	})
+}
+
+// updateFailed reports a failed update of the cloud listener in a Warning event
+// and the Ready condition, both with reason, and returns the requeue for err.
+// Waiting for the local rate limiter is not a failure and only requeues.
+func (r *ListenerReconciler) updateFailed(ctx context.Context, lsn *nlbv1.Listener, reason string, err error, format string, args ...interface{}) (ctrl.Result, error) {
+	if provider.IsLocalRateLimited(err) {
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+	msg := fmt.Sprintf(format, args...)
+	r.Recorder.Event(lsn, corev1.EventTypeWarning, reason, msg)
+	setListenerReady(lsn, metav1.ConditionFalse, reason, msg)
+	if statusErr := r.Status().Update(ctx, lsn); statusErr != nil {
+		return ctrl.Result{}, statusErr
+	}
+	return r.requeueOnAPIError(err), nil
+}
+
 func (r *ListenerReconciler) requeueOnAPIError(err error) ctrl.Result {
 	if provider.IsThrottlingError(err) {
 		return ctrl.Result{RequeueAfter: listenerRequeueThrottling}
@@ -337,14 +1199,71 @@ func (r *ListenerReconciler) requeueOnAPIError(err error) ctrl.Result {
 
 // SetupWithManager wires the controller into the manager.
 func (r *ListenerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	maxConcurrent := r.MaxConcurrentReconciles
-	if maxConcurrent <= 0 {
-		maxConcurrent = 1
-	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nlbv1.Listener{}).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: maxConcurrent,
-		}).
+		// Pick up a referenced NLB or ServerGroup becoming ready (or going away)
+		// immediately instead of on the next listenerRequeueShort.
+		Watches(&nlbv1.NLB{},
+			handler.EnqueueRequestsFromMapFunc(r.listenersReferencing(func(lsn *nlbv1.Listener) string {
+				return lsn.Spec.LoadBalancerRef
+			})),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: nlbReadinessChanged}),
+		).
+		Watches(&nlbv1.ServerGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.listenersReferencing(func(lsn *nlbv1.Listener) string {
+				return lsn.Spec.ServerGroupRef
+			})),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: serverGroupReadinessChanged}),
+		).
+		WithOptions(controllerOptions(r.MaxConcurrentReconciles)).
 		Complete(r)
 }
+
+// listenersReferencing maps an object to the Listeners in its namespace whose
+// reference returned by ref names it.
+func (r *ListenerReconciler) listenersReferencing(ref func(*nlbv1.Listener) string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := &nlbv1.ListenerList{}
+		if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to list Listeners", "namespace", obj.GetNamespace())
+			return nil
+		}
+		var reqs []reconcile.Request
+		for i := range list.Items {
+			if ref(&list.Items[i]) == obj.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: list.Items[i].Namespace,
+					Name:      list.Items[i].Name,
+				}})
+			}
+		}
+		return reqs
+	}
+}
+
+// nlbReadinessChanged filters NLB updates down to those that can unblock or
+// invalidate a Listener, or that request a forced sync, so routine status writes
+// don't fan out.
+func nlbReadinessChanged(e event.UpdateEvent) bool {
+	oldNLB, ok1 := e.ObjectOld.(*nlbv1.NLB)
+	newNLB, ok2 := e.ObjectNew.(*nlbv1.NLB)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return oldNLB.Status.LoadBalancerId != newNLB.Status.LoadBalancerId ||
+		oldNLB.Status.LoadBalancerStatus != newNLB.Status.LoadBalancerStatus ||
+		oldNLB.DeletionTimestamp.IsZero() != newNLB.DeletionTimestamp.IsZero() ||
+		oldNLB.Annotations[nlbv1.ForceSyncAnnotation] != newNLB.Annotations[nlbv1.ForceSyncAnnotation]
+}
+
+// serverGroupReadinessChanged is the ServerGroup counterpart of nlbReadinessChanged.
+func serverGroupReadinessChanged(e event.UpdateEvent) bool {
+	oldSG, ok1 := e.ObjectOld.(*nlbv1.ServerGroup)
+	newSG, ok2 := e.ObjectNew.(*nlbv1.ServerGroup)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return oldSG.Status.ServerGroupId != newSG.Status.ServerGroupId ||
+		oldSG.Status.Phase != newSG.Status.Phase ||
+		oldSG.DeletionTimestamp.IsZero() != newSG.DeletionTimestamp.IsZero()
+}
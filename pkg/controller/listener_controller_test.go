@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider/fake"
+)
+
+func testListener() *nlbv1.Listener {
+	return &nlbv1.Listener{
+		ObjectMeta: metav1.ObjectMeta{Name: "https", Namespace: "default", Finalizers: []string{nlbv1.ListenerFinalizer}},
+		Spec: nlbv1.ListenerSpec{
+			LoadBalancerRef:  "web",
+			ListenerPort:     443,
+			ListenerProtocol: "TCP",
+			ServerGroupRef:   "backend",
+		},
+	}
+}
+
+// newTestListenerReconciler returns a ListenerReconciler backed by a fake API
+// server holding lsn, along with an Active NLB nlb-1 and ServerGroup sgp-1 it
+// references, and a fake NLB API. funcs intercepts calls to the API server.
+func newTestListenerReconciler(t *testing.T, lsn *nlbv1.Listener, funcs interceptor.Funcs) (*ListenerReconciler, *fake.NLBClient) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := nlbv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	nlb := testNLB()
+	nlb.Status = nlbv1.NLBStatus{LoadBalancerId: "nlb-1", LoadBalancerStatus: provider.LoadBalancerStatusActive}
+	sg := &nlbv1.ServerGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "default"},
+		Spec:       nlbv1.ServerGroupSpec{Protocol: "TCP"},
+		Status:     nlbv1.ServerGroupStatus{ServerGroupId: "sgp-1", Phase: nlbv1.ServerGroupActive},
+	}
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(lsn, nlb, sg).
+		WithStatusSubresource(&nlbv1.Listener{}, &nlbv1.NLB{}, &nlbv1.ServerGroup{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+
+	nlbClient := fake.NewNLBClient()
+	return &ListenerReconciler{
+		Client:    c,
+		Scheme:    scheme,
+		Recorder:  record.NewFakeRecorder(100),
+		NLBClient: nlbClient,
+	}, nlbClient
+}
+
+// reconcileListener runs one reconcile of lsn and returns the stored Listener.
+func reconcileListener(t *testing.T, r *ListenerReconciler, lsn *nlbv1.Listener) (ctrl.Result, *nlbv1.Listener) {
+	t.Helper()
+
+	key := client.ObjectKeyFromObject(lsn)
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	stored := &nlbv1.Listener{}
+	if err := r.Get(context.Background(), key, stored); err != nil {
+		t.Fatalf("failed to get Listener: %v", err)
+	}
+	return result, stored
+}
+
+func TestCreatedListenerSurvivesStatusConflict(t *testing.T) {
+	// The first status write after the create conflicts with a concurrent write
+	// of the Listener
+	conflicted := false
+	funcs := interceptor.Funcs{
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if lsn, ok := obj.(*nlbv1.Listener); ok && lsn.Status.ListenerId != "" && !conflicted {
+				conflicted = true
+				latest := &nlbv1.Listener{}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(lsn), latest); err != nil {
+					return err
+				}
+				latest.Annotations = map[string]string{"touched": "true"}
+				if err := c.Update(ctx, latest); err != nil {
+					return err
+				}
+			}
+			return c.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+	}
+	lsn := testListener()
+	r, nlbClient := newTestListenerReconciler(t, lsn, funcs)
+
+	_, stored := reconcileListener(t, r, lsn)
+	if !conflicted {
+		t.Fatal("status write did not conflict")
+	}
+	if n := nlbClient.CallCount("CreateNLBListener"); n != 1 {
+		t.Fatalf("CreateNLBListener called %d times, want 1", n)
+	}
+	if stored.Status.ListenerId != "lsn-fake1" || stored.Status.Phase != nlbv1.ListenerCreating {
+		t.Errorf("stored status = %s/%q, want Creating/lsn-fake1", stored.Status.Phase, stored.Status.ListenerId)
+	}
+
+	// The next pass tracks the recorded listener rather than creating another one
+	_, stored = reconcileListener(t, r, lsn)
+	if n := nlbClient.CallCount("CreateNLBListener"); n != 1 {
+		t.Errorf("CreateNLBListener called %d times after retry, want 1", n)
+	}
+	if stored.Status.ListenerId != "lsn-fake1" || stored.Status.Phase != nlbv1.ListenerRunning {
+		t.Errorf("stored status = %s/%q, want Running/lsn-fake1", stored.Status.Phase, stored.Status.ListenerId)
+	}
+}
+
+func TestRunningListenerRecreated(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners map[string][]provider.ListenerAttribute
+	}{
+		{name: "listener gone from the cloud"},
+		{
+			name: "listener still on the replaced instance",
+			listeners: map[string][]provider.ListenerAttribute{
+				"nlb-old": {{ListenerId: "lsn-old", ListenerStatus: "Running", ListenerPort: 443, LoadBalancerId: "nlb-old"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lsn := testListener()
+			lsn.Status = nlbv1.ListenerStatus{ListenerId: "lsn-old", Phase: nlbv1.ListenerRunning}
+			r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+			for lbId, listeners := range tt.listeners {
+				nlbClient.Listeners[lbId] = listeners
+			}
+
+			result, stored := reconcileListener(t, r, lsn)
+			if !result.Requeue {
+				t.Errorf("got result %+v, want a requeue", result)
+			}
+			if stored.Status.Phase != nlbv1.ListenerPending || stored.Status.ListenerId != "" {
+				t.Fatalf("stored status = %s/%q, want Pending without a listener ID", stored.Status.Phase, stored.Status.ListenerId)
+			}
+
+			_, stored = reconcileListener(t, r, lsn)
+			if n := nlbClient.CallCount("CreateNLBListener"); n != 1 {
+				t.Fatalf("CreateNLBListener called %d times, want 1", n)
+			}
+			created := nlbClient.Listeners["nlb-1"]
+			if len(created) != 1 || stored.Status.ListenerId != created[0].ListenerId {
+				t.Errorf("stored ListenerId %q, listeners on nlb-1 %+v, want the recreated listener", stored.Status.ListenerId, created)
+			}
+		})
+	}
+}
+
+func TestListenerRegionMismatch(t *testing.T) {
+	lsn := testListener()
+	lsn.Spec.Region = "cn-shanghai"
+	r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+	nlb := &nlbv1.NLB{}
+	if err := r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "web"}, nlb); err != nil {
+		t.Fatalf("failed to get NLB: %v", err)
+	}
+	nlb.Spec.RegionId = "cn-hangzhou"
+	if err := r.Update(context.Background(), nlb); err != nil {
+		t.Fatalf("failed to update NLB: %v", err)
+	}
+
+	_, stored := reconcileListener(t, r, lsn)
+	if len(nlbClient.Calls) != 0 {
+		t.Errorf("NLB API called %v, want no calls", nlbClient.Calls)
+	}
+	if stored.Status.Reason != ReasonRegionMismatch {
+		t.Errorf("Status.Reason = %q, want %s", stored.Status.Reason, ReasonRegionMismatch)
+	}
+}
+
+func TestCreatingListenerNotListed(t *testing.T) {
+	tests := []struct {
+		name        string
+		createdAgo  time.Duration
+		wantPhase   nlbv1.ListenerPhase
+		wantRequeue time.Duration
+	}{
+		{name: "within the list timeout", createdAgo: time.Second, wantPhase: nlbv1.ListenerCreating, wantRequeue: listenerRequeueError},
+		{name: "past the list timeout", createdAgo: listenerListTimeout + time.Second, wantPhase: nlbv1.ListenerRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lsn := testListener()
+			created := metav1.NewTime(time.Now().Add(-tt.createdAgo))
+			lsn.Status = nlbv1.ListenerStatus{ListenerId: "lsn-1", Phase: nlbv1.ListenerCreating, CreateTime: &created}
+			r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+			// Kept apart from nlb-1 so that ListListeners of nlb-1 does not return it
+			nlbClient.Listeners["unlisted"] = []provider.ListenerAttribute{
+				{ListenerId: "lsn-1", ListenerStatus: "Running", ListenerPort: 443, LoadBalancerId: "nlb-1"},
+			}
+
+			result, stored := reconcileListener(t, r, lsn)
+			if stored.Status.Phase != tt.wantPhase {
+				t.Errorf("Status.Phase = %s, want %s", stored.Status.Phase, tt.wantPhase)
+			}
+			if result.RequeueAfter != tt.wantRequeue {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, tt.wantRequeue)
+			}
+			if n := nlbClient.CallCount("CreateNLBListener"); n != 0 {
+				t.Errorf("CreateNLBListener called %d times, want 0", n)
+			}
+		})
+	}
+}
+
+func TestAlreadyExistsListenerNotListed(t *testing.T) {
+	tests := []struct {
+		name        string
+		waitedFor   time.Duration
+		wantReason  string
+		wantRequeue time.Duration
+	}{
+		{name: "first rejection", wantReason: "AlreadyExists", wantRequeue: listenerRequeueError},
+		{name: "past the list timeout", waitedFor: listenerListTimeout + time.Second, wantReason: "NotListed", wantRequeue: listenerRequeueShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lsn := testListener()
+			if tt.waitedFor > 0 {
+				since := metav1.NewTime(time.Now().Add(-tt.waitedFor))
+				lsn.Status.CreateTime = &since
+			}
+			r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+			nlbClient.Errors["CreateNLBListener"] = fake.APIError("ListenerAlreadyExists")
+
+			result, stored := reconcileListener(t, r, lsn)
+			if result.RequeueAfter != tt.wantRequeue {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, tt.wantRequeue)
+			}
+			if stored.Status.Reason != tt.wantReason {
+				t.Errorf("Status.Reason = %q, want %s", stored.Status.Reason, tt.wantReason)
+			}
+			if stored.Status.CreateTime == nil {
+				t.Error("Status.CreateTime not recorded, want the start of the adopt wait")
+			}
+			if stored.Status.ListenerId != "" {
+				t.Errorf("Status.ListenerId = %q, want none while the listener is not listed", stored.Status.ListenerId)
+			}
+		})
+	}
+}
+
+func TestRunningListenerWaitsForLoadBalancerLock(t *testing.T) {
+	lsn := testListener()
+	lsn.Spec.Name = "renamed"
+	lsn.Status = nlbv1.ListenerStatus{ListenerId: "lsn-1", Phase: nlbv1.ListenerRunning}
+	r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+	nlbClient.Listeners["nlb-1"] = []provider.ListenerAttribute{
+		{ListenerId: "lsn-1", ListenerStatus: "Running", ListenerPort: 443, LoadBalancerId: "nlb-1", ServerGroupId: "sgp-1"},
+	}
+
+	unlock, _, ok := loadBalancerLocks.tryLock("nlb-1", "NLB default/web")
+	if !ok {
+		t.Fatal("failed to take the load balancer lock")
+	}
+	result, _ := reconcileListener(t, r, lsn)
+	unlock()
+	if result.RequeueAfter != loadBalancerLockRetry {
+		t.Errorf("RequeueAfter = %s, want %s while the load balancer is locked", result.RequeueAfter, loadBalancerLockRetry)
+	}
+	if n := nlbClient.CallCount("UpdateListenerDescription"); n != 0 {
+		t.Errorf("UpdateListenerDescription called %d times while locked, want 0", n)
+	}
+
+	reconcileListener(t, r, lsn)
+	if n := nlbClient.CallCount("UpdateListenerDescription"); n != 1 {
+		t.Errorf("UpdateListenerDescription called %d times after unlock, want 1", n)
+	}
+}
+
+func TestRunningListenerUpdateFailure(t *testing.T) {
+	lsn := testListener()
+	lsn.Spec.Name = "renamed"
+	lsn.Status = nlbv1.ListenerStatus{ListenerId: "lsn-1", Phase: nlbv1.ListenerRunning}
+	setListenerReady(lsn, metav1.ConditionTrue, "Running", "Listener is running")
+	r, nlbClient := newTestListenerReconciler(t, lsn, interceptor.Funcs{})
+	nlbClient.Listeners["nlb-1"] = []provider.ListenerAttribute{
+		{ListenerId: "lsn-1", ListenerStatus: "Running", ListenerPort: 443, LoadBalancerId: "nlb-1", ServerGroupId: "sgp-1"},
+	}
+
+	nlbClient.Errors["UpdateListenerDescription"] = fake.APIError("Conflict.Lock")
+	result, stored := reconcileListener(t, r, lsn)
+	if result.RequeueAfter != listenerRequeueError {
+		t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, listenerRequeueError)
+	}
+	if cond := meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeReady); cond == nil ||
+		cond.Status != metav1.ConditionFalse || cond.Reason != "NameUpdateFailed" {
+		t.Errorf("Ready condition = %+v, want False with reason NameUpdateFailed", cond)
+	}
+
+	delete(nlbClient.Errors, "UpdateListenerDescription")
+	_, stored = reconcileListener(t, r, lsn)
+	if !meta.IsStatusConditionTrue(stored.Status.Conditions, ConditionTypeReady) {
+		t.Errorf("Ready condition = %+v, want True once the update applied", meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeReady))
+	}
+}
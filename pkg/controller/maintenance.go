@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// ConditionTypeChangesPending is True while changes to the instance are deferred
+// until Spec.MaintenanceWindow opens.
+const ConditionTypeChangesPending = "ChangesPending"
+
+// maintenanceWindowState reports whether now falls into the daily window w and,
+// when it does not, how long until the window opens. Equal Start and End make
+// the window span the whole day.
+func maintenanceWindowState(w *nlbv1.MaintenanceWindow, now time.Time) (open bool, opensIn time.Duration, err error) {
+	tz := w.TimeZone
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid maintenanceWindow.timeZone %q: %w", w.TimeZone, err)
+	}
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid maintenanceWindow.start %q, expected HH:MM", w.Start)
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid maintenanceWindow.end %q, expected HH:MM", w.End)
+	}
+
+	now = now.In(loc)
+	at := func(clock time.Time, days int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+days, clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+	startToday, endToday := at(start, 0), at(end, 0)
+	switch {
+	case startToday.Equal(endToday):
+		open = true
+	case startToday.Before(endToday):
+		open = !now.Before(startToday) && now.Before(endToday)
+	default:
+		open = !now.Before(startToday) || now.Before(endToday)
+	}
+	if open {
+		return true, 0, nil
+	}
+	next := startToday
+	if !next.After(now) {
+		next = at(start, 1)
+	}
+	return false, next.Sub(now), nil
+}
+
+// handleMaintenanceWindow defers changes to an existing instance while
+// Spec.MaintenanceWindow is closed. deferred reports whether the caller must
+// return result/err instead of reconciling.
+func (r *NLBReconciler) handleMaintenanceWindow(ctx context.Context, nlb *nlbv1.NLB) (result ctrl.Result, deferred bool, err error) {
+	if nlb.Spec.MaintenanceWindow == nil || nlb.Status.LoadBalancerId == "" || !nlb.DeletionTimestamp.IsZero() {
+		r.clearPendingChanges(nlb)
+		return ctrl.Result{}, false, nil
+	}
+
+	open, opensIn, err := maintenanceWindowState(nlb.Spec.MaintenanceWindow, time.Now())
+	if err != nil {
+		// Fail closed: an unreadable window must not let changes through
+		msg := err.Error()
+		if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeChangesPending); cond == nil || cond.Message != msg {
+			r.Recorder.Event(nlb, "Warning", "InvalidMaintenanceWindow", msg)
+		}
+		r.updateCondition(nlb, ConditionTypeChangesPending, metav1.ConditionTrue, "InvalidMaintenanceWindow", msg)
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			return ctrl.Result{}, true, err
+		}
+		return ctrl.Result{}, true, nil
+	}
+	if open {
+		if len(nlb.Status.PendingChanges) > 0 {
+			r.Recorder.Event(nlb, "Normal", "MaintenanceWindowOpen",
+				fmt.Sprintf("Maintenance window is open, applying %d deferred change(s)", len(nlb.Status.PendingChanges)))
+		}
+		r.clearPendingChanges(nlb)
+		return ctrl.Result{}, false, nil
+	}
+
+	result, err = r.deferChanges(ctx, nlb, opensIn)
+	return result, true, err
+}
+
+// deferChanges records the changes a reconcile of nlb would apply in
+// Status.PendingChanges, issuing only read-only NLB API calls, and requeues by the
+// time the maintenance window opens.
+func (r *NLBReconciler) deferChanges(ctx context.Context, nlb *nlbv1.NLB, opensIn time.Duration) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+
+	plan, err := r.planNLB(ctx, nlb)
+	if err != nil {
+		r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to compute pending changes: %v", err))
+		return ctrl.Result{}, err
+	}
+
+	opensAt := time.Now().Add(opensIn).Truncate(time.Minute).Format(time.RFC3339)
+	if len(plan) == 0 {
+		r.updateCondition(nlb, ConditionTypeChangesPending, metav1.ConditionFalse, "NoChanges",
+			"Live instance matches spec, next maintenance window opens at "+opensAt)
+	} else {
+		msg := fmt.Sprintf("%d change(s) deferred until the maintenance window opens at %s: %s",
+			len(plan), opensAt, strings.Join(plan, "; "))
+		if !slices.Equal(plan, nlb.Status.PendingChanges) {
+			r.Recorder.Event(nlb, "Normal", "ChangesDeferred", msg)
+		}
+		r.updateCondition(nlb, ConditionTypeChangesPending, metav1.ConditionTrue, "OutsideMaintenanceWindow", msg)
+	}
+	nlb.Status.PendingChanges = plan
+	if err := r.updateStatus(ctx, nlb); err != nil {
+		log.Error(err, "Failed to update NLB status")
+		return ctrl.Result{}, err
+	}
+
+	// Keep following drift on the resync period, and wake up when the window opens
+	return ctrl.Result{RequeueAfter: min(r.resyncPeriod(nlb), opensIn)}, nil
+}
+
+// clearPendingChanges drops the deferred changes; they are persisted by the next
+// status update of the reconcile.
+func (r *NLBReconciler) clearPendingChanges(nlb *nlbv1.NLB) {
+	nlb.Status.PendingChanges = nil
+	meta.RemoveStatusCondition(&nlb.Status.Conditions, ConditionTypeChangesPending)
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+var apiCallsPerReconcile = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "nlb_api_calls_per_reconcile",
+		Help:    "Number of NLB OpenAPI calls issued by a single reconcile pass",
+		Buckets: []float64{0, 1, 2, 3, 5, 8, 13, 21, 34},
+	},
+	[]string{"controller"},
+)
+
+var driftCorrectionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "nlb_drift_corrections_total",
+		Help: "Number of changes made outside the operator that were reverted to match the spec",
+	},
+	[]string{"field"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiCallsPerReconcile, driftCorrectionsTotal)
+}
+
+// observeAPICalls records the API calls made during one reconcile pass.
+func observeAPICalls(log klog.Logger, controllerName string, counter *provider.APICallCounter) {
+	calls := counter.Count()
+	apiCallsPerReconcile.WithLabelValues(controllerName).Observe(float64(calls))
+	log.V(1).Info("Reconcile finished", "apiCalls", calls)
+}
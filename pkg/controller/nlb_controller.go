@@ -3,44 +3,85 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
 	"github.com/alibabacloud-go/tea/tea"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
 	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
 )
 
 // isNotFoundError reports whether err indicates the cloud NLB resource no longer exists.
-// Provider layer wraps the SDK error; substring match against the SDK error code suffices.
 func isNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(err.Error(), "ResourceNotFound")
+	return provider.IsNotFoundError(err)
 }
 
 const (
 	NLBFinalizer = "nlboperator.alibabacloud.com/finalizer"
 
-	ConditionTypeReady = "Ready"
-	ConditionTypeError = "Error"
+	ConditionTypeReady                = "Ready"
+	ConditionTypeError                = "Error"
+	ConditionTypeZoneMappingsSynced   = "ZoneMappingsSynced"
+	ConditionTypeSecurityGroupsSynced = "SecurityGroupsSynced"
+	ConditionTypeResourceGroupSynced  = "ResourceGroupSynced"
+	ConditionTypeBillingConfigSynced  = "BillingConfigSynced"
+	ConditionTypeAddressTypeSynced    = "AddressTypeSynced"
+	// ConditionTypeZonesHealthy is True when every zone of the instance is Active
+	ConditionTypeZonesHealthy = "ZonesHealthy"
+	// ConditionTypeDeletionStuck is True once deletion has failed
+	// DeletionStuckAttempts times in a row
+	ConditionTypeDeletionStuck = "DeletionStuck"
+	// ConditionTypeOrphanListeners is True while deletion is held up by listeners
+	// not managed by Listener CRs; its message names them
+	ConditionTypeOrphanListeners = "OrphanListeners"
 
 	ReasonReconcileSuccess = "ReconcileSuccess"
 	ReasonReconcileError   = "ReconcileError"
 	ReasonDeletionSuccess  = "DeletionSuccess"
 	ReasonDeletionError    = "DeletionError"
+	ReasonCloudDeleting    = "CloudDeleting"
+	ReasonDeletionTimeout  = "DeletionTimeout"
+	ReasonDeletionBlocked  = "DeletionProtected"
+
+	ReasonModificationProtected = "ModificationProtected"
+	ReasonCreateFailed          = "CreateFailed"
+	ReasonZoneNotSupported      = "ZoneNotSupported"
+	ReasonImmutableField        = "ImmutableField"
+	ReasonQuotaExceeded         = "QuotaExceeded"
+	ReasonConfiguring           = "Configuring"
+	ReasonAddressAllocating     = "AddressAllocating"
+	ReasonRegionMismatch        = "RegionMismatch"
+
+	// addressRetryInterval is the requeue interval after GetXipFailed, which clears
+	// within seconds once the addresses of a new instance are allocated.
+	addressRetryInterval = 5 * time.Second
+
+	// LoadBalancerStatusCreateFailed is set on Status.LoadBalancerStatus when creation
+	// failed with a terminal error; no further attempt is made until the spec changes.
+	LoadBalancerStatusCreateFailed = "CreateFailed"
 )
 
 // NLBReconciler reconciles an NLB object
@@ -48,42 +89,281 @@ type NLBReconciler struct {
 	client.Client
 	Scheme                  *runtime.Scheme
 	Recorder                record.EventRecorder
-	NLBClient               *provider.NLBClient
+	NLBClient               provider.NLBClientInterface
 	MaxConcurrentReconciles int
+
+	// Clients, when set, selects the NLBClient for the region of each resource;
+	// NLBClient is then only used for resources in the default region.
+	Clients *provider.ClientPool
+
+	// ActiveCheckInterval is how long to wait before re-checking an NLB that is
+	// not yet Active. Provisioning is tracked by requeueing rather than blocking
+	// a worker. Defaults to defaultActiveCheckInterval when zero.
+	ActiveCheckInterval time.Duration
+
+	// ResyncPeriod is how long to wait before re-checking an NLB in steady state
+	// for drift. Defaults to defaultResyncPeriod when zero.
+	ResyncPeriod time.Duration
+
+	// OperatorId is the value of the ManagedByTagKey tag on NLB instances created
+	// by this operator. Defaults to defaultOperatorId when empty.
+	OperatorId string
+
+	// DefaultTags are applied to every NLB instance in addition to Spec.Tags, which
+	// take precedence on key conflicts.
+	DefaultTags []nlbv1.Tag
+
+	// StableLoadBalancerStates are the LoadBalancerStatus values in which the
+	// updates of a reconcile are considered applied; until the instance reaches
+	// one of them it is reported not Ready and checked again shortly. Defaults to
+	// provider.DefaultStableLoadBalancerStates when empty.
+	StableLoadBalancerStates []string
+
+	// DeletionTimeout is how long deletion of an NLB may keep failing before a
+	// DeletionTimeout warning is raised. Zero disables the timeout.
+	DeletionTimeout time.Duration
+
+	// HonorDeletionProtection refuses to delete an NLB whose spec enables deletion
+	// protection until it is disabled in the spec, instead of lifting the protection
+	// of the cloud instance and deleting it.
+	HonorDeletionProtection bool
+
+	// ForceRemoveFinalizer removes the finalizer once DeletionTimeout has passed,
+	// orphaning the cloud instance, so the CR and its namespace can be deleted.
+	ForceRemoveFinalizer bool
+
+	// DeletionRetryMaxBackoff caps the exponentially growing interval between
+	// failed deletion attempts. Defaults to defaultErrorBackoffMax when zero.
+	DeletionRetryMaxBackoff time.Duration
+
+	// DeletionStuckAttempts is the number of consecutive failed deletion attempts
+	// after which the DeletionStuck condition is set and a warning is raised.
+	// Zero disables it.
+	DeletionStuckAttempts int
+
+	// DeletionStuckRetryInterval is how often a stuck deletion is retried.
+	// Defaults to defaultDeletionStuckRetryInterval when zero.
+	DeletionStuckRetryInterval time.Duration
+
+	// errorBackoff spaces out the retries of NLBs whose reconcile keeps failing
+	errorBackoff *requeueBackoff
+	// deletionBackoff counts and spaces out the failed deletion attempts of NLBs
+	deletionBackoff *requeueBackoff
+}
+
+// defaultDeletionStuckRetryInterval is the retry interval of a stuck deletion
+// when DeletionStuckRetryInterval is not set.
+const defaultDeletionStuckRetryInterval = 15 * time.Minute
+
+const defaultOperatorId = "nlb-operator"
+
+func (r *NLBReconciler) operatorId() string {
+	if r.OperatorId == "" {
+		return defaultOperatorId
+	}
+	return r.OperatorId
+}
+
+// provenanceTags returns the tags linking the cloud instance of nlb back to this
+// operator and the CR. They are applied on create and kept by tag reconciliation.
+func (r *NLBReconciler) provenanceTags(nlb *nlbv1.NLB) []nlbv1.Tag {
+	return []nlbv1.Tag{
+		{Key: nlbv1.ManagedByTagKey, Value: r.operatorId()},
+		{Key: nlbv1.CRNamespaceTagKey, Value: nlb.Namespace},
+		{Key: nlbv1.CRNameTagKey, Value: nlb.Name},
+	}
+}
+
+// desiredTags returns Spec.Tags followed by the DefaultTags whose keys the spec
+// does not set.
+func (r *NLBReconciler) desiredTags(nlb *nlbv1.NLB) []nlbv1.Tag {
+	if len(r.DefaultTags) == 0 {
+		return nlb.Spec.Tags
+	}
+	tags := make([]nlbv1.Tag, 0, len(nlb.Spec.Tags)+len(r.DefaultTags))
+	set := make(map[string]bool, len(nlb.Spec.Tags))
+	for _, t := range nlb.Spec.Tags {
+		set[t.Key] = true
+		tags = append(tags, t)
+	}
+	for _, t := range r.DefaultTags {
+		if !set[t.Key] {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+const defaultActiveCheckInterval = 10 * time.Second
+
+// defaultResyncPeriod is the steady-state requeue interval of all controllers.
+const defaultResyncPeriod = 5 * time.Minute
+
+// resyncPeriodFor returns the steady-state requeue interval for obj: the
+// ResyncPeriodAnnotation when set to a valid positive duration, else d, else
+// defaultResyncPeriod.
+func resyncPeriodFor(obj metav1.Object, d time.Duration) time.Duration {
+	if v, ok := obj.GetAnnotations()[nlbv1.ResyncPeriodAnnotation]; ok {
+		if p, err := time.ParseDuration(v); err == nil && p > 0 {
+			return p
+		}
+		klog.Warningf("Ignoring invalid %s annotation %q on %s/%s", nlbv1.ResyncPeriodAnnotation, v, obj.GetNamespace(), obj.GetName())
+	}
+	if d > 0 {
+		return d
+	}
+	return defaultResyncPeriod
+}
+
+// configuringRequeueInterval is how long to wait for an NLB to finish applying a
+// previous change (Configuring state) before attempting further updates.
+const configuringRequeueInterval = 5 * time.Second
+
+// orphanListenerDeleteWorkers bounds concurrent DeleteListener calls when removing
+// orphan listeners; the NLB client's rate limiter still applies on top.
+const orphanListenerDeleteWorkers = 5
+
+// recoverPanic makes controller-runtime turn a panic in Reconcile (e.g. an
+// unexpected nil field in an SDK response) into a reconcile error instead of
+// crashing the manager. Shared by all controllers in this package.
+var recoverPanic = true
+
+func (r *NLBReconciler) resyncPeriod(nlb *nlbv1.NLB) time.Duration {
+	return resyncPeriodFor(nlb, r.ResyncPeriod)
+}
+
+func (r *NLBReconciler) activeCheckInterval() time.Duration {
+	if r.ActiveCheckInterval > 0 {
+		return r.ActiveCheckInterval
+	}
+	return defaultActiveCheckInterval
 }
 
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=nlbs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=nlbs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=nlbs/finalizers,verbs=update
 
-// Reconcile handles the reconciliation of NLB resources
+// regionalClient returns the client of clients for region, or nil when no
+// client pool is configured and the reconciler's own NLBClient applies.
+func regionalClient(clients *provider.ClientPool, region string) (*provider.NLBClient, error) {
+	if clients == nil {
+		return nil, nil
+	}
+	return clients.Get(region)
+}
+
+// resolveRegion returns region, or the default region of clients when region is
+// empty. It returns "" when neither is known.
+func resolveRegion(clients *provider.ClientPool, region string) string {
+	if region == "" && clients != nil {
+		return clients.DefaultRegion()
+	}
+	return region
+}
+
+// forRegion returns a shallow copy of r whose NLBClient targets region, or r
+// itself when no client pool is configured.
+func (r *NLBReconciler) forRegion(region string) (*NLBReconciler, error) {
+	client, err := regionalClient(r.Clients, region)
+	if client == nil {
+		return r, err
+	}
+	regional := *r
+	regional.NLBClient = client
+	return &regional, nil
+}
+
+// Reconcile handles the reconciliation of NLB resources. Failed reconciles are
+// requeued with a per-NLB exponential backoff instead of a fixed interval.
 func (r *NLBReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := r.reconcile(ctx, req)
+	return r.errorBackoff.result(ctx, req.NamespacedName, result, err)
+}
+
+func (r *NLBReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := klog.FromContext(ctx)
 	log.Info("Reconciling NLB", "name", req.Name, "namespace", req.Namespace)
 
+	ctx, apiCalls := provider.WithAPICallCounter(ctx)
+	defer observeAPICalls(log, "nlb", apiCalls)
+
 	// Fetch the NLB instance
 	nlb := &nlbv1.NLB{}
 	if err := r.Get(ctx, req.NamespacedName, nlb); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("NLB resource not found, ignoring since object must be deleted")
+			r.deletionBackoff.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get NLB resource")
 		return ctrl.Result{}, err
 	}
 
+	// Leave the cloud instance untouched while paused
+	if isPaused(nlb) {
+		return r.handlePaused(ctx, nlb)
+	}
+	// Dropped from the status by the next status update below
+	meta.RemoveStatusCondition(&nlb.Status.Conditions, ConditionTypePaused)
+
+	// Use the client of the region the NLB lives in
+	r, err := r.forRegion(nlb.Spec.RegionId)
+	if err != nil {
+		r.Recorder.Event(nlb, "Warning", ReasonReconcileError, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// Report the planned actions instead of applying them
+	if isDryRun(nlb) {
+		return r.handleDryRun(ctx, nlb)
+	}
+	// The plan of a previous dry-run is stale once the annotation is gone; it is
+	// persisted by the next status update below
+	nlb.Status.PlannedActions = nil
+
+	// Serialize operations on the cloud instance with other CRs targeting it
+	if lbId := nlb.Status.LoadBalancerId; lbId != "" {
+		holder := "NLB " + req.NamespacedName.String()
+		unlock, current, ok := loadBalancerLocks.tryLock(lbId, holder)
+		if !ok {
+			if strings.HasPrefix(current, "NLB ") {
+				r.Recorder.Event(nlb, "Warning", "SharedLoadBalancer",
+					fmt.Sprintf("NLB instance %s is also managed by %s, operations are serialized", lbId, current))
+			}
+			log.V(1).Info("Load balancer is locked by another reconcile, retrying", "loadBalancerId", lbId, "holder", current)
+			return ctrl.Result{RequeueAfter: loadBalancerLockRetry}, nil
+		}
+		defer unlock()
+	}
+
+	// Await jobs left running by a previous run of the operator before issuing
+	// new operations, and record the ones started from now on
+	if len(nlb.Status.PendingJobs) > 0 {
+		if result, done, err := r.awaitPendingJobs(ctx, nlb); !done {
+			return result, err
+		}
+	}
+	ctx = provider.WithJobObserver(ctx, r.jobObserver(ctx, nlb))
+
+	// Outside the maintenance window only record what would change
+	if result, deferred, err := r.handleMaintenanceWindow(ctx, nlb); deferred {
+		return result, err
+	}
+
 	// Check if the NLB is being deleted
 	if !nlb.ObjectMeta.DeletionTimestamp.IsZero() {
 		return r.handleDeletion(ctx, nlb)
 	}
 
-	// Add finalizer if not present
+	// Add finalizer if not present, then requeue so that the create or update
+	// and its status writes start from a freshly read object rather than one
+	// whose resourceVersion may be stale by the time the status is written.
 	if !controllerutil.ContainsFinalizer(nlb, NLBFinalizer) {
-		controllerutil.AddFinalizer(nlb, NLBFinalizer)
-		if err := r.Update(ctx, nlb); err != nil {
+		if err := r.addFinalizer(ctx, nlb); err != nil {
 			log.Error(err, "Failed to add finalizer")
 			return ctrl.Result{}, err
 		}
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Handle NLB creation or update
@@ -95,25 +375,63 @@ func (r *NLBReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 func (r *NLBReconciler) handleCreateOrUpdate(ctx context.Context, nlb *nlbv1.NLB) (ctrl.Result, error) {
 	log := klog.FromContext(ctx)
 
+	// Bind to an existing NLB instead of creating one when requested
+	if nlb.Status.LoadBalancerId == "" {
+		if result, adopted, err := r.adoptExisting(ctx, nlb); adopted || err != nil {
+			return result, err
+		}
+	}
+
 	// Check if LoadBalancer already exists
 	if nlb.Status.LoadBalancerId == "" {
+		// A terminal create failure is only retried once the spec has been edited
+		if nlb.Status.LoadBalancerStatus == LoadBalancerStatusCreateFailed {
+			if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeError); cond != nil &&
+				cond.Reason == ReasonCreateFailed && cond.ObservedGeneration == nlb.Generation {
+				log.Info("Skipping create after terminal failure until spec changes", "generation", nlb.Generation)
+				return ctrl.Result{}, nil
+			}
+		}
+
+		// Fail fast on zones that do not support NLB instead of a cryptic create error
+		if msg := r.validateZones(ctx, nlb); msg != "" {
+			setCreateFailure(nlb, ReasonZoneNotSupported, nil)
+			return r.markCreateFailed(ctx, nlb, ReasonZoneNotSupported, msg)
+		}
+
 		// Create new NLB
 		log.Info("Creating new NLB instance")
-		lbId, err := r.NLBClient.CreateLoadBalancer(ctx, nlb)
+		lbId, err := r.NLBClient.CreateLoadBalancer(ctx, nlb, r.desiredTags(nlb), r.provenanceTags(nlb))
 		if err != nil {
+			// The ClientToken makes the retry return the instance being created
+			if provider.IsGetXipFailedError(err) {
+				return r.waitForAddress(ctx, nlb, err)
+			}
+			if provider.IsTerminalError(err) {
+				reason, hint := classifyCreateError(nlb, err)
+				msg := err.Error()
+				if hint != "" {
+					msg = fmt.Sprintf("%s: %v", hint, err)
+				}
+				setCreateFailure(nlb, reason, err)
+				return r.markCreateFailed(ctx, nlb, reason, msg)
+			}
+			setCreateFailure(nlb, ReasonReconcileError, err)
 			r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to create NLB: %v", err))
 			r.updateCondition(nlb, ConditionTypeError, metav1.ConditionTrue, ReasonReconcileError, err.Error())
-			if statusErr := r.Status().Update(ctx, nlb); statusErr != nil {
+			if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
 				log.Error(statusErr, "Failed to update NLB status after create error")
 			}
-			return ctrl.Result{RequeueAfter: 30 * time.Second}, err
+			return ctrl.Result{}, err
 		}
 
 		// Update status immediately with LoadBalancerId and initial status
 		nlb.Status.LoadBalancerId = lbId
 		nlb.Status.LoadBalancerStatus = "Provisioning"
+		setCreateFailure(nlb, "", nil)
+		meta.RemoveStatusCondition(&nlb.Status.Conditions, ConditionTypeError)
 		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, "Provisioning", "NLB instance is being created")
-		if err := r.Status().Update(ctx, nlb); err != nil {
+		if err := r.updateStatus(ctx, nlb); err != nil {
 			log.Error(err, "Failed to update NLB status")
 			return ctrl.Result{}, err
 		}
@@ -121,31 +439,77 @@ func (r *NLBReconciler) handleCreateOrUpdate(ctx context.Context, nlb *nlbv1.NLB
 		r.Recorder.Event(nlb, "Normal", ReasonReconcileSuccess, fmt.Sprintf("Successfully created NLB: %s", lbId))
 		log.Info("Successfully created NLB", "loadBalancerId", lbId)
 
-		// Requeue to wait for NLB to become Active
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		// Requeue to wait for NLB to become Active instead of blocking the worker
+		return ctrl.Result{RequeueAfter: r.activeCheckInterval()}, nil
+	}
+
+	// The instance cannot be found in another region, and resetting the status
+	// there would create a second instance and leak the original one
+	if live, target := nlb.Status.RegionId, resolveRegion(r.Clients, nlb.Spec.RegionId); live != "" && target != "" && live != target {
+		msg := fmt.Sprintf("NLB %s lives in region %s but the spec targets region %s, restore regionId to %s",
+			nlb.Status.LoadBalancerId, live, target, live)
+		if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeReady); cond != nil && cond.Reason == ReasonRegionMismatch && cond.Message == msg {
+			return ctrl.Result{}, nil
+		}
+		r.Recorder.Event(nlb, "Warning", ReasonRegionMismatch, msg)
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonRegionMismatch, msg)
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
 	}
 
 	// NLB already exists, sync its status
 	log.Info("Syncing NLB status", "loadBalancerId", nlb.Status.LoadBalancerId)
 
-	lb, err := r.NLBClient.GetLoadBalancer(ctx, nlb.Status.LoadBalancerId)
+	// A changed force-sync annotation asks for a fresh look at the cloud instance
+	if nlb.Annotations[nlbv1.ForceSyncAnnotation] != nlb.Status.ObservedForceSync {
+		r.NLBClient.InvalidateLoadBalancer(nlb.Status.LoadBalancerId)
+	}
+	// Fetched once per reconcile and passed to every drift handler below
+	lb, err := r.NLBClient.GetLoadBalancerCached(ctx, nlb.Status.LoadBalancerId)
 	if err != nil {
+		if provider.IsGetXipFailedError(err) {
+			return r.waitForAddress(ctx, nlb, err)
+		}
 		r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to get NLB: %v", err))
 		r.updateCondition(nlb, ConditionTypeError, metav1.ConditionTrue, ReasonReconcileError, err.Error())
-		if statusErr := r.Status().Update(ctx, nlb); statusErr != nil {
+		if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
 			log.Error(statusErr, "Failed to update NLB status after get error")
 		}
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, err
+		return ctrl.Result{}, err
 	}
 
 	if lb == nil {
+		// A not-found misreported during an API hiccup must not cause a duplicate
+		// instance, so confirm the deletion with a second, independent API first
+		if deleted, err := r.confirmDeleted(ctx, nlb.Status.LoadBalancerId); err != nil || !deleted {
+			if err != nil {
+				log.Error(err, "Failed to confirm external deletion of NLB")
+				return ctrl.Result{}, err
+			}
+			log.Info("GetLoadBalancerAttribute reported the NLB as not found but ListLoadBalancers still returns it, retrying")
+			return ctrl.Result{RequeueAfter: r.activeCheckInterval()}, nil
+		}
+
 		// Load balancer was deleted externally, reset status
 		log.Info("Load balancer was deleted externally, will recreate")
 		nlb.Status.LoadBalancerId = ""
 		nlb.Status.DNSName = ""
 		nlb.Status.LoadBalancerStatus = ""
+		nlb.Status.RegionId = ""
+		nlb.Status.CreateTime = ""
+		nlb.Status.CrossZoneEnabled = nil
+		nlb.Status.AddressIpVersion = ""
+		nlb.Status.Ipv6AddressType = ""
+		nlb.Status.SecurityGroupIds = nil
+		nlb.Status.PendingJobs = nil
 		nlb.Status.Eips = nil
-		if err := r.Status().Update(ctx, nlb); err != nil {
+		nlb.Status.ZoneMappingStatus = nil
+		nlb.Status.ActiveZones = nil
+		nlb.Status.BackendHealth = nil
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, "Recreating", "NLB instance was deleted outside the operator, recreating")
+		if err := r.updateStatus(ctx, nlb); err != nil {
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{Requeue: true}, nil
@@ -154,9 +518,17 @@ func (r *NLBReconciler) handleCreateOrUpdate(ctx context.Context, nlb *nlbv1.NLB
 	// Update status from cloud
 	nlb.Status.DNSName = tea.StringValue(lb.DNSName)
 	nlb.Status.LoadBalancerStatus = tea.StringValue(lb.LoadBalancerStatus)
+	nlb.Status.RegionId = tea.StringValue(lb.RegionId)
+	nlb.Status.CreateTime = tea.StringValue(lb.CreateTime)
+	nlb.Status.CrossZoneEnabled = lb.CrossZoneEnabled
+	nlb.Status.AddressIpVersion = tea.StringValue(lb.AddressIpVersion)
+	nlb.Status.Ipv6AddressType = tea.StringValue(lb.Ipv6AddressType)
+	nlb.Status.SecurityGroupIds = applySecurityGroupChanges(lb, nil, nil)
 
-	// Fill EIP information from ZoneMappings
+	// Fill EIP and per-zone address information from ZoneMappings
 	nlb.Status.Eips = nil
+	nlb.Status.ZoneMappingStatus = nil
+	nlb.Status.ActiveZones = nil
 	if lb.ZoneMappings != nil {
 		for _, zm := range lb.ZoneMappings {
 			if zm == nil {
@@ -165,39 +537,256 @@ func (r *NLBReconciler) handleCreateOrUpdate(ctx context.Context, nlb *nlbv1.NLB
 			eipInfo := nlbv1.EIPInfo{
 				ZoneId: tea.StringValue(zm.ZoneId),
 			}
+			zoneStatus := nlbv1.ZoneMappingStatus{
+				ZoneId:    tea.StringValue(zm.ZoneId),
+				VSwitchId: tea.StringValue(zm.VSwitchId),
+				Status:    tea.StringValue(zm.Status),
+			}
 			if zm.LoadBalancerAddresses != nil && len(zm.LoadBalancerAddresses) > 0 && zm.LoadBalancerAddresses[0] != nil {
-				eipInfo.IP = tea.StringValue(zm.LoadBalancerAddresses[0].PublicIPv4Address)
+				addr := zm.LoadBalancerAddresses[0]
+				eipInfo.IP = tea.StringValue(addr.PublicIPv4Address)
+				eipInfo.PrivateIPv4Address = tea.StringValue(addr.PrivateIPv4Address)
+				eipInfo.Ipv6Address = tea.StringValue(addr.Ipv6Address)
+				zoneStatus.PrivateIPv4Address = tea.StringValue(addr.PrivateIPv4Address)
+				zoneStatus.PublicIPv4Address = tea.StringValue(addr.PublicIPv4Address)
+				zoneStatus.Ipv6Address = tea.StringValue(addr.Ipv6Address)
+				zoneStatus.AllocationId = tea.StringValue(addr.AllocationId)
 			}
 			nlb.Status.Eips = append(nlb.Status.Eips, eipInfo)
+			nlb.Status.ZoneMappingStatus = append(nlb.Status.ZoneMappingStatus, zoneStatus)
+			if zoneStatus.Status == provider.ZoneStatusActive {
+				nlb.Status.ActiveZones = append(nlb.Status.ActiveZones, zoneStatus.ZoneId)
+			}
+		}
+	}
+
+	// The cloud instance is being deleted externally. Any update against it would
+	// fail, so wait until it is gone; the lb == nil branch above then recreates it.
+	if nlb.Status.LoadBalancerStatus == provider.LoadBalancerStatusDeleting {
+		log.Info("Cloud NLB is in Deleting state, waiting for it to disappear before recreating",
+			"loadBalancerId", nlb.Status.LoadBalancerId)
+		if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeReady); cond == nil || cond.Reason != ReasonCloudDeleting {
+			r.Recorder.Event(nlb, "Warning", ReasonCloudDeleting,
+				fmt.Sprintf("NLB %s is being deleted outside the operator, will recreate once gone", nlb.Status.LoadBalancerId))
+		}
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonCloudDeleting,
+			"NLB instance is being deleted in the cloud, waiting to recreate")
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			log.Error(err, "Failed to update NLB status")
+			return ctrl.Result{}, err
 		}
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
-	// Handle security groups
-	if err := r.handleSecurityGroups(ctx, nlb); err != nil {
-		r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to handle security groups: %v", err))
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, err
+	// A previous operation is still being applied; updates would be rejected
+	// with a state error, so wait for the instance to settle first.
+	if nlb.Status.LoadBalancerStatus == provider.LoadBalancerStatusConfiguring {
+		log.V(1).Info("NLB is Configuring, deferring updates", "loadBalancerId", nlb.Status.LoadBalancerId)
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonConfiguring,
+			"Waiting for NLB to leave Configuring state")
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			log.Error(err, "Failed to update NLB status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: configuringRequeueInterval}, nil
+	}
+
+	// Differences found from here on are drift unless the spec changed since the last pass
+	ctx, drift := withDriftRecorder(ctx, nlb)
+
+	// Converge tags with the spec
+	if err := r.handleTags(ctx, nlb); err != nil {
+		return r.handleUpdateError(ctx, nlb, "tags", err)
 	}
 
 	// If NLB is not yet Active, requeue to check again
-	if tea.StringValue(lb.LoadBalancerStatus) != "Active" {
+	if tea.StringValue(lb.LoadBalancerStatus) != provider.LoadBalancerStatusActive {
 		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, "Provisioning", fmt.Sprintf("NLB status: %s", tea.StringValue(lb.LoadBalancerStatus)))
-		if err := r.Status().Update(ctx, nlb); err != nil {
+		if err := r.updateStatus(ctx, nlb); err != nil {
 			log.Error(err, "Failed to update NLB status")
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.activeCheckInterval()}, nil
+	}
+
+	// NLB is Active, converge attributes that can only be changed on an Active instance
+	if err := r.handleDeletionProtection(ctx, nlb, lb); err != nil {
+		return r.handleUpdateError(ctx, nlb, "deletion protection", err)
 	}
 
-	// NLB is Active
+	if err := r.handleModificationProtection(ctx, nlb, lb); err != nil {
+		return r.handleUpdateError(ctx, nlb, "modification protection", err)
+	}
+
+	if err := r.handleAttributes(ctx, nlb, lb); err != nil {
+		return r.handleUpdateError(ctx, nlb, "load balancer attributes", err)
+	}
+
+	if err := r.handleBandwidthPackage(ctx, nlb, lb); err != nil {
+		return r.handleUpdateError(ctx, nlb, "bandwidth package", err)
+	}
+
+	if err := r.handleIpv6AddressType(ctx, nlb, lb); err != nil {
+		return r.handleUpdateError(ctx, nlb, "IPv6 address type", err)
+	}
+
+	r.handleZoneMappings(ctx, nlb, lb)
+
+	r.handleZoneHealth(nlb)
+
+	r.handleSecurityGroups(ctx, nlb, lb)
+
+	r.handleResourceGroup(ctx, nlb, lb)
+
+	r.handleBillingConfig(nlb, lb)
+
+	r.handleAddressType(nlb, lb)
+
+	// Updates leave the instance Configuring for a while after their job
+	// finished; report success only once it settled
+	status, settled, err := r.loadBalancerSettled(ctx, nlb)
+	if err != nil {
+		return r.handleUpdateError(ctx, nlb, "load balancer status", err)
+	}
+	if !settled {
+		log.V(1).Info("NLB has not settled after update, checking again shortly",
+			"loadBalancerId", nlb.Status.LoadBalancerId, "status", status)
+		r.updateDriftCondition(nlb, drift)
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonConfiguring,
+			fmt.Sprintf("Waiting for NLB to settle after update, status: %s", status))
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			log.Error(err, "Failed to update NLB status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: configuringRequeueInterval}, nil
+	}
+
+	r.refreshBackendHealth(ctx, nlb)
+
+	r.updateDriftCondition(nlb, drift)
 	r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionTrue, ReasonReconcileSuccess, "NLB reconciled successfully")
+	nlb.Status.ObservedGeneration = nlb.Generation
+	now := metav1.Now()
+	nlb.Status.LastReconcileTime = &now
+	if v := nlb.Annotations[nlbv1.ForceSyncAnnotation]; v != nlb.Status.ObservedForceSync {
+		r.Recorder.Event(nlb, "Normal", "ForceSync", fmt.Sprintf("Completed forced drift reconcile (force-sync=%q)", v))
+		nlb.Status.ObservedForceSync = v
+	}
 
-	if err := r.Status().Update(ctx, nlb); err != nil {
+	if err := r.updateStatus(ctx, nlb); err != nil {
 		log.Error(err, "Failed to update NLB status")
 		return ctrl.Result{}, err
 	}
 
 	r.Recorder.Event(nlb, "Normal", ReasonReconcileSuccess, "Successfully reconciled NLB")
-	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	return ctrl.Result{RequeueAfter: r.resyncPeriod(nlb)}, nil
+}
+
+// adoptExisting binds the CR to an existing cloud NLB, either by Spec.LoadBalancerId
+// or, when Spec.AdoptExistingByName is set, by looking up Spec.LoadBalancerName in the VPC.
+// adopted reports whether the caller must return result/err instead of creating a new NLB.
+func (r *NLBReconciler) adoptExisting(ctx context.Context, nlb *nlbv1.NLB) (result ctrl.Result, adopted bool, err error) {
+	log := klog.FromContext(ctx)
+
+	lbId := nlb.Spec.LoadBalancerId
+	if lbId != "" {
+		lb, err := r.NLBClient.GetLoadBalancer(ctx, lbId)
+		if err != nil {
+			r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to get NLB %s for adoption: %v", lbId, err))
+			return ctrl.Result{}, true, err
+		}
+		if lb == nil {
+			// Never fall back to creating a new instance when an explicit ID was given.
+			msg := fmt.Sprintf("NLB %s specified in spec.loadBalancerId does not exist", lbId)
+			r.Recorder.Event(nlb, "Warning", "AdoptFailed", msg)
+			r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, "AdoptFailed", msg)
+			if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
+				log.Error(statusErr, "Failed to update NLB status")
+				return ctrl.Result{}, true, statusErr
+			}
+			return ctrl.Result{RequeueAfter: r.resyncPeriod(nlb)}, true, nil
+		}
+	} else {
+		// An instance carrying this CR's provenance tags was created by us before the
+		// status write recording its ID was lost; bind to it instead of creating another.
+		lbId, err = r.findByProvenance(ctx, nlb)
+		if err != nil {
+			r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to look up NLB by provenance tags: %v", err))
+			return ctrl.Result{}, true, err
+		}
+		if lbId == "" && nlb.Spec.AdoptExistingByName && nlb.Spec.LoadBalancerName != "" {
+			lbId, err = r.NLBClient.FindLoadBalancerByName(ctx, nlb.Spec.VpcId, nlb.Spec.LoadBalancerName)
+			if err != nil {
+				r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to look up NLB by name: %v", err))
+				return ctrl.Result{}, true, err
+			}
+			if lbId == "" {
+				log.Info("No existing NLB found by name, will create", "name", nlb.Spec.LoadBalancerName)
+				return ctrl.Result{}, false, nil
+			}
+			owner, err := r.provenanceOwner(ctx, lbId)
+			if err != nil {
+				return ctrl.Result{}, true, err
+			}
+			if owner != "" && owner != nlb.Namespace+"/"+nlb.Name {
+				// Never steal an instance this operator created for another CR.
+				msg := fmt.Sprintf("NLB %s named %q is managed by NLB %s", lbId, nlb.Spec.LoadBalancerName, owner)
+				r.Recorder.Event(nlb, "Warning", "AdoptFailed", msg)
+				r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, "AdoptFailed", msg)
+				if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
+					log.Error(statusErr, "Failed to update NLB status")
+					return ctrl.Result{}, true, statusErr
+				}
+				return ctrl.Result{RequeueAfter: r.resyncPeriod(nlb)}, true, nil
+			}
+		}
+		if lbId == "" {
+			return ctrl.Result{}, false, nil
+		}
+	}
+
+	log.Info("Adopting existing NLB", "loadBalancerId", lbId)
+	nlb.Status.LoadBalancerId = lbId
+	if err := r.updateStatus(ctx, nlb); err != nil {
+		log.Error(err, "Failed to update NLB status")
+		return ctrl.Result{}, true, err
+	}
+	r.Recorder.Event(nlb, "Normal", "Adopted", fmt.Sprintf("Adopted existing NLB: %s", lbId))
+
+	// Requeue to sync status from the adopted instance
+	return ctrl.Result{Requeue: true}, true, nil
+}
+
+// findByProvenance returns the ID of the instance tagged with the provenance tags
+// of nlb, or "" when there is none.
+func (r *NLBReconciler) findByProvenance(ctx context.Context, nlb *nlbv1.NLB) (string, error) {
+	tags := make(map[string]string)
+	for _, t := range r.provenanceTags(nlb) {
+		tags[t.Key] = t.Value
+	}
+	lbs, err := r.NLBClient.ListLoadBalancers(ctx, provider.LoadBalancerFilter{Tags: tags})
+	if err != nil {
+		return "", err
+	}
+	for _, lb := range lbs {
+		if lb.LoadBalancerStatus != provider.LoadBalancerStatusDeleting {
+			return lb.LoadBalancerId, nil
+		}
+	}
+	return "", nil
+}
+
+// provenanceOwner returns "<namespace>/<name>" of the NLB CR recorded in the
+// provenance tags of lbId when the instance was created by this operator, else "".
+func (r *NLBReconciler) provenanceOwner(ctx context.Context, lbId string) (string, error) {
+	tags, err := r.NLBClient.ListTagResources(ctx, lbId)
+	if err != nil {
+		return "", err
+	}
+	if tags[nlbv1.ManagedByTagKey] != r.operatorId() {
+		return "", nil
+	}
+	return tags[nlbv1.CRNamespaceTagKey] + "/" + tags[nlbv1.CRNameTagKey], nil
 }
 
 // handleDeletion handles the deletion of NLB resources.
@@ -207,7 +796,10 @@ func (r *NLBReconciler) handleCreateOrUpdate(ctx context.Context, nlb *nlbv1.NLB
 //  3. 调 GetLoadBalancer 确认云端状态：
 //     - NotFound  -> 移除 finalizer 完成删除；
 //     - Deleting  -> Requeue 等待；
-//     - 其它状态 -> 调 DeleteLoadBalancer 后 Requeue 等待下一轮 Get 再确认。
+//     - 其它状态 -> 调 DeleteLoadBalancer（轮询 Get 确认云端消失）后移除 finalizer，失败则 Requeue。
+//  4. 失败后按指数退避重试；连续失败 DeletionStuckAttempts 次后设置 DeletionStuck 条件并改为按
+//     DeletionStuckRetryInterval 重试；持续失败超过 DeletionTimeout 时产生告警事件，开启
+//     ForceRemoveFinalizer 时放弃云端实例并移除 finalizer。
 func (r *NLBReconciler) handleDeletion(ctx context.Context, nlb *nlbv1.NLB) (ctrl.Result, error) {
 	log := klog.FromContext(ctx)
 
@@ -227,6 +819,21 @@ func (r *NLBReconciler) handleDeletion(ctx context.Context, nlb *nlbv1.NLB) (ctr
 		return ctrl.Result{}, nil
 	}
 
+	// 受删除保护的实例需先在 spec 中关闭删除保护；修改 spec 会触发重新调和
+	if r.HonorDeletionProtection && nlb.Spec.DeletionProtection != nil && nlb.Spec.DeletionProtection.Enabled {
+		msg := "Deletion is blocked while spec.deletionProtection.enabled is true, set it to false to delete the NLB"
+		if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeReady); cond == nil || cond.Reason != ReasonDeletionBlocked {
+			r.Recorder.Event(nlb, "Warning", ReasonDeletionBlocked, msg)
+		}
+		log.Info("Deletion blocked by deletion protection", "loadBalancerId", nlb.Status.LoadBalancerId)
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonDeletionBlocked, msg)
+		if err := r.updateStatus(ctx, nlb); err != nil {
+			log.Error(err, "Failed to update NLB status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// 2. 检查是否还有 Listener CR 引用此 NLB，若存在则等待其全部删除
 	// 即使 Listener 处于 Deleting 状态，其 finalizer 可能还需要 NLB 存在才能完成 DeleteListener API 调用
 	listenerList := &nlbv1.ListenerList{}
@@ -236,10 +843,14 @@ func (r *NLBReconciler) handleDeletion(ctx context.Context, nlb *nlbv1.NLB) (ctr
 	}
 
 	var referencingListeners int
+	managedListenerIds := make(map[string]bool, len(listenerList.Items))
 	for _, lsn := range listenerList.Items {
-		if lsn.Spec.LoadBalancerRef == nlb.Name {
+		if lsn.Namespace == nlb.Namespace && lsn.Spec.LoadBalancerRef == nlb.Name {
 			referencingListeners++
 		}
+		if lsn.Status.ListenerId != "" {
+			managedListenerIds[lsn.Status.ListenerId] = true
+		}
 	}
 
 	if referencingListeners > 0 {
@@ -255,7 +866,7 @@ func (r *NLBReconciler) handleDeletion(ctx context.Context, nlb *nlbv1.NLB) (ctr
 	if nlb.Status.LoadBalancerStatus != "Deleting" {
 		nlb.Status.LoadBalancerStatus = "Deleting"
 		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, "Deleting", "NLB is being deleted")
-		if err := r.Status().Update(ctx, nlb); err != nil {
+		if err := r.updateStatus(ctx, nlb); err != nil {
 			log.Error(err, "Failed to update NLB status to Deleting")
 			// Continue with deletion even if status update fails
 		}
@@ -274,7 +885,7 @@ func (r *NLBReconciler) handleDeletion(ctx context.Context, nlb *nlbv1.NLB) (ctr
 			return ctrl.Result{}, nil
 		}
 		r.Recorder.Event(nlb, "Warning", ReasonDeletionError, fmt.Sprintf("Failed to get NLB during deletion: %v", err))
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, err
+		return r.deletionFailed(ctx, nlb, err)
 	}
 
 	// 云端不存在（GetLoadBalancer 在 ResourceNotFound 时返回 nil, nil）
@@ -297,39 +908,959 @@ func (r *NLBReconciler) handleDeletion(ctx context.Context, nlb *nlbv1.NLB) (ctr
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	// 非 Deleting 状态，调用 Delete
+	// 4. 清理云端残留的 Listener（例如控制台创建的），避免 DeleteLoadBalancer 失败
+	if err := r.deleteOrphanListeners(ctx, nlb, managedListenerIds); err != nil {
+		r.Recorder.Event(nlb, "Warning", ReasonDeletionError, fmt.Sprintf("Failed to delete orphan listeners: %v", err))
+		return r.deletionFailed(ctx, nlb, err)
+	}
+
+	// 非 Deleting 状态，调用 Delete；DeleteLoadBalancer 会轮询 GetLoadBalancer 直到云端确认消失
 	if err := r.NLBClient.DeleteLoadBalancer(ctx, nlb.Status.LoadBalancerId); err != nil {
 		if isNotFoundError(err) {
-			controllerutil.RemoveFinalizer(nlb, NLBFinalizer)
-			if uerr := r.Update(ctx, nlb); uerr != nil {
-				log.Error(uerr, "Failed to remove finalizer after Delete NotFound")
-				return ctrl.Result{}, uerr
-			}
-			return ctrl.Result{}, nil
+			// NotFound 可能来自子资源，下一轮 Get 确认云端消失后再移除 finalizer
+			log.Info("DeleteLoadBalancer reported NotFound, confirming with GetLoadBalancer",
+				"loadBalancerId", nlb.Status.LoadBalancerId, "error", err.Error())
+			return ctrl.Result{Requeue: true}, nil
 		}
 		r.Recorder.Event(nlb, "Warning", ReasonDeletionError, fmt.Sprintf("Failed to delete NLB: %v", err))
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		return r.deletionFailed(ctx, nlb, err)
 	}
 
-	// Delete 调用成功（可能是异步），不立即移除 finalizer，
-	// 等下一轮 Reconcile 再 Get 确认云端真正消失后才移除。
+	// DeleteLoadBalancer 仅在云端确认消失后返回 nil，此时才移除 finalizer
 	r.Recorder.Event(nlb, "Normal", ReasonDeletionSuccess,
-		fmt.Sprintf("Issued DeleteLoadBalancer for NLB: %s, waiting cloud confirmation", nlb.Status.LoadBalancerId))
-	log.Info("Issued DeleteLoadBalancer, waiting for cloud to disappear",
-		"loadBalancerId", nlb.Status.LoadBalancerId)
-	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		fmt.Sprintf("Deleted NLB: %s", nlb.Status.LoadBalancerId))
+	log.Info("Cloud NLB deleted, removing finalizer", "loadBalancerId", nlb.Status.LoadBalancerId)
+	controllerutil.RemoveFinalizer(nlb, NLBFinalizer)
+	if err := r.Update(ctx, nlb); err != nil {
+		log.Error(err, "Failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// deletionFailed is called when a step of handleDeletion fails with err. The
+// deletion is retried with an exponential backoff; once it failed
+// DeletionStuckAttempts times in a row the DeletionStuck condition is set and it
+// is retried every DeletionStuckRetryInterval. Once the NLB has been deleting for
+// longer than DeletionTimeout it raises a warning and, with ForceRemoveFinalizer,
+// gives up on the cloud instance: the finalizer is removed and the orphaned
+// LoadBalancerId is recorded in the OrphanedLoadBalancerAnnotation and the event
+// for manual cleanup.
+func (r *NLBReconciler) deletionFailed(ctx context.Context, nlb *nlbv1.NLB, err error) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+	lbId := nlb.Status.LoadBalancerId
+
+	var elapsed time.Duration
+	if nlb.DeletionTimestamp != nil {
+		elapsed = time.Since(nlb.DeletionTimestamp.Time)
+	}
+	if r.DeletionTimeout <= 0 || nlb.DeletionTimestamp == nil || elapsed < r.DeletionTimeout {
+		return r.retryDeletion(ctx, nlb, err)
+	}
+
+	if !r.ForceRemoveFinalizer {
+		r.Recorder.Eventf(nlb, corev1.EventTypeWarning, ReasonDeletionTimeout,
+			"Deletion of NLB %s has been failing for %s, the finalizer is kept until it succeeds: %v",
+			lbId, elapsed.Round(time.Second), err)
+		return r.retryDeletion(ctx, nlb, err)
+	}
+
+	log.Error(err, "Deletion timed out, removing finalizer and orphaning cloud NLB",
+		"loadBalancerId", lbId, "elapsed", elapsed.Round(time.Second))
+	r.Recorder.Eventf(nlb, corev1.EventTypeWarning, ReasonDeletionTimeout,
+		"Deletion of NLB %s has been failing for %s, removing finalizer; the cloud instance is orphaned and must be deleted manually: %v",
+		lbId, elapsed.Round(time.Second), err)
+	if nlb.Annotations == nil {
+		nlb.Annotations = make(map[string]string)
+	}
+	nlb.Annotations[nlbv1.OrphanedLoadBalancerAnnotation] = lbId
+	controllerutil.RemoveFinalizer(nlb, NLBFinalizer)
+	if uerr := r.Update(ctx, nlb); uerr != nil {
+		log.Error(uerr, "Failed to remove finalizer after deletion timeout")
+		return ctrl.Result{}, uerr
+	}
+	return ctrl.Result{}, nil
+}
+
+// retryDeletion records a failed deletion attempt of nlb and returns when to
+// retry. The DeletionStuck condition is persisted only when it is first set, so
+// status updates do not trigger retries ahead of the backoff.
+func (r *NLBReconciler) retryDeletion(ctx context.Context, nlb *nlbv1.NLB, err error) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+	key := client.ObjectKeyFromObject(nlb)
+
+	d := r.deletionBackoff.next(key)
+	attempts := r.deletionBackoff.attempts(key)
+	if r.DeletionStuckAttempts <= 0 || attempts < r.DeletionStuckAttempts {
+		log.Error(err, "Deletion failed, backing off", "attempts", attempts, "requeueAfter", d)
+		return ctrl.Result{RequeueAfter: d}, nil
+	}
+
+	interval := r.DeletionStuckRetryInterval
+	if interval <= 0 {
+		interval = defaultDeletionStuckRetryInterval
+	}
+	log.Error(err, "Deletion is stuck, retrying at a slower cadence", "attempts", attempts, "requeueAfter", interval)
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeDeletionStuck); cond == nil || cond.Status != metav1.ConditionTrue {
+		r.Recorder.Eventf(nlb, corev1.EventTypeWarning, ConditionTypeDeletionStuck,
+			"Deletion of NLB %s failed %d times in a row, retrying every %s: %v",
+			nlb.Status.LoadBalancerId, attempts, interval, err)
+		r.updateCondition(nlb, ConditionTypeDeletionStuck, metav1.ConditionTrue, ReasonDeletionError,
+			fmt.Sprintf("Deletion failed %d times in a row: %v", attempts, err))
+		if uerr := r.updateStatus(ctx, nlb); uerr != nil {
+			log.Error(uerr, "Failed to update NLB status")
+		}
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// handleSecurityGroups converges security group membership with Spec.SecurityGroupIds.
+// Groups no longer in the spec are left only if the operator joined them before
+// (Status.ManagedSecurityGroupIds), so groups attached outside the operator are kept.
+// API rejections (e.g. the instance does not support security groups) are reported
+// through the SecurityGroupsSynced condition rather than failing the reconcile.
+func (r *NLBReconciler) handleSecurityGroups(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) {
+	log := klog.FromContext(ctx)
+
+	toJoin, toLeave := diffSecurityGroups(nlb, lb)
+
+	if len(toJoin) > 0 || len(toLeave) > 0 {
+		recordDriftCorrection(ctx, "securityGroupIds", tea.StringSliceValue(lb.SecurityGroupIds), nlb.Spec.SecurityGroupIds)
+	}
+	if len(toJoin) > 0 {
+		log.Info("Joining security groups", "securityGroupIds", toJoin)
+		if err := r.NLBClient.JoinSecurityGroup(ctx, nlb.Status.LoadBalancerId, toJoin); err != nil {
+			r.securityGroupsFailed(nlb, "join", err)
+			return
+		}
+	}
+	if len(toLeave) > 0 {
+		log.Info("Leaving security groups", "securityGroupIds", toLeave)
+		if err := r.NLBClient.LeaveSecurityGroup(ctx, nlb.Status.LoadBalancerId, toLeave); err != nil {
+			r.securityGroupsFailed(nlb, "leave", err)
+			return
+		}
+	}
+	if len(toJoin) > 0 || len(toLeave) > 0 {
+		r.Recorder.Event(nlb, "Normal", "SecurityGroupsUpdated",
+			fmt.Sprintf("Joined %v, left %v", toJoin, toLeave))
+		nlb.Status.SecurityGroupIds = applySecurityGroupChanges(lb, toJoin, toLeave)
+	}
+
+	nlb.Status.ManagedSecurityGroupIds = append([]string(nil), nlb.Spec.SecurityGroupIds...)
+	r.updateCondition(nlb, ConditionTypeSecurityGroupsSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Security groups match spec")
+}
+
+// handleResourceGroup moves the instance to Spec.ResourceGroupId when it lives in
+// another resource group. An empty spec leaves the live group alone. Failures, most
+// commonly a credential lacking permission on the target group, are reported
+// through the ResourceGroupSynced condition rather than failing the reconcile.
+func (r *NLBReconciler) handleResourceGroup(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) {
+	desired := nlb.Spec.ResourceGroupId
+	if desired == "" {
+		return
+	}
+	live := tea.StringValue(lb.ResourceGroupId)
+	if live == desired {
+		r.updateCondition(nlb, ConditionTypeResourceGroupSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Resource group matches spec")
+		return
+	}
+
+	recordDriftCorrection(ctx, "resourceGroupId", live, desired)
+	log := klog.FromContext(ctx)
+	log.Info("Moving NLB to resource group", "from", live, "to", desired)
+	if err := r.NLBClient.MoveResourceGroup(ctx, nlb.Status.LoadBalancerId, desired); err != nil {
+		reason := "MoveFailed"
+		if code := provider.ErrorCode(err); strings.Contains(code, "Forbidden") || strings.Contains(code, "NoPermission") {
+			reason = "PermissionDenied"
+		}
+		log.Error(err, "Failed to move NLB to resource group", "resourceGroupId", desired)
+		r.Recorder.Event(nlb, "Warning", "ResourceGroupMoveFailed",
+			fmt.Sprintf("Failed to move NLB from resource group %s to %s: %v", live, desired, err))
+		r.updateCondition(nlb, ConditionTypeResourceGroupSynced, metav1.ConditionFalse, reason, err.Error())
+		return
+	}
+
+	r.Recorder.Event(nlb, "Normal", "ResourceGroupMoved",
+		fmt.Sprintf("Moved NLB from resource group %s to %s", live, desired))
+	r.updateCondition(nlb, ConditionTypeResourceGroupSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Moved to resource group "+desired)
+}
+
+// handleAddressType reports whether Spec.AddressType matches the live instance.
+// The operator does not change the network type through
+// UpdateLoadBalancerAddressTypeConfig, and the CRD rejects edits of the field,
+// so a mismatch, e.g. on an adopted instance or a CR edited before the
+// rule was installed, is surfaced as an ImmutableField condition.
+func (r *NLBReconciler) handleAddressType(nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) {
+	live := tea.StringValue(lb.AddressType)
+	desired := nlb.Spec.AddressType
+	if live == "" || desired == "" || live == desired {
+		r.updateCondition(nlb, ConditionTypeAddressTypeSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Address type matches spec")
+		return
+	}
+
+	msg := fmt.Sprintf("addressType %s differs from the instance's %s and changing it is not supported by this operator; recreate the NLB to change the network type", desired, live)
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeAddressTypeSynced); cond == nil || cond.Message != msg {
+		r.Recorder.Event(nlb, "Warning", ReasonImmutableField, msg)
+	}
+	r.updateCondition(nlb, ConditionTypeAddressTypeSynced, metav1.ConditionFalse, ReasonImmutableField, msg)
+}
+
+// handleBillingConfig reports whether Spec.BillingConfig matches the live instance.
+// The billing method cannot be changed in place, so a mismatch, e.g. on an adopted
+// instance, is surfaced as an ImmutableField condition rather than converged.
+func (r *NLBReconciler) handleBillingConfig(nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) {
+	if nlb.Spec.BillingConfig == nil {
+		meta.RemoveStatusCondition(&nlb.Status.Conditions, ConditionTypeBillingConfigSynced)
+		return
+	}
+	var live string
+	if lb.LoadBalancerBillingConfig != nil {
+		live = tea.StringValue(lb.LoadBalancerBillingConfig.PayType)
+	}
+	desired := nlb.Spec.BillingConfig.PayType
+	if live == "" || live == desired {
+		r.updateCondition(nlb, ConditionTypeBillingConfigSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Billing configuration matches spec")
+		return
+	}
+
+	msg := fmt.Sprintf("billingConfig.payType %s differs from the instance's %s and can only be set at creation", desired, live)
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeBillingConfigSynced); cond == nil || cond.Message != msg {
+		r.Recorder.Event(nlb, "Warning", ReasonImmutableField, msg)
+	}
+	r.updateCondition(nlb, ConditionTypeBillingConfigSynced, metav1.ConditionFalse, ReasonImmutableField, msg)
+}
+
+// applySecurityGroupChanges returns the sorted security groups of lb after joining
+// toJoin and leaving toLeave, sparing a describe call after the change.
+func applySecurityGroupChanges(lb *nlbsdk.GetLoadBalancerAttributeResponseBody, toJoin, toLeave []string) []string {
+	leave := make(map[string]bool, len(toLeave))
+	for _, id := range toLeave {
+		leave[id] = true
+	}
+	set := make(map[string]bool, len(lb.SecurityGroupIds)+len(toJoin))
+	for _, id := range append(tea.StringSliceValue(lb.SecurityGroupIds), toJoin...) {
+		if id != "" && !leave[id] {
+			set[id] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// diffSecurityGroups returns the security groups to join and, among those previously
+// joined by the operator, the ones to leave.
+func diffSecurityGroups(nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) (toJoin, toLeave []string) {
+	live := make(map[string]bool, len(lb.SecurityGroupIds))
+	for _, id := range lb.SecurityGroupIds {
+		live[tea.StringValue(id)] = true
+	}
+	desired := make(map[string]bool, len(nlb.Spec.SecurityGroupIds))
+	for _, id := range nlb.Spec.SecurityGroupIds {
+		desired[id] = true
+		if !live[id] {
+			toJoin = append(toJoin, id)
+		}
+	}
+	for _, id := range nlb.Status.ManagedSecurityGroupIds {
+		if !desired[id] && live[id] {
+			toLeave = append(toLeave, id)
+		}
+	}
+	return toJoin, toLeave
+}
+
+func (r *NLBReconciler) securityGroupsFailed(nlb *nlbv1.NLB, op string, err error) {
+	r.Recorder.Event(nlb, "Warning", "SecurityGroupUpdateFailed", fmt.Sprintf("Failed to %s security groups: %v", op, err))
+	r.updateCondition(nlb, ConditionTypeSecurityGroupsSynced, metav1.ConditionFalse, "SecurityGroupUpdateFailed",
+		fmt.Sprintf("Failed to %s security groups: %v", op, err))
+}
+
+// deleteOrphanListeners enumerates the listeners still attached to the NLB once all
+// of its Listener CRs are gone. Listeners still tracked by a Listener CR, e.g. of
+// another NLB CR bound to the same instance, are never touched: deletion waits
+// for them. The others are orphans. With Spec.DeleteOrphanListeners they are
+// deleted; otherwise they are only reported, since DeleteLoadBalancer may then
+// fail. The report is made once per orphan set through the OrphanListeners
+// condition, not on every deletion retry.
+func (r *NLBReconciler) deleteOrphanListeners(ctx context.Context, nlb *nlbv1.NLB, managedListenerIds map[string]bool) error {
+	log := klog.FromContext(ctx)
+
+	listeners, err := r.NLBClient.ListLoadBalancerListeners(ctx, nlb.Status.LoadBalancerId)
+	if err != nil {
+		return err
+	}
+
+	var orphans, managed []string
+	for _, lsn := range listeners {
+		log.Info("Found listener on NLB being deleted", "listenerId", lsn.ListenerId,
+			"port", lsn.ListenerPort, "protocol", lsn.ListenerProtocol, "operatorManaged", managedListenerIds[lsn.ListenerId])
+		if managedListenerIds[lsn.ListenerId] {
+			managed = append(managed, lsn.ListenerId)
+		} else {
+			orphans = append(orphans, lsn.ListenerId)
+		}
+	}
+	if len(managed) > 0 {
+		sort.Strings(managed)
+		return fmt.Errorf("listeners %v are still managed by Listener CRs, waiting for them to be deleted", managed)
+	}
+	if len(orphans) == 0 {
+		meta.RemoveStatusCondition(&nlb.Status.Conditions, ConditionTypeOrphanListeners)
+		return nil
+	}
+
+	if !nlb.Spec.DeleteOrphanListeners {
+		sort.Strings(orphans)
+		msg := fmt.Sprintf("NLB still has listeners %v not managed by Listener CRs; set spec.deleteOrphanListeners to remove them", orphans)
+		if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeOrphanListeners); cond == nil ||
+			cond.Status != metav1.ConditionTrue || cond.Message != msg {
+			r.Recorder.Event(nlb, "Warning", ConditionTypeOrphanListeners, msg)
+			r.updateCondition(nlb, ConditionTypeOrphanListeners, metav1.ConditionTrue, ConditionTypeOrphanListeners, msg)
+			if err := r.updateStatus(ctx, nlb); err != nil {
+				log.Error(err, "Failed to update NLB status")
+			}
+		}
+		return nil
+	}
+
+	if err := r.deleteListeners(ctx, orphans); err != nil {
+		return err
+	}
+	r.Recorder.Event(nlb, "Normal", "OrphanListenersDeleted", fmt.Sprintf("Deleted listeners %v before deleting NLB", orphans))
+	return nil
+}
+
+// setCreateFailure records the structured details of a failed create in the
+// status, so automation can branch on them without parsing the condition message.
+// An empty reason clears them.
+func setCreateFailure(nlb *nlbv1.NLB, reason string, err error) {
+	nlb.Status.FailureReason = reason
+	nlb.Status.FailureCode = provider.ErrorCode(err)
+	nlb.Status.FailureRequestId = provider.RequestIdFromError(err)
+}
+
+// markCreateFailed records a non-retryable create failure: the Error condition keeps
+// ReasonCreateFailed so creation is skipped until the spec changes, while the Ready
+// condition carries the more specific reason.
+func (r *NLBReconciler) markCreateFailed(ctx context.Context, nlb *nlbv1.NLB, reason, msg string) (ctrl.Result, error) {
+	r.Recorder.Event(nlb, "Warning", ReasonCreateFailed, fmt.Sprintf("Failed to create NLB, not retrying until spec changes: %s", msg))
+	nlb.Status.LoadBalancerStatus = LoadBalancerStatusCreateFailed
+	r.updateCondition(nlb, ConditionTypeError, metav1.ConditionTrue, ReasonCreateFailed, msg)
+	r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, reason, "NLB creation failed with a non-retryable error")
+	if err := r.updateStatus(ctx, nlb); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to update NLB status after create error")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// validateZones checks Spec.ZoneMappings against the zones supporting NLB in the
+// region and returns a message naming the unsupported ones, or "". A failing
+// DescribeZones call does not block creation.
+func (r *NLBReconciler) validateZones(ctx context.Context, nlb *nlbv1.NLB) string {
+	zones, err := r.NLBClient.DescribeZones(ctx)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to describe NLB zones, skipping zone validation")
+		return ""
+	}
+	supported := make(map[string]bool, len(zones))
+	for _, z := range zones {
+		supported[z] = true
+	}
+	var unsupported []string
+	for _, zm := range nlb.Spec.ZoneMappings {
+		if !supported[zm.ZoneId] {
+			unsupported = append(unsupported, zm.ZoneId)
+		}
+	}
+	if len(unsupported) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("zones %v in zoneMappings do not support NLB in this region (supported: %v)", unsupported, zones)
+}
+
+// classifyCreateError maps well-known CreateLoadBalancer error codes to a condition
+// reason and a hint naming the spec field to fix.
+func classifyCreateError(nlb *nlbv1.NLB, err error) (reason, hint string) {
+	code := provider.ErrorCode(err)
+	switch {
+	case strings.Contains(code, "VSwitch"):
+		return "InvalidVSwitch", fmt.Sprintf("check that every zoneMappings[].vSwitchId exists, belongs to VPC %s and is in the listed zone", nlb.Spec.VpcId)
+	case strings.Contains(code, "Vpc"):
+		return "InvalidVpc", fmt.Sprintf("check that VPC %s exists in the NLB region", nlb.Spec.VpcId)
+	case strings.Contains(code, "Zone"):
+		return ReasonZoneNotSupported, "check that every zoneMappings[].zoneId supports NLB in the region"
+	case strings.Contains(code, "BandwidthPackage"):
+		return "InvalidBandwidthPackage", fmt.Sprintf("check that bandwidth package %s exists in the NLB region", nlb.Spec.BandwidthPackageId)
+	case isQuotaErrorCode(code):
+		return ReasonQuotaExceeded, "raise the NLB quota or delete unused instances"
+	case strings.Contains(code, "Forbidden"), strings.Contains(code, "NoPermission"):
+		return "PermissionDenied", "grant the operator's credential permission to create NLB instances"
+	}
+	return ReasonCreateFailed, ""
+}
+
+// isQuotaErrorCode reports whether an OpenAPI error code denotes an exceeded quota.
+func isQuotaErrorCode(code string) bool {
+	return strings.Contains(code, "QuotaExceeded") || strings.Contains(code, "ResourceQuotaLimit")
+}
+
+// deleteListeners deletes listeners concurrently with at most
+// orphanListenerDeleteWorkers in flight, since each DeleteListener waits for its
+// own async job. Errors are aggregated in input order.
+func (r *NLBReconciler) deleteListeners(ctx context.Context, listenerIds []string) error {
+	errs := make([]error, len(listenerIds))
+	sem := make(chan struct{}, orphanListenerDeleteWorkers)
+	var wg sync.WaitGroup
+	for i, id := range listenerIds {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := r.NLBClient.DeleteListener(provider.ForkJobObserver(ctx), id); err != nil {
+				errs[i] = fmt.Errorf("listener %s: %w", id, err)
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	return utilerrors.NewAggregate(errs)
+}
+
+// handleDeletionProtection converges the live deletion protection setting with
+// Spec.DeletionProtection. A nil spec leaves the cloud setting untouched.
+// This only runs on the create/update path; handleDeletion disables protection
+// through DeleteLoadBalancer and never reaches here.
+func (r *NLBReconciler) handleDeletionProtection(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) error {
+	if nlb.Spec.DeletionProtection == nil {
+		return nil
+	}
+
+	var liveEnabled bool
+	if lb.DeletionProtectionConfig != nil {
+		liveEnabled = tea.BoolValue(lb.DeletionProtectionConfig.Enabled)
+	}
+	if liveEnabled == nlb.Spec.DeletionProtection.Enabled {
+		return nil
+	}
+
+	recordDriftCorrection(ctx, "deletionProtection", liveEnabled, nlb.Spec.DeletionProtection.Enabled)
+	log := klog.FromContext(ctx)
+	log.Info("Correcting deletion protection drift", "live", liveEnabled, "desired", nlb.Spec.DeletionProtection.Enabled)
+	if err := r.NLBClient.UpdateLoadBalancerProtection(ctx, nlb.Status.LoadBalancerId,
+		nlb.Spec.DeletionProtection.Enabled, nlb.Spec.DeletionProtection.Reason); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(nlb, "Normal", "DeletionProtectionDrift",
+		fmt.Sprintf("Corrected deletion protection from %t to %t", liveEnabled, nlb.Spec.DeletionProtection.Enabled))
+	return nil
+}
+
+// handleModificationProtection converges the live modification protection status with
+// Spec.ModificationProtection. A nil spec leaves the cloud setting untouched.
+func (r *NLBReconciler) handleModificationProtection(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) error {
+	if nlb.Spec.ModificationProtection == nil {
+		return nil
+	}
+
+	var liveStatus string
+	if lb.ModificationProtectionConfig != nil {
+		liveStatus = tea.StringValue(lb.ModificationProtectionConfig.Status)
+	}
+	if liveStatus == nlb.Spec.ModificationProtection.Status {
+		return nil
+	}
+
+	recordDriftCorrection(ctx, "modificationProtection", liveStatus, nlb.Spec.ModificationProtection.Status)
+	log := klog.FromContext(ctx)
+	log.Info("Correcting modification protection drift", "live", liveStatus, "desired", nlb.Spec.ModificationProtection.Status)
+	if err := r.NLBClient.UpdateLoadBalancerModificationProtection(ctx, nlb.Status.LoadBalancerId,
+		nlb.Spec.ModificationProtection.Status, nlb.Spec.ModificationProtection.Reason); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(nlb, "Normal", "ModificationProtectionDrift",
+		fmt.Sprintf("Corrected modification protection from %q to %q", liveStatus, nlb.Spec.ModificationProtection.Status))
+	return nil
+}
+
+// handleBandwidthPackage attaches, replaces or detaches the shared bandwidth package
+// so it matches Spec.BandwidthPackageId. A package attached outside the operator is
+// only detached when the spec names a different one.
+func (r *NLBReconciler) handleBandwidthPackage(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) error {
+	log := klog.FromContext(ctx)
+
+	toDetach, toAttach := diffBandwidthPackage(nlb, lb)
+	if toDetach != "" || toAttach != "" {
+		recordDriftCorrection(ctx, "bandwidthPackageId", toDetach, toAttach)
+	}
+	if toDetach != "" {
+		log.Info("Detaching bandwidth package", "bandwidthPackageId", toDetach)
+		if err := r.NLBClient.DetachCommonBandwidthPackage(ctx, nlb.Status.LoadBalancerId, toDetach); err != nil {
+			return err
+		}
+		r.Recorder.Event(nlb, "Normal", "BandwidthPackageDetached", fmt.Sprintf("Detached bandwidth package %s", toDetach))
+	}
+	if toAttach != "" {
+		log.Info("Attaching bandwidth package", "bandwidthPackageId", toAttach)
+		if err := r.NLBClient.AttachCommonBandwidthPackage(ctx, nlb.Status.LoadBalancerId, toAttach); err != nil {
+			return err
+		}
+		r.Recorder.Event(nlb, "Normal", "BandwidthPackageAttached", fmt.Sprintf("Attached bandwidth package %s", toAttach))
+	}
+
+	nlb.Status.ManagedBandwidthPackageId = nlb.Spec.BandwidthPackageId
+	return nil
+}
+
+// diffBandwidthPackage returns the live bandwidth package to detach and the desired
+// one to attach, either of which may be empty.
+func diffBandwidthPackage(nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) (toDetach, toAttach string) {
+	live := tea.StringValue(lb.BandwidthPackageId)
+	desired := nlb.Spec.BandwidthPackageId
+	if live == desired {
+		return "", ""
+	}
+	if live != "" && (desired != "" || live == nlb.Status.ManagedBandwidthPackageId) {
+		toDetach = live
+	}
+	return toDetach, desired
+}
+
+// handleIpv6AddressType converges the IPv6 address type of a DualStack instance
+// with Spec.Ipv6AddressType. CreateLoadBalancer cannot set it, so an instance
+// asking for an Internet IPv6 address is switched here once it is Active.
+func (r *NLBReconciler) handleIpv6AddressType(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) error {
+	desired := nlb.Spec.Ipv6AddressType
+	if desired == "" || nlb.Spec.AddressIpVersion != "DualStack" {
+		return nil
+	}
+	live := tea.StringValue(lb.Ipv6AddressType)
+	if live == desired {
+		return nil
+	}
+
+	recordDriftCorrection(ctx, "ipv6AddressType", live, desired)
+	klog.FromContext(ctx).Info("Changing IPv6 address type", "live", live, "desired", desired)
+	if err := r.NLBClient.UpdateLoadBalancerIpv6AddressType(ctx, nlb.Status.LoadBalancerId, desired); err != nil {
+		return err
+	}
+	r.Recorder.Event(nlb, "Normal", "Ipv6AddressTypeUpdated",
+		fmt.Sprintf("Changed IPv6 address type from %s to %s", live, desired))
+	return nil
+}
+
+// handleAttributes converges mutable instance attributes (the name, the CPS limit
+// and cross-zone load balancing) with the spec through UpdateLoadBalancerAttribute.
+func (r *NLBReconciler) handleAttributes(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) error {
+	update := &provider.LoadBalancerAttributeUpdate{}
+	changed := false
+
+	liveName := tea.StringValue(lb.LoadBalancerName)
+	if nlb.Spec.LoadBalancerName != "" && nlb.Spec.LoadBalancerName != liveName {
+		update.LoadBalancerName = tea.String(nlb.Spec.LoadBalancerName)
+		changed = true
+		recordDriftCorrection(ctx, "loadBalancerName", liveName, nlb.Spec.LoadBalancerName)
+	}
+
+	liveCps := tea.Int32Value(lb.Cps)
+	if cps := desiredCps(nlb); cps != nil && *cps != liveCps {
+		update.Cps = cps
+		changed = true
+		recordDriftCorrection(ctx, "capacity.cps", liveCps, *cps)
+	}
+
+	liveCrossZone := tea.BoolValue(lb.CrossZoneEnabled)
+	if want := nlb.Spec.CrossZoneEnabled; want != nil && *want != liveCrossZone {
+		update.CrossZoneEnabled = tea.Bool(*want)
+		changed = true
+		recordDriftCorrection(ctx, "crossZoneEnabled", liveCrossZone, *want)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	log := klog.FromContext(ctx)
+	log.Info("Updating NLB attributes", "loadBalancerId", nlb.Status.LoadBalancerId)
+	if err := r.NLBClient.UpdateLoadBalancerAttribute(ctx, nlb.Status.LoadBalancerId, update); err != nil {
+		return err
+	}
+
+	if update.LoadBalancerName != nil {
+		r.Recorder.Event(nlb, "Normal", "Renamed",
+			fmt.Sprintf("Renamed NLB from %q to %q", liveName, nlb.Spec.LoadBalancerName))
+	}
+	if update.Cps != nil {
+		r.Recorder.Event(nlb, "Normal", "CapacityUpdated",
+			fmt.Sprintf("Updated CPS limit from %d to %d", liveCps, *update.Cps))
+	}
+	if update.CrossZoneEnabled != nil {
+		r.Recorder.Event(nlb, "Normal", "CrossZoneUpdated",
+			fmt.Sprintf("Changed cross-zone load balancing from %t to %t", liveCrossZone, *update.CrossZoneEnabled))
+	}
+	return nil
 }
 
-// handleSecurityGroups handles security group operations
-func (r *NLBReconciler) handleSecurityGroups(ctx context.Context, nlb *nlbv1.NLB) error {
-	if len(nlb.Spec.SecurityGroupIds) == 0 {
+// desiredCps returns Spec.Capacity.Cps, or nil when the CPS limit is not managed.
+func desiredCps(nlb *nlbv1.NLB) *int32 {
+	if nlb.Spec.Capacity == nil {
 		return nil
 	}
+	return nlb.Spec.Capacity.Cps
+}
 
+// handleZoneMappings converges the live zone mappings with Spec.ZoneMappings.
+// Zone changes can legitimately be rejected by the cloud (e.g. removing a zone
+// that still carries connections), so the outcome is reported through the
+// ZoneMappingsSynced condition instead of failing the reconcile.
+func (r *NLBReconciler) handleZoneMappings(ctx context.Context, nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) {
 	log := klog.FromContext(ctx)
-	log.Info("Joining security groups", "securityGroupIds", nlb.Spec.SecurityGroupIds)
 
-	return r.NLBClient.JoinSecurityGroup(ctx, nlb.Status.LoadBalancerId, nlb.Spec.SecurityGroupIds)
+	live, inSync := liveZoneMappings(nlb, lb)
+	if inSync {
+		if drift := immutableZoneDrift(nlb); len(drift) > 0 {
+			recordDrift(ctx, "zoneMappings")
+			r.reportImmutableZoneDrift(nlb, drift)
+			return
+		}
+		r.updateCondition(nlb, ConditionTypeZoneMappingsSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Zone mappings match spec")
+		return
+	}
+
+	if len(nlb.Spec.ZoneMappings) < 2 {
+		r.updateCondition(nlb, ConditionTypeZoneMappingsSynced, metav1.ConditionFalse, "InvalidZoneMappings",
+			fmt.Sprintf("At least 2 zone mappings are required, got %d", len(nlb.Spec.ZoneMappings)))
+		return
+	}
+
+	recordDriftCorrection(ctx, "zoneMappings", live, nlb.Spec.ZoneMappings)
+	log.Info("Updating NLB zone mappings", "live", live, "desired", nlb.Spec.ZoneMappings)
+	if err := r.NLBClient.UpdateLoadBalancerZones(ctx, nlb.Status.LoadBalancerId, nlb.Spec.ZoneMappings); err != nil {
+		log.Error(err, "Failed to update NLB zone mappings")
+		r.Recorder.Event(nlb, "Warning", "ZoneUpdateFailed", fmt.Sprintf("Failed to update zone mappings: %v", err))
+		r.updateCondition(nlb, ConditionTypeZoneMappingsSynced, metav1.ConditionFalse, "ZoneUpdateFailed", err.Error())
+		return
+	}
+
+	r.Recorder.Event(nlb, "Normal", "ZonesUpdated",
+		fmt.Sprintf("Updated zone mappings from %d to %d zones", len(live), len(nlb.Spec.ZoneMappings)))
+	if ignored := addedZoneIpv6Addresses(nlb, live); len(ignored) > 0 {
+		r.reportImmutableZoneDrift(nlb, ignored)
+		return
+	}
+	r.updateCondition(nlb, ConditionTypeZoneMappingsSynced, metav1.ConditionTrue, ReasonReconcileSuccess, "Zone mappings updated")
+}
+
+// addedZoneIpv6Addresses lists the ipv6Address of the zones in the spec that are
+// missing from live. UpdateLoadBalancerZones cannot set the IPv6 address of an
+// added zone, so these are not applied.
+func addedZoneIpv6Addresses(nlb *nlbv1.NLB, live map[string]string) []string {
+	var ignored []string
+	for _, zm := range nlb.Spec.ZoneMappings {
+		if _, ok := live[zm.ZoneId]; !ok && zm.Ipv6Address != "" {
+			ignored = append(ignored, fmt.Sprintf("%s ipv6Address %s (only applied at instance creation)", zm.ZoneId, zm.Ipv6Address))
+		}
+	}
+	return ignored
+}
+
+// immutableZoneDrift lists the per-zone addresses in the spec that differ from the
+// ones allocated in Status.ZoneMappingStatus. UpdateLoadBalancerZones only applies
+// privateIPv4Address and allocationId when a zone is added, and ipv6Address is only
+// applied by CreateLoadBalancer, so these cannot be converged in place.
+func immutableZoneDrift(nlb *nlbv1.NLB) []string {
+	allocated := make(map[string]nlbv1.ZoneMappingStatus, len(nlb.Status.ZoneMappingStatus))
+	for _, st := range nlb.Status.ZoneMappingStatus {
+		allocated[st.ZoneId] = st
+	}
+
+	var drift []string
+	for _, zm := range nlb.Spec.ZoneMappings {
+		st, ok := allocated[zm.ZoneId]
+		if !ok {
+			continue
+		}
+		if zm.PrivateIPv4Address != "" && zm.PrivateIPv4Address != st.PrivateIPv4Address {
+			drift = append(drift, fmt.Sprintf("%s privateIPv4Address %s -> %s", zm.ZoneId, st.PrivateIPv4Address, zm.PrivateIPv4Address))
+		}
+		if zm.Ipv6Address != "" && zm.Ipv6Address != st.Ipv6Address {
+			drift = append(drift, fmt.Sprintf("%s ipv6Address %s -> %s (only applied at instance creation)", zm.ZoneId, st.Ipv6Address, zm.Ipv6Address))
+		}
+		if zm.AllocationId != "" && zm.AllocationId != st.AllocationId {
+			drift = append(drift, fmt.Sprintf("%s allocationId %s -> %s", zm.ZoneId, st.AllocationId, zm.AllocationId))
+		}
+	}
+	return drift
+}
+
+// reportImmutableZoneDrift surfaces address changes that cannot be applied in
+// place, emitting the event once per transition.
+func (r *NLBReconciler) reportImmutableZoneDrift(nlb *nlbv1.NLB, drift []string) {
+	msg := fmt.Sprintf("Zone addresses cannot be changed in place; privateIPv4Address and allocationId are applied by removing the zone from zoneMappings and adding it back, ipv6Address only when the instance is created: %s",
+		strings.Join(drift, "; "))
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeZoneMappingsSynced); cond == nil || cond.Message != msg {
+		r.Recorder.Event(nlb, "Warning", ReasonImmutableField, msg)
+	}
+	r.updateCondition(nlb, ConditionTypeZoneMappingsSynced, metav1.ConditionFalse, ReasonImmutableField, msg)
+}
+
+// handleZoneHealth sets the ZonesHealthy condition from the zone statuses in
+// Status.ZoneMappingStatus. A zone that is stopped or shifted away no longer
+// serves traffic, so a Warning event is emitted when the set of degraded zones
+// changes and a Normal event once all zones are Active again.
+func (r *NLBReconciler) handleZoneHealth(nlb *nlbv1.NLB) {
+	var degraded []string
+	for _, st := range nlb.Status.ZoneMappingStatus {
+		if st.Status != provider.ZoneStatusActive {
+			degraded = append(degraded, fmt.Sprintf("%s=%s", st.ZoneId, st.Status))
+		}
+	}
+
+	cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeZonesHealthy)
+	if len(degraded) == 0 {
+		if cond != nil && cond.Status == metav1.ConditionFalse {
+			r.Recorder.Event(nlb, "Normal", "ZonesRecovered", "All zones of the NLB are Active again")
+		}
+		r.updateCondition(nlb, ConditionTypeZonesHealthy, metav1.ConditionTrue, "AllZonesActive",
+			fmt.Sprintf("All %d zones are Active", len(nlb.Status.ZoneMappingStatus)))
+		return
+	}
+
+	sort.Strings(degraded)
+	msg := fmt.Sprintf("%d of %d zones are not Active: %s", len(degraded), len(nlb.Status.ZoneMappingStatus), strings.Join(degraded, ", "))
+	if cond == nil || cond.Message != msg {
+		r.Recorder.Event(nlb, "Warning", "ZoneDegraded", msg)
+	}
+	r.updateCondition(nlb, ConditionTypeZonesHealthy, metav1.ConditionFalse, "ZoneDegraded", msg)
+}
+
+// liveZoneMappings returns the live zone -> vSwitch mapping and whether it matches Spec.ZoneMappings.
+func liveZoneMappings(nlb *nlbv1.NLB, lb *nlbsdk.GetLoadBalancerAttributeResponseBody) (map[string]string, bool) {
+	live := make(map[string]string, len(lb.ZoneMappings))
+	for _, zm := range lb.ZoneMappings {
+		if zm == nil {
+			continue
+		}
+		live[tea.StringValue(zm.ZoneId)] = tea.StringValue(zm.VSwitchId)
+	}
+
+	inSync := len(live) == len(nlb.Spec.ZoneMappings)
+	for _, zm := range nlb.Spec.ZoneMappings {
+		if live[zm.ZoneId] != zm.VSwitchId {
+			inSync = false
+			break
+		}
+	}
+	return live, inSync
+}
+
+// loadBalancerSettled re-reads the instance after the updates of this pass and
+// reports its status and whether it is one of StableLoadBalancerStates. Every
+// update invalidates the cached attributes, so without updates this is served
+// from the attribute cache. An instance that disappeared is reported as not
+// settled; the next pass recreates it.
+func (r *NLBReconciler) loadBalancerSettled(ctx context.Context, nlb *nlbv1.NLB) (string, bool, error) {
+	lb, err := r.NLBClient.GetLoadBalancerCached(ctx, nlb.Status.LoadBalancerId)
+	if err != nil || lb == nil {
+		return "", false, err
+	}
+	status := tea.StringValue(lb.LoadBalancerStatus)
+	nlb.Status.LoadBalancerStatus = status
+	return status, provider.IsLoadBalancerStable(status, r.StableLoadBalancerStates), nil
+}
+
+// handleUpdateError reports a failed attribute update on an existing NLB.
+// Updates rejected by modification protection will not succeed by retrying,
+// so they are surfaced as a condition and retried on the slow resync interval
+// instead of returning an error that would requeue with backoff.
+func (r *NLBReconciler) handleUpdateError(ctx context.Context, nlb *nlbv1.NLB, what string, err error) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+
+	if provider.IsModificationProtectionError(err) {
+		msg := fmt.Sprintf("Update of %s is blocked by modification protection: %v", what, err)
+		r.Recorder.Event(nlb, "Warning", ReasonModificationProtected, msg)
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonModificationProtected, msg)
+		if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
+			log.Error(statusErr, "Failed to update NLB status")
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod(nlb)}, nil
+	}
+
+	// Retrying does not help until the quota is raised or the spec is changed
+	if isQuotaErrorCode(provider.ErrorCode(err)) {
+		msg := fmt.Sprintf("Update of %s exceeds a quota: %v", what, err)
+		r.Recorder.Event(nlb, "Warning", ReasonQuotaExceeded, msg)
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonQuotaExceeded, msg)
+		if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
+			log.Error(statusErr, "Failed to update NLB status")
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{RequeueAfter: r.resyncPeriod(nlb)}, nil
+	}
+
+	// The instance entered Configuring between the status check and the update
+	if provider.IsIncorrectStatusError(err) {
+		log.Info("NLB is busy, retrying update shortly", "update", what, "error", err.Error())
+		r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonConfiguring,
+			"Waiting for NLB to leave Configuring state")
+		if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
+			log.Error(statusErr, "Failed to update NLB status")
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{RequeueAfter: configuringRequeueInterval}, nil
+	}
+
+	r.Recorder.Event(nlb, "Warning", ReasonReconcileError, fmt.Sprintf("Failed to reconcile %s: %v", what, err))
+	return ctrl.Result{}, err
+}
+
+// confirmDeleted reports whether ListLoadBalancers agrees that lbId no longer exists.
+func (r *NLBReconciler) confirmDeleted(ctx context.Context, lbId string) (bool, error) {
+	lbs, err := r.NLBClient.ListLoadBalancers(ctx, provider.LoadBalancerFilter{LoadBalancerIds: []string{lbId}})
+	if err != nil {
+		return false, err
+	}
+	for _, lb := range lbs {
+		if lb.LoadBalancerId == lbId {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// waitForAddress handles GetXipFailed, a transient error while a new instance is
+// being provisioned: instead of reporting a reconcile error and backing off, it
+// marks the NLB as waiting for its addresses and checks again shortly.
+func (r *NLBReconciler) waitForAddress(ctx context.Context, nlb *nlbv1.NLB, err error) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+	log.Info("NLB addresses are not allocated yet, retrying", "error", err.Error())
+
+	r.updateCondition(nlb, ConditionTypeReady, metav1.ConditionFalse, ReasonAddressAllocating,
+		"NLB instance addresses are still being allocated (GetXipFailed), retrying")
+	if statusErr := r.updateStatus(ctx, nlb); statusErr != nil {
+		log.Error(statusErr, "Failed to update NLB status while waiting for addresses")
+	}
+	return ctrl.Result{RequeueAfter: addressRetryInterval}, nil
+}
+
+// handleTags converges the tags on the cloud instance with Spec.Tags and DefaultTags.
+// In additive mode only tags previously applied by the operator (Status.ManagedTagKeys)
+// are removed; in exclusive mode every user tag not present in the spec is removed.
+func (r *NLBReconciler) handleTags(ctx context.Context, nlb *nlbv1.NLB) error {
+	log := klog.FromContext(ctx)
+
+	current, err := r.NLBClient.ListTagResources(ctx, nlb.Status.LoadBalancerId)
+	if err != nil {
+		return err
+	}
+
+	tags := r.desiredTags(nlb)
+	toAdd, toRemove := diffTags(nlb, tags, current, r.provenanceTags(nlb))
+
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		recordDriftCorrection(ctx, "tags", toRemove, toAdd)
+		log.Info("Correcting tag drift", "add", len(toAdd), "remove", toRemove)
+		if err := r.NLBClient.TagResources(ctx, nlb.Status.LoadBalancerId, toAdd); err != nil {
+			return err
+		}
+		if err := r.NLBClient.UntagResources(ctx, nlb.Status.LoadBalancerId, toRemove); err != nil {
+			return err
+		}
+		r.Recorder.Event(nlb, "Normal", "TagsUpdated",
+			fmt.Sprintf("Reconciled tags: %d added/updated, %d removed", len(toAdd), len(toRemove)))
+	}
+
+	desired := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		desired[t.Key] = true
+	}
+	managed := make([]string, 0, len(desired))
+	for k := range desired {
+		managed = append(managed, k)
+	}
+	sort.Strings(managed)
+	nlb.Status.ManagedTagKeys = managed
+	return nil
+}
+
+// diffTags returns the tags to add or update and the sorted tag keys to remove
+// to converge current with tags, honouring Spec.TagMode. The provenance tags are
+// always desired, so they are restored when stripped out of band and never
+// removed in exclusive mode.
+func diffTags(nlb *nlbv1.NLB, tags []nlbv1.Tag, current map[string]string, provenance []nlbv1.Tag) ([]nlbv1.Tag, []string) {
+	desired := make(map[string]string, len(tags)+len(provenance))
+	var toAdd []nlbv1.Tag
+	for _, t := range append(append([]nlbv1.Tag{}, provenance...), tags...) {
+		if _, ok := desired[t.Key]; ok {
+			continue
+		}
+		desired[t.Key] = t.Value
+		if v, ok := current[t.Key]; !ok || v != t.Value {
+			toAdd = append(toAdd, t)
+		}
+	}
+
+	var toRemove []string
+	if nlb.Spec.TagMode == nlbv1.TagModeExclusive {
+		for k := range current {
+			if _, ok := desired[k]; !ok {
+				toRemove = append(toRemove, k)
+			}
+		}
+	} else {
+		for _, k := range nlb.Status.ManagedTagKeys {
+			if _, ok := desired[k]; ok {
+				continue
+			}
+			if _, ok := current[k]; ok {
+				toRemove = append(toRemove, k)
+			}
+		}
+	}
+	sort.Strings(toRemove)
+	return toAdd, toRemove
+}
+
+// addFinalizer adds NLBFinalizer to nlb with a merge patch. The patch carries
+// the resourceVersion so that finalizers added by others since nlb was read are
+// not overwritten; a conflict is retried by the caller's requeue.
+func (r *NLBReconciler) addFinalizer(ctx context.Context, nlb *nlbv1.NLB) error {
+	patch := client.MergeFromWithOptions(nlb.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(nlb, NLBFinalizer)
+	return r.Patch(ctx, nlb, patch)
+}
+
+// updateStatus writes nlb.Status, retrying on conflicts. The status is owned by
+// this controller, so on a conflict, e.g. because the spec, an annotation or the
+// finalizers changed since nlb was read, the freshly computed status is applied
+// to the latest object rather than failing the reconcile. nlb is refreshed with
+// the stored object so that later updates in the same reconcile do not conflict.
+func (r *NLBReconciler) updateStatus(ctx context.Context, nlb *nlbv1.NLB) error {
+	status := nlb.Status.DeepCopy()
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			latest := &nlbv1.NLB{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(nlb), latest); err != nil {
+				return err
+			}
+			status.DeepCopyInto(&latest.Status)
+			latest.DeepCopyInto(nlb)
+		}
+		first = false
+		return r.Status().Update(ctx, nlb)
+	})
 }
 
 // updateCondition updates the condition of the NLB resource
@@ -356,19 +1887,82 @@ func (r *NLBReconciler) updateCondition(nlb *nlbv1.NLB, conditionType string, st
 	if !found {
 		nlb.Status.Conditions = append(nlb.Status.Conditions, condition)
 	}
+
+	nlb.Status.Phase = derivePhase(nlb)
+}
+
+// derivePhase summarizes the conditions and cloud status of nlb into a single phase.
+func derivePhase(nlb *nlbv1.NLB) nlbv1.NLBPhase {
+	if !nlb.ObjectMeta.DeletionTimestamp.IsZero() {
+		return nlbv1.NLBDeleting
+	}
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeError); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.Reason == ReasonCreateFailed {
+		return nlbv1.NLBFailed
+	}
+
+	ready := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeReady)
+	switch {
+	case ready != nil && ready.Status == metav1.ConditionTrue:
+		return nlbv1.NLBActive
+	case ready != nil && ready.Reason == "AdoptFailed":
+		return nlbv1.NLBFailed
+	case ready != nil && (ready.Reason == ReasonCloudDeleting || ready.Reason == "Deleting"):
+		return nlbv1.NLBDeleting
+	case nlb.Status.LoadBalancerId == "":
+		return nlbv1.NLBPending
+	case nlb.Status.LoadBalancerStatus == provider.LoadBalancerStatusActive,
+		nlb.Status.LoadBalancerStatus == provider.LoadBalancerStatusConfiguring:
+		// Serving in the cloud but an update is pending or blocked (e.g. modification
+		// protection); the Ready condition carries the details.
+		return nlbv1.NLBActive
+	default:
+		return nlbv1.NLBProvisioning
+	}
+}
+
+// controllerOptions returns the options shared by the controllers, running up to
+// maxConcurrentReconciles reconciles at once, or one when it is not positive.
+func controllerOptions(maxConcurrentReconciles int) controller.Options {
+	if maxConcurrentReconciles <= 0 {
+		maxConcurrentReconciles = 1
+	}
+	return controller.Options{
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RecoverPanic:            &recoverPanic,
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager
 func (r *NLBReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	maxConcurrent := r.MaxConcurrentReconciles
-	if maxConcurrent <= 0 {
-		maxConcurrent = 1
+	r.errorBackoff = newRequeueBackoff(defaultErrorBackoffBase, defaultErrorBackoffMax)
+	deletionBackoffMax := r.DeletionRetryMaxBackoff
+	if deletionBackoffMax <= 0 {
+		deletionBackoffMax = defaultErrorBackoffMax
 	}
+	r.deletionBackoff = newRequeueBackoff(defaultErrorBackoffBase, deletionBackoffMax)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nlbv1.NLB{}).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: maxConcurrent,
-		}).
+		// handleDeletion waits for referencing Listener CRs to go away; re-enqueue
+		// the NLB as each one is deleted instead of polling.
+		Watches(&nlbv1.Listener{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				lsn, ok := obj.(*nlbv1.Listener)
+				if !ok || lsn.Spec.LoadBalancerRef == "" {
+					return nil
+				}
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{
+					Namespace: lsn.Namespace,
+					Name:      lsn.Spec.LoadBalancerRef,
+				}}}
+			}),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
+		WithOptions(controllerOptions(r.MaxConcurrentReconciles)).
 		Complete(r)
 }
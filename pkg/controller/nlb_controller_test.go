@@ -0,0 +1,560 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider/fake"
+)
+
+func testNLB() *nlbv1.NLB {
+	return &nlbv1.NLB{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Finalizers: []string{NLBFinalizer}},
+		Spec: nlbv1.NLBSpec{
+			LoadBalancerName: "web",
+			AddressType:      "Intranet",
+			VpcId:            "vpc-1",
+			ZoneMappings: []nlbv1.ZoneMapping{
+				{ZoneId: "cn-hangzhou-a", VSwitchId: "vsw-a"},
+				{ZoneId: "cn-hangzhou-b", VSwitchId: "vsw-b"},
+			},
+		},
+	}
+}
+
+// newTestReconciler returns an NLBReconciler backed by a fake API server holding
+// nlb and a fake NLB API, along with nlb as read back from the API server.
+func newTestReconciler(t *testing.T, nlb *nlbv1.NLB) (*NLBReconciler, *fake.NLBClient, *nlbv1.NLB) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := nlbv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nlb).
+		WithStatusSubresource(&nlbv1.NLB{}).
+		Build()
+
+	nlbClient := fake.NewNLBClient()
+	nlbClient.Zones = []string{"cn-hangzhou-a", "cn-hangzhou-b"}
+	r := &NLBReconciler{
+		Client:    c,
+		Scheme:    scheme,
+		Recorder:  record.NewFakeRecorder(100),
+		NLBClient: nlbClient,
+	}
+
+	stored := &nlbv1.NLB{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(nlb), stored); err != nil {
+		t.Fatalf("failed to get NLB: %v", err)
+	}
+	return r, nlbClient, stored
+}
+
+func TestCreateMergesDefaultTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		specTags    []nlbv1.Tag
+		defaultTags []nlbv1.Tag
+		want        map[string]string
+	}{
+		{
+			name:     "no default tags",
+			specTags: []nlbv1.Tag{{Key: "app", Value: "web"}},
+			want:     map[string]string{"app": "web"},
+		},
+		{
+			name:        "default tags only",
+			defaultTags: []nlbv1.Tag{{Key: "cost-center", Value: "infra"}},
+			want:        map[string]string{"cost-center": "infra"},
+		},
+		{
+			name:        "disjoint keys are merged",
+			specTags:    []nlbv1.Tag{{Key: "app", Value: "web"}},
+			defaultTags: []nlbv1.Tag{{Key: "cost-center", Value: "infra"}},
+			want:        map[string]string{"app": "web", "cost-center": "infra"},
+		},
+		{
+			name:        "spec tag wins on key conflict",
+			specTags:    []nlbv1.Tag{{Key: "env", Value: "staging"}},
+			defaultTags: []nlbv1.Tag{{Key: "env", Value: "prod"}, {Key: "cost-center", Value: "infra"}},
+			want:        map[string]string{"env": "staging", "cost-center": "infra"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlb := testNLB()
+			nlb.Spec.Tags = tt.specTags
+			r, nlbClient, nlb := newTestReconciler(t, nlb)
+			r.DefaultTags = tt.defaultTags
+
+			if _, err := r.handleCreateOrUpdate(context.Background(), nlb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if nlb.Status.LoadBalancerId == "" {
+				t.Fatal("NLB was not created")
+			}
+
+			got := nlbClient.Tags[nlb.Status.LoadBalancerId]
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("tag %s = %q, want %q", k, got[k], v)
+				}
+			}
+			if want := len(tt.want) + len(r.provenanceTags(nlb)); len(got) != want {
+				t.Errorf("got %d tags %v, want %d", len(got), got, want)
+			}
+		})
+	}
+}
+
+func TestCreateRetriesGetXipFailed(t *testing.T) {
+	r, nlbClient, nlb := newTestReconciler(t, testNLB())
+	ctx := context.Background()
+
+	nlbClient.Errors["CreateLoadBalancer"] = fake.APIError("GetXipFailed")
+	result, err := r.handleCreateOrUpdate(ctx, nlb)
+	if err != nil {
+		t.Fatalf("GetXipFailed returned error %v, want a requeue", err)
+	}
+	if result.RequeueAfter != addressRetryInterval {
+		t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, addressRetryInterval)
+	}
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeReady); cond == nil || cond.Reason != ReasonAddressAllocating {
+		t.Errorf("Ready condition = %v, want reason %s", cond, ReasonAddressAllocating)
+	}
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeError); cond != nil {
+		t.Errorf("GetXipFailed set the Error condition: %v", cond)
+	}
+
+	delete(nlbClient.Errors, "CreateLoadBalancer")
+	if _, err := r.handleCreateOrUpdate(ctx, nlb); err != nil {
+		t.Fatalf("unexpected error after GetXipFailed cleared: %v", err)
+	}
+	if _, ok := nlbClient.LoadBalancers[nlb.Status.LoadBalancerId]; !ok {
+		t.Errorf("Status.LoadBalancerId = %q, want the created instance", nlb.Status.LoadBalancerId)
+	}
+	if n := len(nlbClient.LoadBalancers); n != 1 {
+		t.Errorf("got %d instances, want 1", n)
+	}
+}
+
+func TestMissingLoadBalancerRecreation(t *testing.T) {
+	const lbId = "nlb-existing"
+
+	tests := []struct {
+		name string
+		// exists keeps the instance in the fake NLB API
+		exists bool
+		// specRegion is the region the spec targets, the instance lives in cn-hangzhou
+		specRegion string
+		errors     map[string]error
+		wantErr    bool
+		wantReset  bool
+	}{
+		{
+			name:    "transient error getting the instance",
+			exists:  true,
+			errors:  map[string]error{"GetLoadBalancer": fake.APIError("Throttling.User")},
+			wantErr: true,
+		},
+		{
+			name:    "transient error confirming the deletion",
+			errors:  map[string]error{"ListLoadBalancers": fake.APIError("ServiceUnavailable")},
+			wantErr: true,
+		},
+		{
+			name:      "deletion confirmed",
+			wantReset: true,
+		},
+		{
+			name:       "spec moved to another region",
+			specRegion: "cn-shanghai",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlb := testNLB()
+			nlb.Spec.RegionId = tt.specRegion
+			nlb.Status.LoadBalancerId = lbId
+			nlb.Status.RegionId = "cn-hangzhou"
+			r, nlbClient, nlb := newTestReconciler(t, nlb)
+			if tt.exists {
+				nlbClient.LoadBalancers[lbId] = &nlbsdk.GetLoadBalancerAttributeResponseBody{
+					LoadBalancerId:     tea.String(lbId),
+					LoadBalancerStatus: tea.String("Active"),
+				}
+			}
+			for method, err := range tt.errors {
+				nlbClient.Errors[method] = err
+			}
+
+			_, err := r.handleCreateOrUpdate(context.Background(), nlb)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if n := nlbClient.CallCount("CreateLoadBalancer"); n != 0 {
+				t.Errorf("CreateLoadBalancer called %d times, want 0", n)
+			}
+			if tt.wantReset {
+				if nlb.Status.LoadBalancerId != "" {
+					t.Errorf("Status.LoadBalancerId = %q, want it cleared for recreation", nlb.Status.LoadBalancerId)
+				}
+			} else if nlb.Status.LoadBalancerId != lbId {
+				t.Errorf("Status.LoadBalancerId = %q, want %q kept", nlb.Status.LoadBalancerId, lbId)
+			}
+			if tt.specRegion != "" {
+				if n := nlbClient.CallCount("GetLoadBalancer"); n != 0 {
+					t.Errorf("GetLoadBalancer called %d times in the other region, want 0", n)
+				}
+				if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeReady); cond == nil || cond.Reason != ReasonRegionMismatch {
+					t.Errorf("Ready condition = %+v, want reason %s", cond, ReasonRegionMismatch)
+				}
+			}
+		})
+	}
+}
+
+// slowListenerClient makes DeleteListener take delay, standing in for the wait on
+// its async job, and fail for the listeners in failing. It records the peak
+// number of DeleteListener calls in flight.
+type slowListenerClient struct {
+	*fake.NLBClient
+	delay   time.Duration
+	failing map[string]bool
+
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func newSlowListenerClient(n int, delay time.Duration) (*slowListenerClient, []string) {
+	nlbClient := fake.NewNLBClient()
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("lsn-%02d", i)
+		nlbClient.Listeners["nlb-1"] = append(nlbClient.Listeners["nlb-1"], provider.ListenerAttribute{
+			ListenerId:       ids[i],
+			ListenerPort:     int32(1000 + i),
+			ListenerProtocol: "TCP",
+			LoadBalancerId:   "nlb-1",
+		})
+	}
+	return &slowListenerClient{NLBClient: nlbClient, delay: delay}, ids
+}
+
+func (c *slowListenerClient) DeleteListener(ctx context.Context, listenerId string) error {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+
+	time.Sleep(c.delay)
+	if c.failing[listenerId] {
+		return fmt.Errorf("listener %s is busy", listenerId)
+	}
+	return c.NLBClient.DeleteListener(ctx, listenerId)
+}
+
+func TestDeleteListeners(t *testing.T) {
+	nlbClient, ids := newSlowListenerClient(20, 10*time.Millisecond)
+	nlbClient.failing = map[string]bool{ids[3]: true, ids[17]: true}
+	r := &NLBReconciler{NLBClient: nlbClient}
+
+	err := r.deleteListeners(context.Background(), ids)
+	if err == nil {
+		t.Fatal("got no error, want the failures of two listeners")
+	}
+	// Errors are reported in input order, whatever order the deletions finish in
+	msg := err.Error()
+	if i, j := strings.Index(msg, ids[3]), strings.Index(msg, ids[17]); i < 0 || j < 0 || i > j {
+		t.Errorf("error %q does not name %s before %s", msg, ids[3], ids[17])
+	}
+	if left := nlbClient.Listeners["nlb-1"]; len(left) != 2 {
+		t.Errorf("%d listeners left, want the 2 failing ones", len(left))
+	}
+	if peak := nlbClient.peak.Load(); peak < 2 || peak > orphanListenerDeleteWorkers {
+		t.Errorf("peak concurrent deletions = %d, want between 2 and %d", peak, orphanListenerDeleteWorkers)
+	}
+}
+
+// BenchmarkDeleteListeners compares deleting 20 listeners one at a time with
+// deleteListeners, each deletion taking as long as a short async job.
+func BenchmarkDeleteListeners(b *testing.B) {
+	const delay = 5 * time.Millisecond
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			nlbClient, ids := newSlowListenerClient(20, delay)
+			for _, id := range ids {
+				if err := nlbClient.DeleteListener(context.Background(), id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			nlbClient, ids := newSlowListenerClient(20, delay)
+			r := &NLBReconciler{NLBClient: nlbClient}
+			if err := r.deleteListeners(context.Background(), ids); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDeleteOrphanListeners(t *testing.T) {
+	tests := []struct {
+		name          string
+		deleteOrphans bool
+		managed       map[string]bool
+		wantErr       bool
+		wantLeft      []string
+		wantCondition bool
+	}{
+		{
+			name:          "orphans are reported",
+			wantLeft:      []string{"lsn-a", "lsn-b"},
+			wantCondition: true,
+		},
+		{
+			name:          "orphans are deleted",
+			deleteOrphans: true,
+		},
+		{
+			name:          "listeners managed by a Listener CR are kept",
+			deleteOrphans: true,
+			managed:       map[string]bool{"lsn-a": true},
+			wantErr:       true,
+			wantLeft:      []string{"lsn-a", "lsn-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlb := testNLB()
+			nlb.Spec.DeleteOrphanListeners = tt.deleteOrphans
+			nlb.Status.LoadBalancerId = "nlb-1"
+			r, nlbClient, nlb := newTestReconciler(t, nlb)
+			nlbClient.Listeners["nlb-1"] = []provider.ListenerAttribute{
+				{ListenerId: "lsn-a", ListenerPort: 80, ListenerProtocol: "TCP"},
+				{ListenerId: "lsn-b", ListenerPort: 443, ListenerProtocol: "TCPSSL"},
+			}
+
+			// A second pass over the same orphans stands in for a deletion retry
+			for i := 0; i < 2; i++ {
+				if err := r.deleteOrphanListeners(context.Background(), nlb, tt.managed); (err != nil) != tt.wantErr {
+					t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+				}
+			}
+
+			var left []string
+			for _, lsn := range nlbClient.Listeners["nlb-1"] {
+				left = append(left, lsn.ListenerId)
+			}
+			if strings.Join(left, ",") != strings.Join(tt.wantLeft, ",") {
+				t.Errorf("listeners left = %v, want %v", left, tt.wantLeft)
+			}
+
+			cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeOrphanListeners)
+			if (cond != nil) != tt.wantCondition {
+				t.Errorf("OrphanListeners condition = %v, want set %t", cond, tt.wantCondition)
+			}
+			if cond != nil && strings.Contains(cond.Message, "lsn-a") != !tt.managed["lsn-a"] {
+				t.Errorf("OrphanListeners condition %q misreports lsn-a", cond.Message)
+			}
+
+			recorder := r.Recorder.(*record.FakeRecorder)
+			var warnings int
+			for len(recorder.Events) > 0 {
+				if strings.HasPrefix(<-recorder.Events, "Warning "+ConditionTypeOrphanListeners) {
+					warnings++
+				}
+			}
+			if want := map[bool]int{true: 1}[tt.wantCondition]; warnings != want {
+				t.Errorf("got %d OrphanListeners warnings over two passes, want %d", warnings, want)
+			}
+		})
+	}
+}
+
+func TestSyncToleratesNilFields(t *testing.T) {
+	tests := []struct {
+		name string
+		lb   *nlbsdk.GetLoadBalancerAttributeResponseBody
+	}{
+		{
+			name: "empty body",
+			lb:   &nlbsdk.GetLoadBalancerAttributeResponseBody{},
+		},
+		{
+			name: "active without DNS name or zone mappings",
+			lb:   &nlbsdk.GetLoadBalancerAttributeResponseBody{LoadBalancerStatus: tea.String("Active")},
+		},
+		{
+			name: "active with empty zone mappings",
+			lb: &nlbsdk.GetLoadBalancerAttributeResponseBody{
+				LoadBalancerStatus: tea.String("Active"),
+				ZoneMappings: []*nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappings{
+					nil,
+					{},
+					{LoadBalancerAddresses: []*nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappingsLoadBalancerAddresses{nil}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlb := testNLB()
+			nlb.Status.LoadBalancerId = "nlb-1"
+			r, nlbClient, nlb := newTestReconciler(t, nlb)
+			nlbClient.LoadBalancers["nlb-1"] = tt.lb
+
+			defer func() {
+				if p := recover(); p != nil {
+					t.Fatalf("reconcile panicked: %v", p)
+				}
+			}()
+			if _, err := r.handleCreateOrUpdate(context.Background(), nlb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if nlb.Status.DNSName != "" {
+				t.Errorf("Status.DNSName = %q, want empty", nlb.Status.DNSName)
+			}
+		})
+	}
+}
+
+func TestStaleResourceVersion(t *testing.T) {
+	nlb := testNLB()
+	nlb.Finalizers = nil
+	r, _, stale := newTestReconciler(t, nlb)
+	ctx := context.Background()
+
+	// Bump the stored object between the read and the write
+	bump := func() {
+		t.Helper()
+		latest := &nlbv1.NLB{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(stale), latest); err != nil {
+			t.Fatalf("failed to get NLB: %v", err)
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = map[string]string{}
+		}
+		latest.Annotations["bumped"] = latest.ResourceVersion
+		if err := r.Update(ctx, latest); err != nil {
+			t.Fatalf("failed to bump NLB: %v", err)
+		}
+	}
+
+	// The finalizer patch must not overwrite the newer object, the reconcile
+	// fails and is requeued to start over from the latest one
+	bump()
+	if err := r.addFinalizer(ctx, stale.DeepCopy()); !errors.IsConflict(err) {
+		t.Fatalf("addFinalizer on a stale object got error %v, want a conflict", err)
+	}
+	latest := &nlbv1.NLB{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(stale), latest); err != nil {
+		t.Fatalf("failed to get NLB: %v", err)
+	}
+	if err := r.addFinalizer(ctx, latest); err != nil {
+		t.Fatalf("addFinalizer on the latest object: %v", err)
+	}
+
+	// The status is owned by the controller and is applied to the latest object
+	stale = latest.DeepCopy()
+	bump()
+	stale.Status.LoadBalancerId = "nlb-1"
+	if err := r.updateStatus(ctx, stale); err != nil {
+		t.Fatalf("updateStatus on a stale object: %v", err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(stale), latest); err != nil {
+		t.Fatalf("failed to get NLB: %v", err)
+	}
+	if latest.Status.LoadBalancerId != "nlb-1" {
+		t.Errorf("stored LoadBalancerId = %q, want nlb-1", latest.Status.LoadBalancerId)
+	}
+	if !controllerutil.ContainsFinalizer(latest, NLBFinalizer) || latest.Annotations["bumped"] == "" {
+		t.Errorf("status write lost concurrent changes: finalizers %v, annotations %v", latest.Finalizers, latest.Annotations)
+	}
+	if stale.ResourceVersion != latest.ResourceVersion {
+		t.Errorf("updateStatus left resourceVersion %s, stored is %s", stale.ResourceVersion, latest.ResourceVersion)
+	}
+}
+
+func TestControllerOptions(t *testing.T) {
+	tests := []struct {
+		configured int
+		want       int
+	}{
+		{configured: 0, want: 1},
+		{configured: -1, want: 1},
+		{configured: 1, want: 1},
+		{configured: 8, want: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.configured), func(t *testing.T) {
+			opts := controllerOptions(tt.configured)
+			if opts.MaxConcurrentReconciles != tt.want {
+				t.Errorf("MaxConcurrentReconciles = %d, want %d", opts.MaxConcurrentReconciles, tt.want)
+			}
+			if opts.RecoverPanic == nil || !*opts.RecoverPanic {
+				t.Error("RecoverPanic is not enabled")
+			}
+		})
+	}
+}
+
+func TestAddedZoneIpv6AddressReported(t *testing.T) {
+	nlb := testNLB()
+	nlb.Spec.AddressIpVersion = provider.AddressIpVersionDualStack
+	nlb.Spec.ZoneMappings = append(nlb.Spec.ZoneMappings,
+		nlbv1.ZoneMapping{ZoneId: "cn-hangzhou-c", VSwitchId: "vsw-c", Ipv6Address: "2408:4000::10"})
+	nlb.Status.LoadBalancerId = "nlb-1"
+	r, nlbClient, nlb := newTestReconciler(t, nlb)
+	lb := &nlbsdk.GetLoadBalancerAttributeResponseBody{LoadBalancerId: tea.String("nlb-1")}
+	for _, zm := range nlb.Spec.ZoneMappings[:2] {
+		lb.ZoneMappings = append(lb.ZoneMappings, &nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappings{
+			ZoneId:    tea.String(zm.ZoneId),
+			VSwitchId: tea.String(zm.VSwitchId),
+		})
+	}
+	nlbClient.LoadBalancers["nlb-1"] = lb
+
+	r.handleZoneMappings(context.Background(), nlb, lb)
+	if n := nlbClient.CallCount("UpdateLoadBalancerZones"); n != 1 {
+		t.Fatalf("UpdateLoadBalancerZones called %d times, want 1", n)
+	}
+	cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypeZoneMappingsSynced)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReasonImmutableField ||
+		!strings.Contains(cond.Message, "cn-hangzhou-c ipv6Address") {
+		t.Errorf("ZoneMappingsSynced condition = %+v, want False with reason %s naming the added zone", cond, ReasonImmutableField)
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+// OrphanCollector periodically looks for NLB instances tagged as managed by this
+// operator (ManagedByTagKey=OperatorId) that no NLB CR refers to, and reports them.
+// With Delete set, an instance found orphaned in two consecutive passes is deleted;
+// the second pass guards against an instance created moments before its CR status
+// recorded the ID. Instances with deletion protection enabled are never deleted.
+type OrphanCollector struct {
+	client.Client
+	Clients    *provider.ClientPool
+	OperatorId string
+	Interval   time.Duration
+	Delete     bool
+
+	// candidates are the orphans found in the previous pass, keyed by instance ID
+	candidates map[string]bool
+}
+
+// NeedLeaderElection makes the collector run on the leader only.
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs a collection pass every Interval until ctx is done.
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	log := klog.FromContext(ctx).WithName("orphan-gc")
+	ctx = klog.NewContext(ctx, log)
+	log.Info("Starting orphan NLB collector", "interval", c.Interval, "delete", c.Delete, "operatorId", c.OperatorId)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.collect(ctx); err != nil {
+			log.Error(err, "Orphan NLB collection failed")
+		}
+	}, c.Interval)
+	return nil
+}
+
+// collect runs one pass over the default region and every region used by an NLB CR.
+func (c *OrphanCollector) collect(ctx context.Context) error {
+	log := klog.FromContext(ctx)
+
+	nlbList := &nlbv1.NLBList{}
+	if err := c.List(ctx, nlbList); err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(nlbList.Items))
+	regions := map[string]bool{c.Clients.DefaultRegion(): true}
+	for _, nlb := range nlbList.Items {
+		if nlb.Status.LoadBalancerId != "" {
+			known[nlb.Status.LoadBalancerId] = true
+		}
+		if nlb.Spec.LoadBalancerId != "" {
+			known[nlb.Spec.LoadBalancerId] = true
+		}
+		if nlb.Spec.RegionId != "" {
+			regions[nlb.Spec.RegionId] = true
+		}
+	}
+
+	orphans := make(map[string]bool)
+	for region := range regions {
+		nlbClient, err := c.Clients.Get(region)
+		if err != nil {
+			log.Error(err, "Skipping region", "region", region)
+			continue
+		}
+		lbs, err := nlbClient.ListLoadBalancers(ctx, provider.LoadBalancerFilter{
+			Tags: map[string]string{nlbv1.ManagedByTagKey: c.OperatorId},
+		})
+		if err != nil {
+			log.Error(err, "Failed to list load balancers", "region", region)
+			continue
+		}
+		for _, lb := range lbs {
+			if known[lb.LoadBalancerId] {
+				continue
+			}
+			orphans[lb.LoadBalancerId] = true
+			log.Info("Found NLB instance managed by this operator without an NLB CR",
+				"region", region, "loadBalancerId", lb.LoadBalancerId, "name", lb.LoadBalancerName,
+				"status", lb.LoadBalancerStatus, "tags", lb.Tags)
+			if c.Delete && c.candidates[lb.LoadBalancerId] {
+				c.deleteOrphan(ctx, nlbClient, lb.LoadBalancerId)
+			}
+		}
+	}
+	c.candidates = orphans
+	return nil
+}
+
+// deleteOrphan deletes an orphaned instance unless deletion protection is enabled.
+func (c *OrphanCollector) deleteOrphan(ctx context.Context, nlbClient *provider.NLBClient, lbId string) {
+	log := klog.FromContext(ctx)
+
+	lb, err := nlbClient.GetLoadBalancer(ctx, lbId)
+	if err != nil {
+		log.Error(err, "Failed to get orphan NLB", "loadBalancerId", lbId)
+		return
+	}
+	if lb == nil {
+		return
+	}
+	if lb.DeletionProtectionConfig != nil && tea.BoolValue(lb.DeletionProtectionConfig.Enabled) {
+		log.Info("Not deleting orphan NLB with deletion protection enabled", "loadBalancerId", lbId)
+		return
+	}
+	if err := nlbClient.DeleteLoadBalancer(ctx, lbId); err != nil {
+		log.Error(err, "Failed to delete orphan NLB", "loadBalancerId", lbId)
+		return
+	}
+	log.Info("Deleted orphan NLB", "loadBalancerId", lbId)
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// ConditionTypePaused is True while reconciliation of an NLB is paused by the
+// PausedAnnotation.
+const ConditionTypePaused = "Paused"
+
+// isPaused reports whether reconciliation of nlb is paused. Deletion of a paused
+// NLB still proceeds unless it also carries the PauseDeletionAnnotation.
+func isPaused(nlb *nlbv1.NLB) bool {
+	annotations := nlb.GetAnnotations()
+	if annotations[nlbv1.PausedAnnotation] != "true" {
+		return false
+	}
+	return nlb.DeletionTimestamp.IsZero() || annotations[nlbv1.PauseDeletionAnnotation] == "true"
+}
+
+// handlePaused records the Paused condition and returns without calling any NLB
+// API. No requeue is needed: removing the annotation triggers a reconcile.
+func (r *NLBReconciler) handlePaused(ctx context.Context, nlb *nlbv1.NLB) (ctrl.Result, error) {
+	log := klog.FromContext(ctx)
+	log.Info("Reconciliation is paused, skipping")
+
+	msg := "Reconciliation is paused by the " + nlbv1.PausedAnnotation + " annotation"
+	if !nlb.DeletionTimestamp.IsZero() {
+		msg = "Deletion is paused by the " + nlbv1.PauseDeletionAnnotation + " annotation"
+	}
+	if cond := meta.FindStatusCondition(nlb.Status.Conditions, ConditionTypePaused); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.Message == msg {
+		return ctrl.Result{}, nil
+	}
+
+	r.Recorder.Event(nlb, "Normal", "Paused", msg)
+	r.updateCondition(nlb, ConditionTypePaused, metav1.ConditionTrue, "Paused", msg)
+	if err := r.updateStatus(ctx, nlb); err != nil {
+		log.Error(err, "Failed to update NLB status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -12,7 +13,6 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
@@ -35,6 +35,10 @@ type ServerGroupReconciler struct {
 	Recorder                record.EventRecorder
 	NLBClient               *provider.NLBClient
 	MaxConcurrentReconciles int
+
+	// Clients, when set, selects the NLBClient for the region of each resource;
+	// NLBClient is then only used for resources in the default region.
+	Clients *provider.ClientPool
 }
 
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=servergroups,verbs=get;list;watch;create;update;patch;delete
@@ -42,10 +46,25 @@ type ServerGroupReconciler struct {
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=servergroups/finalizers,verbs=update
 // +kubebuilder:rbac:groups=nlboperator.alibabacloud.com,resources=listeners,verbs=get;list;watch
 
+// forRegion returns a shallow copy of r whose NLBClient targets region, or r
+// itself when no client pool is configured.
+func (r *ServerGroupReconciler) forRegion(region string) (*ServerGroupReconciler, error) {
+	client, err := regionalClient(r.Clients, region)
+	if client == nil {
+		return r, err
+	}
+	regional := *r
+	regional.NLBClient = client
+	return &regional, nil
+}
+
 // Reconcile handles ServerGroup lifecycle.
 func (r *ServerGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := klog.FromContext(ctx).WithValues("servergroup", req.NamespacedName)
 
+	ctx, apiCalls := provider.WithAPICallCounter(ctx)
+	defer observeAPICalls(log, "servergroup", apiCalls)
+
 	sg := &nlbv1.ServerGroup{}
 	if err := r.Get(ctx, req.NamespacedName, sg); err != nil {
 		if errors.IsNotFound(err) {
@@ -55,6 +74,12 @@ func (r *ServerGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	r, err := r.forRegion(sg.Spec.Region)
+	if err != nil {
+		r.Recorder.Event(sg, corev1.EventTypeWarning, "RegionClientFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+
 	if !sg.ObjectMeta.DeletionTimestamp.IsZero() {
 		return r.handleDeletion(ctx, sg)
 	}
@@ -147,6 +172,7 @@ func (r *ServerGroupReconciler) handleCreateOrSync(ctx context.Context, sg *nlbv
 		if attr.ServerGroupStatus == cloudSGStatusAvailable {
 			sg.Status.Phase = nlbv1.ServerGroupActive
 			sg.Status.Message = "ServerGroup is available"
+			sg.Status.Scheduler = attr.Scheduler
 			if err := r.Status().Update(ctx, sg); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -163,6 +189,57 @@ func (r *ServerGroupReconciler) handleCreateOrSync(ctx context.Context, sg *nlbv
 			_ = r.Status().Update(ctx, sg)
 			return ctrl.Result{Requeue: true}, nil
 		}
+		// Converge the mutable connection drain settings; reached on spec changes only,
+		// since Active does not requeue.
+		attr, err := r.NLBClient.GetServerGroupAttribute(ctx, sg.Status.ServerGroupId)
+		if err != nil {
+			r.Recorder.Eventf(sg, corev1.EventTypeWarning, "GetAttributeFailed",
+				"Failed to query ServerGroup %s: %v", sg.Status.ServerGroupId, err)
+			return r.requeueOnAPIError(err), nil
+		}
+		if attr != nil && connectionDrainDiffers(sg, attr) {
+			log.Info("Updating ServerGroup connection drain", "serverGroupId", sg.Status.ServerGroupId,
+				"enabled", sg.Spec.ConnectionDrainEnabled, "timeout", sg.Spec.ConnectionDrainTimeout)
+			if err := r.NLBClient.UpdateServerGroupConnectionDrain(ctx, sg.Status.ServerGroupId,
+				sg.Spec.ConnectionDrainEnabled, sg.Spec.ConnectionDrainTimeout); err != nil {
+				r.Recorder.Eventf(sg, corev1.EventTypeWarning, "UpdateFailed",
+					"Failed to update connection drain of ServerGroup %s: %v", sg.Status.ServerGroupId, err)
+				return r.requeueOnAPIError(err), nil
+			}
+			r.Recorder.Eventf(sg, corev1.EventTypeNormal, "ConnectionDrainUpdated",
+				"Connection drain enabled=%t timeout=%ds", sg.Spec.ConnectionDrainEnabled, sg.Spec.ConnectionDrainTimeout)
+		}
+		if want := sg.Spec.PreserveClientIpEnabled; attr != nil && want != nil && *want != attr.PreserveClientIpEnabled {
+			log.Info("Updating ServerGroup preserve client IP", "serverGroupId", sg.Status.ServerGroupId, "enabled", *want)
+			if err := r.NLBClient.UpdateServerGroupPreserveClientIp(ctx, sg.Status.ServerGroupId, *want); err != nil {
+				r.Recorder.Eventf(sg, corev1.EventTypeWarning, "UpdateFailed",
+					"Failed to update preserve client IP of ServerGroup %s: %v", sg.Status.ServerGroupId, err)
+				return r.requeueOnAPIError(err), nil
+			}
+			r.Recorder.Eventf(sg, corev1.EventTypeNormal, "PreserveClientIpUpdated",
+				"Preserve client IP enabled=%t", *want)
+		}
+		if attr != nil {
+			scheduler := attr.Scheduler
+			if want := sg.Spec.Scheduler; want != "" && !strings.EqualFold(want, attr.Scheduler) {
+				log.Info("Updating ServerGroup scheduler", "serverGroupId", sg.Status.ServerGroupId,
+					"from", attr.Scheduler, "to", want)
+				if err := r.NLBClient.UpdateServerGroupScheduler(ctx, sg.Status.ServerGroupId, want); err != nil {
+					r.Recorder.Eventf(sg, corev1.EventTypeWarning, "UpdateFailed",
+						"Failed to update scheduler of ServerGroup %s: %v", sg.Status.ServerGroupId, err)
+					return r.requeueOnAPIError(err), nil
+				}
+				r.Recorder.Eventf(sg, corev1.EventTypeNormal, "SchedulerUpdated",
+					"Scheduler changed from %s to %s", attr.Scheduler, want)
+				scheduler = want
+			}
+			if sg.Status.Scheduler != scheduler {
+				sg.Status.Scheduler = scheduler
+				if err := r.Status().Update(ctx, sg); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
 		// Reconcile complete: no further requeue, no health check.
 		return ctrl.Result{}, nil
 
@@ -256,6 +333,15 @@ func (r *ServerGroupReconciler) handleDeletion(ctx context.Context, sg *nlbv1.Se
 	return ctrl.Result{RequeueAfter: sgRequeueDeletion}, nil
 }
 
+// connectionDrainDiffers reports whether the live connection drain settings differ
+// from the spec. The timeout is ignored while draining is disabled.
+func connectionDrainDiffers(sg *nlbv1.ServerGroup, attr *provider.ServerGroupAttribute) bool {
+	if sg.Spec.ConnectionDrainEnabled != attr.ConnectionDrainEnabled {
+		return true
+	}
+	return sg.Spec.ConnectionDrainEnabled && sg.Spec.ConnectionDrainTimeout != attr.ConnectionDrainTimeout
+}
+
 func (r *ServerGroupReconciler) requeueOnAPIError(err error) ctrl.Result {
 	if provider.IsThrottlingError(err) {
 		return ctrl.Result{RequeueAfter: sgRequeueThrottling}
@@ -265,14 +351,8 @@ func (r *ServerGroupReconciler) requeueOnAPIError(err error) ctrl.Result {
 
 // SetupWithManager wires the controller into the manager.
 func (r *ServerGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	maxConcurrent := r.MaxConcurrentReconciles
-	if maxConcurrent <= 0 {
-		maxConcurrent = 1
-	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nlbv1.ServerGroup{}).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: maxConcurrent,
-		}).
+		WithOptions(controllerOptions(r.MaxConcurrentReconciles)).
 		Complete(r)
 }
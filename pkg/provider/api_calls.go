@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// APICallCounter counts the NLB OpenAPI calls issued on behalf of a single reconcile.
+type APICallCounter struct {
+	count int64
+}
+
+// Count returns the number of API calls recorded so far.
+func (c *APICallCounter) Count() int64 {
+	if c == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&c.count)
+}
+
+type apiCallCounterKey struct{}
+
+// WithAPICallCounter returns a context carrying a fresh APICallCounter. Every
+// NLBClient method called with the returned context records its SDK calls on it.
+func WithAPICallCounter(ctx context.Context) (context.Context, *APICallCounter) {
+	counter := &APICallCounter{}
+	return context.WithValue(ctx, apiCallCounterKey{}, counter), counter
+}
+
+// recordAPICall increments the counter attached to ctx, if any.
+func recordAPICall(ctx context.Context) {
+	if counter, ok := ctx.Value(apiCallCounterKey{}).(*APICallCounter); ok {
+		atomic.AddInt64(&counter.count, 1)
+	}
+}
+
+// DefaultRetryableErrorCodes are the OpenAPI error codes retried by doRequest
+// when NLBClient.RetryableErrorCodes is empty.
+var DefaultRetryableErrorCodes = []string{"Throttling", "ServiceUnavailable", "GetXipFailed"}
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// isRetryable reports whether the OpenAPI error code of err is, or starts with,
+// one of the client's retryable error codes.
+func (c *NLBClient) isRetryable(err error) bool {
+	codes := c.RetryableErrorCodes
+	if len(codes) == 0 {
+		codes = DefaultRetryableErrorCodes
+	}
+	return hasErrorCode(err, codes...)
+}
+
+// doRequest issues an SDK call on behalf of ctx, retrying retryable errors up to
+// NLBClient.MaxRetries times with exponential backoff and jitter. Backoff sleeps
+// are bounded by ctx.
+func doRequest[Req, Resp any](ctx context.Context, c *NLBClient, req Req, call func(Req) (Resp, error)) (Resp, error) {
+	recordAction(ctx, req)
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := doRequestOnce(ctx, c, req, call)
+		if err == nil || attempt >= c.MaxRetries || !c.isRetryable(err) {
+			return resp, err
+		}
+
+		sleep := wait.Jitter(delay, 1.0)
+		klog.V(4).Infof("Retrying NLB OpenAPI request after retryable error (attempt %d/%d, backoff %v): %v",
+			attempt+1, c.MaxRetries, sleep, err)
+		timer := time.NewTimer(sleep)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+// doRequestOnce issues a single SDK call on behalf of ctx once NLBClient.APILimiter
+// grants a token. The SDK is synchronous and ignores cancellation, so the call runs
+// in its own goroutine and doRequestOnce returns as soon as ctx is done or the
+// per-request timeout (NLBClient.RequestTimeout) elapses, leaving the abandoned
+// call to finish in the background. Waiting for a token does not count against
+// RequestTimeout.
+func doRequestOnce[Req, Resp any](ctx context.Context, c *NLBClient, req Req, call func(Req) (Resp, error)) (Resp, error) {
+	recordAPICall(ctx)
+
+	if c.APILimiter != nil {
+		if err := c.APILimiter.Wait(ctx); err != nil {
+			var zero Resp
+			return zero, fmt.Errorf("request aborted while waiting for the API rate limiter: %w", err)
+		}
+	}
+
+	if c.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.RequestTimeout)
+		defer cancel()
+	}
+
+	type result struct {
+		resp Resp
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := call(req)
+		done <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return r.resp, newNLBAPIError(r.err)
+		}
+		return r.resp, nil
+	case <-ctx.Done():
+		var zero Resp
+		return zero, fmt.Errorf("request aborted: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// cancelAfterStart cancels ctx as soon as started is closed and returns the
+// time of cancellation.
+func cancelAfterStart(t *testing.T, started <-chan struct{}, cancel context.CancelFunc) <-chan time.Time {
+	t.Helper()
+	cancelled := make(chan time.Time, 1)
+	go func() {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+		}
+		cancel()
+		cancelled <- time.Now()
+	}()
+	return cancelled
+}
+
+func TestDoRequestReturnsOnCancel(t *testing.T) {
+	c := &NLBClient{MaxRetries: 3}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	// The SDK ignores cancellation, so the call blocks until the test ends
+	call := func(req string) (string, error) {
+		close(started)
+		<-release
+		return "", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := cancelAfterStart(t, started, cancel)
+
+	_, err := doRequest(ctx, c, "req", call)
+	returned := time.Now()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if d := returned.Sub(<-cancelled); d > time.Second {
+		t.Errorf("doRequest returned %s after cancellation", d)
+	}
+}
+
+func TestWaitJobFinishReturnsOnCancel(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-started:
+		default:
+			close(started)
+		}
+		<-release
+	}))
+	// Unblock the abandoned GetJobStatus call before the server shuts down
+	defer server.Close()
+	defer close(release)
+
+	sdk, err := nlbsdk.NewClient(&openapi.Config{
+		AccessKeyId:     tea.String("ak"),
+		AccessKeySecret: tea.String("secret"),
+		RegionId:        tea.String("cn-hangzhou"),
+		Endpoint:        tea.String(strings.TrimPrefix(server.URL, "http://")),
+		Protocol:        tea.String("http"),
+	})
+	if err != nil {
+		t.Fatalf("failed to create SDK client: %v", err)
+	}
+	c := &NLBClient{client: sdk, lbCache: newLBAttributeCache(), JobPollTimeout: 10 * time.Minute}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := cancelAfterStart(t, started, cancel)
+
+	err = c.waitJobFinish(ctx, "job-1", c.JobPollTimeout)
+	returned := time.Now()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if d := returned.Sub(<-cancelled); d > time.Second {
+		t.Errorf("waitJobFinish returned %s after cancellation, the poll timeout is %s", d, c.JobPollTimeout)
+	}
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// BackendStatusHealthy is reported for backend servers that GetListenerHealthStatus
+// does not list as abnormal. The other states (Unhealthy, Initial, Unavailable) are
+// passed through from the API.
+const BackendStatusHealthy = "Healthy"
+
+// BackendServerHealth is the health of a single backend server behind a listener.
+type BackendServerHealth struct {
+	ServerId string
+	ServerIp string
+	Port     int32
+	Status   string
+	// Reason is the reason code of an abnormal status, e.g. CONNECT_TIMEOUT
+	Reason string
+}
+
+// ServerGroupHealth is the health of the backend servers of one server group
+// attached to a listener.
+type ServerGroupHealth struct {
+	ServerGroupId string
+	Servers       []BackendServerHealth
+}
+
+// GetListenerBackendHealth returns the health of every backend server behind the
+// listener. GetListenerHealthStatus only reports abnormal servers, so the members
+// of each server group are listed with ListServerGroupServers and the remaining
+// ones reported as Healthy. GetListenerHealthStatusRequest in the v4 SDK takes no
+// NextToken, so only the first page of abnormal servers is returned.
+func (c *NLBClient) GetListenerBackendHealth(ctx context.Context, listenerId string) ([]ServerGroupHealth, error) {
+	req := &nlbsdk.GetListenerHealthStatusRequest{
+		ListenerId: tea.String(listenerId),
+	}
+
+	resp, err := doRequest(ctx, c, req, c.client.GetListenerHealthStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health status of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return nil, fmt.Errorf("invalid response from GetListenerHealthStatus API")
+	}
+
+	var groups []ServerGroupHealth
+	for _, lsn := range resp.Body.ListenerHealthStatus {
+		if lsn == nil {
+			continue
+		}
+		for _, info := range lsn.ServerGroupInfos {
+			if info == nil {
+				continue
+			}
+			group, err := c.serverGroupHealth(ctx, info)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// serverGroupHealth merges the abnormal servers reported for a server group with
+// its full member list.
+func (c *NLBClient) serverGroupHealth(ctx context.Context, info *nlbsdk.GetListenerHealthStatusResponseBodyListenerHealthStatusServerGroupInfos) (ServerGroupHealth, error) {
+	sgId := tea.StringValue(info.ServerGroupId)
+	group := ServerGroupHealth{ServerGroupId: sgId}
+
+	abnormal := make(map[string]BackendServerHealth, len(info.NonNormalServers))
+	for _, s := range info.NonNormalServers {
+		if s == nil {
+			continue
+		}
+		h := BackendServerHealth{
+			ServerId: tea.StringValue(s.ServerId),
+			ServerIp: tea.StringValue(s.ServerIp),
+			Port:     tea.Int32Value(s.Port),
+			Status:   tea.StringValue(s.Status),
+		}
+		if s.Reason != nil {
+			h.Reason = tea.StringValue(s.Reason.ReasonCode)
+		}
+		abnormal[backendKey(h.ServerId, h.ServerIp, h.Port)] = h
+	}
+
+	members, err := c.ListServerGroupServers(ctx, sgId)
+	if err != nil {
+		return group, err
+	}
+	for _, m := range members {
+		key := backendKey(m.ServerId, m.ServerIp, m.Port)
+		if h, ok := abnormal[key]; ok {
+			group.Servers = append(group.Servers, h)
+			delete(abnormal, key)
+			continue
+		}
+		m.Status = BackendStatusHealthy
+		group.Servers = append(group.Servers, m)
+	}
+	// Abnormal servers missing from the member list, e.g. removed meanwhile
+	for _, h := range abnormal {
+		group.Servers = append(group.Servers, h)
+	}
+	return group, nil
+}
+
+// ListServerGroupServers returns the backend servers registered in a server group.
+// Only the identity of each server is filled in; Status is left empty.
+func (c *NLBClient) ListServerGroupServers(ctx context.Context, sgId string) ([]BackendServerHealth, error) {
+	req := &nlbsdk.ListServerGroupServersRequest{
+		ServerGroupId: tea.String(sgId),
+	}
+
+	var servers []BackendServerHealth
+	for {
+		resp, err := doRequest(ctx, c, req, c.client.ListServerGroupServers)
+		if err != nil {
+			if IsNotFoundError(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to list servers of server group %s: %w", sgId, err)
+		}
+		if resp == nil || resp.Body == nil {
+			return nil, fmt.Errorf("invalid response from ListServerGroupServers API")
+		}
+		for _, s := range resp.Body.Servers {
+			if s == nil {
+				continue
+			}
+			servers = append(servers, BackendServerHealth{
+				ServerId: tea.StringValue(s.ServerId),
+				ServerIp: tea.StringValue(s.ServerIp),
+				Port:     tea.Int32Value(s.Port),
+			})
+		}
+		next := tea.StringValue(resp.Body.NextToken)
+		if next == "" {
+			return servers, nil
+		}
+		req.NextToken = tea.String(next)
+	}
+}
+
+func backendKey(serverId, serverIp string, port int32) string {
+	return fmt.Sprintf("%s/%s:%d", serverId, serverIp, port)
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aliyun/credentials-go/credentials"
+	"golang.org/x/time/rate"
+)
+
+// ClientPool hands out one NLBClient per region, so a single operator instance
+// can manage NLBs in several regions. Clients for non-default regions are created
+// lazily on first use; they share the pool's credential provider and copy the
+// tuning (timeouts, retries, rate limits) of the default client at that time.
+type ClientPool struct {
+	defaultRegion string
+	endpoints     EndpointConfig
+	cred          credentials.Credential
+	userAgent     string
+
+	mu      sync.Mutex
+	clients map[string]*NLBClient
+}
+
+// NewClientPool creates a pool whose default client targets defaultRegion.
+// endpoints selects the API endpoint of each region; userAgent is sent with the
+// requests of every client, see UserAgent.
+func NewClientPool(endpoints EndpointConfig, defaultRegion string, credConfig CredentialConfig, userAgent string) (*ClientPool, error) {
+	if err := endpoints.Validate(defaultRegion); err != nil {
+		return nil, err
+	}
+	cred, err := newCredential(credConfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newNLBClient(endpoints.endpointFor(defaultRegion, true), defaultRegion, cred, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientPool{
+		defaultRegion: defaultRegion,
+		endpoints:     endpoints,
+		cred:          cred,
+		userAgent:     userAgent,
+		clients:       map[string]*NLBClient{defaultRegion: client},
+	}, nil
+}
+
+// DefaultRegion returns the region used when a resource does not specify one.
+func (p *ClientPool) DefaultRegion() string {
+	return p.defaultRegion
+}
+
+// Default returns the client for the default region.
+func (p *ClientPool) Default() *NLBClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clients[p.defaultRegion]
+}
+
+// Get returns the client for region, creating it on first use. An empty region
+// selects the default region.
+func (p *ClientPool) Get(region string) (*NLBClient, error) {
+	if region == "" {
+		region = p.defaultRegion
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[region]; ok {
+		return client, nil
+	}
+
+	client, err := newNLBClient(p.endpoints.endpointFor(region, false), region, p.cred, p.userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NLB client for region %s: %w", region, err)
+	}
+	client.copySettings(p.clients[p.defaultRegion])
+	p.clients[region] = client
+	return client, nil
+}
+
+// copySettings copies the tuning of tmpl onto c. Rate limiters are not shared:
+// OpenAPI quotas apply per region, so c gets its own buckets with the same limits.
+func (c *NLBClient) copySettings(tmpl *NLBClient) {
+	if tmpl == nil {
+		return
+	}
+	if tmpl.GetListenerLimiter != nil {
+		c.GetListenerLimiter = rate.NewLimiter(tmpl.GetListenerLimiter.Limit(), tmpl.GetListenerLimiter.Burst())
+	}
+	if tmpl.CreateListenerLimiter != nil {
+		c.CreateListenerLimiter = rate.NewLimiter(tmpl.CreateListenerLimiter.Limit(), tmpl.CreateListenerLimiter.Burst())
+	}
+	if tmpl.APILimiter != nil {
+		c.APILimiter = rate.NewLimiter(tmpl.APILimiter.Limit(), tmpl.APILimiter.Burst())
+	}
+	c.RequestTimeout = tmpl.RequestTimeout
+	c.MaxRetries = tmpl.MaxRetries
+	c.RetryableErrorCodes = tmpl.RetryableErrorCodes
+	c.LoadBalancerOperationTimeout = tmpl.LoadBalancerOperationTimeout
+	c.ListenerOperationTimeout = tmpl.ListenerOperationTimeout
+	c.JobPollInterval = tmpl.JobPollInterval
+	c.JobPollTimeout = tmpl.JobPollTimeout
+	c.AttributeCacheTTL = tmpl.AttributeCacheTTL
+	c.ShutdownGracePeriod = tmpl.ShutdownGracePeriod
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/aliyun/credentials-go/credentials"
+)
+
+// Credential modes supported by NewNLBClient.
+const (
+	// CredentialModeAccessKey uses a static AccessKey pair (local development).
+	CredentialModeAccessKey = "access_key"
+	// CredentialModeSTS uses a static STS token (AccessKey pair + SecurityToken).
+	// The token is not refreshed; restart with a new token before it expires.
+	CredentialModeSTS = "sts"
+	// CredentialModeECSRAMRole obtains and refreshes STS credentials from the ECS
+	// instance metadata service for the RAM role attached to the node.
+	CredentialModeECSRAMRole = "ecs_ram_role"
+	// CredentialModeRAMRoleArn assumes RoleArn with the given AccessKey pair and
+	// refreshes the resulting STS credentials before they expire.
+	CredentialModeRAMRoleArn = "ram_role_arn"
+)
+
+// CredentialConfig describes how NLBClient authenticates against the OpenAPI.
+type CredentialConfig struct {
+	// Mode is one of the CredentialMode* constants. Defaults to CredentialModeAccessKey.
+	Mode string
+
+	AccessKeyId     string
+	AccessKeySecret string
+	// SecurityToken is required for CredentialModeSTS.
+	SecurityToken string
+	// RoleName is the ECS RAM role name for CredentialModeECSRAMRole.
+	// When empty, the role attached to the instance is discovered from metadata.
+	RoleName string
+	// RoleArn and RoleSessionName are used by CredentialModeRAMRoleArn.
+	RoleArn         string
+	RoleSessionName string
+}
+
+// Validate checks that the fields required by the selected mode are set.
+func (cfg CredentialConfig) Validate() error {
+	switch cfg.mode() {
+	case CredentialModeAccessKey:
+		if cfg.AccessKeyId == "" || cfg.AccessKeySecret == "" {
+			return fmt.Errorf("credential mode %s requires access key id and secret", CredentialModeAccessKey)
+		}
+	case CredentialModeSTS:
+		if cfg.AccessKeyId == "" || cfg.AccessKeySecret == "" || cfg.SecurityToken == "" {
+			return fmt.Errorf("credential mode %s requires access key id, secret and security token", CredentialModeSTS)
+		}
+	case CredentialModeECSRAMRole:
+	case CredentialModeRAMRoleArn:
+		if cfg.AccessKeyId == "" || cfg.AccessKeySecret == "" || cfg.RoleArn == "" {
+			return fmt.Errorf("credential mode %s requires access key id, secret and role arn", CredentialModeRAMRoleArn)
+		}
+	default:
+		return fmt.Errorf("unsupported credential mode %q", cfg.Mode)
+	}
+	return nil
+}
+
+func (cfg CredentialConfig) mode() string {
+	if cfg.Mode == "" {
+		return CredentialModeAccessKey
+	}
+	return cfg.Mode
+}
+
+// newCredential builds a credentials-go provider. Modes backed by STS
+// (ecs_ram_role, ram_role_arn) refresh transparently on each SDK call.
+func newCredential(cfg CredentialConfig) (credentials.Credential, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	config := new(credentials.Config).SetType(cfg.mode())
+	switch cfg.mode() {
+	case CredentialModeAccessKey:
+		config.SetAccessKeyId(cfg.AccessKeyId).SetAccessKeySecret(cfg.AccessKeySecret)
+	case CredentialModeSTS:
+		config.SetAccessKeyId(cfg.AccessKeyId).SetAccessKeySecret(cfg.AccessKeySecret).SetSecurityToken(cfg.SecurityToken)
+	case CredentialModeECSRAMRole:
+		if cfg.RoleName != "" {
+			config.SetRoleName(cfg.RoleName)
+		}
+	case CredentialModeRAMRoleArn:
+		sessionName := cfg.RoleSessionName
+		if sessionName == "" {
+			sessionName = "nlb-operator"
+		}
+		config.SetAccessKeyId(cfg.AccessKeyId).SetAccessKeySecret(cfg.AccessKeySecret).
+			SetRoleArn(cfg.RoleArn).SetRoleSessionName(sessionName)
+	}
+
+	cred, err := credentials.NewCredential(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s credential: %w", cfg.mode(), err)
+	}
+	return cred, nil
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Network types selecting the default NLB API endpoint of a region.
+const (
+	// NetworkTypePublic uses the SDK's public endpoint, e.g. nlb.cn-hangzhou.aliyuncs.com
+	NetworkTypePublic = "public"
+	// NetworkTypeVPC uses the VPC endpoint, e.g. nlb-vpc.cn-hangzhou.aliyuncs.com
+	NetworkTypeVPC = "vpc"
+)
+
+// regionPlaceholder is replaced by the region ID in EndpointConfig.Template.
+const regionPlaceholder = "{region}"
+
+// EndpointConfig selects the NLB API endpoint of each region. The first match wins:
+// RegionEndpoints, Endpoint (default region only), Template, then the default
+// endpoint of NetworkType.
+type EndpointConfig struct {
+	// Endpoint is the endpoint of the default region
+	Endpoint string
+	// RegionEndpoints are endpoints of individual regions, keyed by region ID
+	RegionEndpoints map[string]string
+	// Template builds the endpoint of a region by replacing "{region}" with its ID,
+	// e.g. "nlb-vpc.{region}.aliyuncs.com"
+	Template string
+	// NetworkType is NetworkTypePublic (the default) or NetworkTypeVPC
+	NetworkType string
+}
+
+// Validate checks the network type and template, and that the explicit endpoints
+// of defaultRegion and RegionEndpoints do not name another region.
+func (c EndpointConfig) Validate(defaultRegion string) error {
+	switch c.NetworkType {
+	case "", NetworkTypePublic, NetworkTypeVPC:
+	default:
+		return fmt.Errorf("unsupported network type %q, must be %s or %s", c.NetworkType, NetworkTypePublic, NetworkTypeVPC)
+	}
+	if c.Template != "" && !strings.Contains(c.Template, regionPlaceholder) {
+		return fmt.Errorf("endpoint template %q does not contain %s", c.Template, regionPlaceholder)
+	}
+	if region := endpointRegion(c.Endpoint); region != "" && defaultRegion != "" && region != defaultRegion {
+		return fmt.Errorf("endpoint %q is in region %s, not in the default region %s", c.Endpoint, region, defaultRegion)
+	}
+	for regionId, ep := range c.RegionEndpoints {
+		if region := endpointRegion(ep); region != "" && region != regionId {
+			return fmt.Errorf("endpoint %q of region %s is in region %s", ep, regionId, region)
+		}
+	}
+	return nil
+}
+
+// endpointRegion returns the region of a regional NLB API endpoint, such as
+// nlb.cn-hangzhou.aliyuncs.com or nlb-vpc.cn-hangzhou.aliyuncs.com, or "" for
+// other endpoints (proxies, central endpoints) whose region cannot be told.
+func endpointRegion(endpoint string) string {
+	host := endpoint
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+	host, _, _ = strings.Cut(host, ":")
+	labels := strings.Split(strings.ToLower(host), ".")
+	if len(labels) != 4 || labels[2] != "aliyuncs" || labels[3] != "com" {
+		return ""
+	}
+	if labels[0] != "nlb" && labels[0] != "nlb-vpc" {
+		return ""
+	}
+	return labels[1]
+}
+
+// endpointFor returns the endpoint of region, or "" to let the SDK resolve its
+// public endpoint.
+func (c EndpointConfig) endpointFor(region string, isDefault bool) string {
+	if ep := c.RegionEndpoints[region]; ep != "" {
+		return ep
+	}
+	if isDefault && c.Endpoint != "" {
+		return c.Endpoint
+	}
+	if c.Template != "" {
+		return strings.ReplaceAll(c.Template, regionPlaceholder, region)
+	}
+	if c.NetworkType == NetworkTypeVPC {
+		return fmt.Sprintf("nlb-vpc.%s.aliyuncs.com", region)
+	}
+	return ""
+}
+
+// ParseRegionEndpoints parses a comma separated list of region=endpoint pairs.
+func ParseRegionEndpoints(value string) (map[string]string, error) {
+	endpoints := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		region, ep, ok := strings.Cut(pair, "=")
+		region, ep = strings.TrimSpace(region), strings.TrimSpace(ep)
+		if !ok || region == "" || ep == "" {
+			return nil, fmt.Errorf("invalid region endpoint %q, expected region=endpoint", pair)
+		}
+		endpoints[region] = ep
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+var (
+	// ErrResourceNotFound matches NLB OpenAPI errors reporting that the requested
+	// resource does not exist.
+	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrThrottling matches NLB OpenAPI errors reporting that the request was throttled.
+	ErrThrottling = errors.New("request throttled")
+)
+
+// NLBAPIError is an error returned by the NLB OpenAPI. It keeps the SDK error text
+// and adds the error code, which the Is*Error classifiers match, and the
+// RequestId that Alibaba Cloud support asks for.
+type NLBAPIError struct {
+	// Code is the OpenAPI error code, e.g. "ResourceNotFound.loadBalancer"
+	Code string
+	// RequestId identifies the failed request on the cloud side
+	RequestId string
+
+	err error
+}
+
+// Error returns the SDK error text followed by the RequestId, so it reaches
+// conditions and events wherever the error is formatted. An error built without
+// an SDK error, as by the fake client, reports its code instead.
+func (e *NLBAPIError) Error() string {
+	msg := e.Code
+	if e.err != nil {
+		msg = e.err.Error()
+	}
+	if e.RequestId == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s (RequestId: %s)", msg, e.RequestId)
+}
+
+// Unwrap returns the underlying SDK error.
+func (e *NLBAPIError) Unwrap() error {
+	return e.err
+}
+
+// Is classifies the error code, so callers can use errors.Is with
+// ErrResourceNotFound and ErrThrottling.
+func (e *NLBAPIError) Is(target error) bool {
+	switch target {
+	case ErrResourceNotFound:
+		return strings.Contains(e.Code, "NotFound")
+	case ErrThrottling:
+		return strings.HasPrefix(e.Code, "Throttling") ||
+			strings.HasPrefix(e.Code, "RequestLimitExceeded") ||
+			strings.HasPrefix(e.Code, "ServiceUnavailable")
+	}
+	return false
+}
+
+// requestIdPattern matches the RequestId the OpenAPI client embeds in SDK error messages.
+var requestIdPattern = regexp.MustCompile(`request id: ([\w-]+)`)
+
+// newNLBAPIError wraps an SDK error into an NLBAPIError; other errors are returned as is.
+func newNLBAPIError(err error) error {
+	var sdkErr *tea.SDKError
+	if !errors.As(err, &sdkErr) {
+		return err
+	}
+
+	apiErr := &NLBAPIError{Code: tea.StringValue(sdkErr.Code), err: err}
+	var data struct {
+		RequestId string
+	}
+	if sdkErr.Data != nil && json.Unmarshal([]byte(tea.StringValue(sdkErr.Data)), &data) == nil {
+		apiErr.RequestId = data.RequestId
+	}
+	if apiErr.RequestId == "" {
+		if m := requestIdPattern.FindStringSubmatch(tea.StringValue(sdkErr.Message)); m != nil {
+			apiErr.RequestId = m[1]
+		}
+	}
+	return apiErr
+}
+
+// ErrorCode returns the OpenAPI error code of the NLB OpenAPI error wrapped by err,
+// or "" when err did not come from the OpenAPI.
+func ErrorCode(err error) string {
+	var apiErr *NLBAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// hasErrorCode reports whether the OpenAPI error code of err is, or starts with,
+// one of codes, e.g. "InvalidParam" matches "InvalidParameter" and
+// "InvalidParam.ZoneId". Errors that did not come from the OpenAPI never match,
+// whatever their text.
+func hasErrorCode(err error, codes ...string) bool {
+	code := ErrorCode(err)
+	if code == "" {
+		return false
+	}
+	for _, c := range codes {
+		if strings.HasPrefix(code, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestIdFromError returns the RequestId of the NLB OpenAPI error wrapped by err,
+// or "" when err did not come from the OpenAPI.
+func RequestIdFromError(err error) string {
+	var apiErr *NLBAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestId
+	}
+	return ""
+}
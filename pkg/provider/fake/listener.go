@@ -0,0 +1,261 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/alibabacloud-go/tea/tea"
+
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+// listener returns the listener listenerId, or nil. The returned pointer
+// aliases the entry in Listeners. It must be called with f.mu held.
+func (f *NLBClient) listener(listenerId string) *provider.ListenerAttribute {
+	for lbId := range f.Listeners {
+		for i := range f.Listeners[lbId] {
+			if f.Listeners[lbId][i].ListenerId == listenerId {
+				return &f.Listeners[lbId][i]
+			}
+		}
+	}
+	return nil
+}
+
+// update applies fn to the listener listenerId, or fails with
+// ErrResourceNotFound. It must be called with f.mu held.
+func (f *NLBClient) update(listenerId string, fn func(l *provider.ListenerAttribute)) error {
+	l := f.listener(listenerId)
+	if l == nil {
+		return fmt.Errorf("listener %s: %w", listenerId, provider.ErrResourceNotFound)
+	}
+	fn(l)
+	return nil
+}
+
+func (f *NLBClient) CreateNLBListener(ctx context.Context, nlbId, sgId string, port int32, protocol string, opts provider.ListenerOptions) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("CreateNLBListener"); err != nil {
+		return "", err
+	}
+
+	for _, l := range f.Listeners[nlbId] {
+		if l.ListenerPort == port {
+			return "", fmt.Errorf("port %d of %s is used by %s: %w", port, nlbId, l.ListenerId, APIError("ListenerAlreadyExists"))
+		}
+	}
+	f.nextId++
+	l := provider.ListenerAttribute{
+		ListenerId:          fmt.Sprintf("lsn-fake%d", f.nextId),
+		ListenerStatus:      "Running",
+		ListenerPort:        port,
+		ListenerProtocol:    protocol,
+		LoadBalancerId:      nlbId,
+		ServerGroupId:       sgId,
+		ListenerDescription: opts.Description,
+		Cps:                 tea.Int32Value(opts.Cps),
+		IdleTimeout:         tea.Int32Value(opts.IdleTimeout),
+		SecurityPolicyId:    opts.SecurityPolicyId,
+	}
+	if opts.ProxyProtocol != nil {
+		l.ProxyProtocolEnabled = opts.ProxyProtocol.Enabled
+	}
+	if opts.Alpn != nil {
+		l.AlpnEnabled, l.AlpnPolicy = opts.Alpn.Enabled, opts.Alpn.Policy
+	}
+	if opts.CA != nil {
+		l.CaEnabled, l.CaCertificateIds = opts.CA.Enabled, slices.Clone(opts.CA.CertificateIds)
+	}
+	f.Listeners[nlbId] = append(f.Listeners[nlbId], l)
+	return l.ListenerId, nil
+}
+
+func (f *NLBClient) GetListenerAttribute(ctx context.Context, listenerId string) (*provider.ListenerAttribute, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetListenerAttribute"); err != nil {
+		return nil, err
+	}
+
+	// As with the real client, a missing listener is not an error
+	l := f.listener(listenerId)
+	if l == nil {
+		return nil, nil
+	}
+	attr := *l
+	attr.CaCertificateIds = slices.Clone(l.CaCertificateIds)
+	return &attr, nil
+}
+
+func (f *NLBClient) ListListeners(ctx context.Context, nlbId string, port int32) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListListeners"); err != nil {
+		return "", err
+	}
+
+	for _, l := range f.Listeners[nlbId] {
+		if l.ListenerPort == port {
+			return l.ListenerId, nil
+		}
+	}
+	return "", nil
+}
+
+func (f *NLBClient) DeleteNLBListener(ctx context.Context, listenerId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DeleteNLBListener"); err != nil {
+		return err
+	}
+
+	for lbId, listeners := range f.Listeners {
+		f.Listeners[lbId] = slices.DeleteFunc(listeners, func(l provider.ListenerAttribute) bool {
+			return l.ListenerId == listenerId
+		})
+	}
+	delete(f.AdditionalCertificates, listenerId)
+	return nil
+}
+
+func (f *NLBClient) StartListener(ctx context.Context, listenerId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("StartListener"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.ListenerStatus = "Running" })
+}
+
+func (f *NLBClient) StopListener(ctx context.Context, listenerId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("StopListener"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.ListenerStatus = "Stopped" })
+}
+
+func (f *NLBClient) UpdateListenerServerGroup(ctx context.Context, listenerId, sgId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerServerGroup"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.ServerGroupId = sgId })
+}
+
+func (f *NLBClient) UpdateListenerDescription(ctx context.Context, listenerId, description string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerDescription"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.ListenerDescription = description })
+}
+
+func (f *NLBClient) UpdateListenerCps(ctx context.Context, listenerId string, cps int32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerCps"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.Cps = cps })
+}
+
+func (f *NLBClient) UpdateListenerIdleTimeout(ctx context.Context, listenerId, protocol string, idleTimeout int32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerIdleTimeout"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.IdleTimeout = idleTimeout })
+}
+
+func (f *NLBClient) UpdateListenerProxyProtocol(ctx context.Context, listenerId string, pp provider.ListenerProxyProtocol) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerProxyProtocol"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) {
+		l.ProxyProtocolEnabled = pp.Enabled
+		if pp.V2 != nil {
+			l.ProxyProtocolV2 = *pp.V2
+		}
+	})
+}
+
+func (f *NLBClient) UpdateListenerAlpn(ctx context.Context, listenerId string, alpn provider.ListenerAlpn) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerAlpn"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) {
+		l.AlpnEnabled = alpn.Enabled
+		if alpn.Enabled {
+			l.AlpnPolicy = alpn.Policy
+		}
+	})
+}
+
+func (f *NLBClient) UpdateListenerSecurityPolicy(ctx context.Context, listenerId, policyId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerSecurityPolicy"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) { l.SecurityPolicyId = policyId })
+}
+
+func (f *NLBClient) UpdateListenerCA(ctx context.Context, listenerId string, ca provider.ListenerCA) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateListenerCA"); err != nil {
+		return err
+	}
+	return f.update(listenerId, func(l *provider.ListenerAttribute) {
+		l.CaEnabled = ca.Enabled
+		if len(ca.CertificateIds) > 0 {
+			l.CaCertificateIds = slices.Clone(ca.CertificateIds)
+		}
+	})
+}
+
+func (f *NLBClient) ListAdditionalCertificates(ctx context.Context, listenerId string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListAdditionalCertificates"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.AdditionalCertificates[listenerId]), nil
+}
+
+func (f *NLBClient) AssociateAdditionalCertificates(ctx context.Context, listenerId string, certIds []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("AssociateAdditionalCertificates"); err != nil {
+		return err
+	}
+	for _, id := range certIds {
+		if !slices.Contains(f.AdditionalCertificates[listenerId], id) {
+			f.AdditionalCertificates[listenerId] = append(f.AdditionalCertificates[listenerId], id)
+		}
+	}
+	return nil
+}
+
+func (f *NLBClient) DissociateAdditionalCertificates(ctx context.Context, listenerId string, certIds []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DissociateAdditionalCertificates"); err != nil {
+		return err
+	}
+	f.AdditionalCertificates[listenerId] = slices.DeleteFunc(f.AdditionalCertificates[listenerId], func(id string) bool {
+		return slices.Contains(certIds, id)
+	})
+	return nil
+}
@@ -0,0 +1,553 @@
+// Package fake provides an in-memory provider.NLBClientInterface and
+// provider.ListenerClientInterface, so the NLB and Listener reconcilers can be
+// exercised without calling the NLB OpenAPI.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+)
+
+// NLBClient is an in-memory NLB API. Instances, tags, listeners and jobs are kept
+// in its exported maps, which tests may seed and inspect between calls; every
+// call is recorded in Calls and fails with Errors[method] when set.
+type NLBClient struct {
+	mu sync.Mutex
+
+	// LoadBalancers holds the instances by ID
+	LoadBalancers map[string]*nlbsdk.GetLoadBalancerAttributeResponseBody
+	// Tags holds the tags of each instance by ID
+	Tags map[string]map[string]string
+	// Listeners holds the listeners of each instance by ID
+	Listeners map[string][]provider.ListenerAttribute
+	// AdditionalCertificates holds the SNI certificates of each listener by ID
+	AdditionalCertificates map[string][]string
+	// BackendHealth holds the backend health of each listener by ID
+	BackendHealth map[string][]provider.ServerGroupHealth
+	// JobStatuses holds the status of each async job by ID
+	JobStatuses map[string]string
+	// Zones is returned by DescribeZones
+	Zones []string
+
+	// CreateStatus is the status of instances created by CreateLoadBalancer.
+	// Defaults to Active when empty
+	CreateStatus string
+
+	// Errors makes the named method fail with the given error
+	Errors map[string]error
+	// Calls records the name of every method called, in order
+	Calls []string
+
+	nextId int
+}
+
+var (
+	_ provider.NLBClientInterface      = &NLBClient{}
+	_ provider.ListenerClientInterface = &NLBClient{}
+)
+
+// NewNLBClient returns an empty fake NLBClient.
+func NewNLBClient() *NLBClient {
+	return &NLBClient{
+		LoadBalancers:          make(map[string]*nlbsdk.GetLoadBalancerAttributeResponseBody),
+		Tags:                   make(map[string]map[string]string),
+		Listeners:              make(map[string][]provider.ListenerAttribute),
+		AdditionalCertificates: make(map[string][]string),
+		BackendHealth:          make(map[string][]provider.ServerGroupHealth),
+		JobStatuses:            make(map[string]string),
+		Errors:                 make(map[string]error),
+	}
+}
+
+// APIError returns an NLB OpenAPI error with code, classified like the errors of
+// the real client, for injection through Errors.
+func APIError(code string) error {
+	return &provider.NLBAPIError{Code: code}
+}
+
+// CallCount returns how many times method was called.
+func (f *NLBClient) CallCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, call := range f.Calls {
+		if call == method {
+			n++
+		}
+	}
+	return n
+}
+
+// call records a call to method and returns the error injected for it, if any.
+// It must be called with f.mu held.
+func (f *NLBClient) call(method string) error {
+	f.Calls = append(f.Calls, method)
+	return f.Errors[method]
+}
+
+// get returns the instance lbId, or ErrResourceNotFound. It must be called with
+// f.mu held.
+func (f *NLBClient) get(lbId string) (*nlbsdk.GetLoadBalancerAttributeResponseBody, error) {
+	lb, ok := f.LoadBalancers[lbId]
+	if !ok {
+		return nil, fmt.Errorf("load balancer %s: %w", lbId, provider.ErrResourceNotFound)
+	}
+	return lb, nil
+}
+
+func (f *NLBClient) CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB, tags, provenanceTags []nlbv1.Tag) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("CreateLoadBalancer"); err != nil {
+		return "", err
+	}
+
+	f.nextId++
+	lbId := fmt.Sprintf("nlb-fake%d", f.nextId)
+	status := f.CreateStatus
+	if status == "" {
+		status = "Active"
+	}
+	lb := &nlbsdk.GetLoadBalancerAttributeResponseBody{
+		LoadBalancerId:     tea.String(lbId),
+		LoadBalancerName:   tea.String(nlb.Spec.LoadBalancerName),
+		LoadBalancerStatus: tea.String(status),
+		AddressType:        tea.String(nlb.Spec.AddressType),
+		VpcId:              tea.String(nlb.Spec.VpcId),
+		DNSName:            tea.String(lbId + ".fake.nlb.aliyuncs.com"),
+		SecurityGroupIds:   tea.StringSlice(nlb.Spec.SecurityGroupIds),
+		ZoneMappings:       zoneMappings(nlb.Spec.ZoneMappings),
+	}
+	if nlb.Spec.AddressIpVersion != "" {
+		lb.AddressIpVersion = tea.String(nlb.Spec.AddressIpVersion)
+	}
+	if nlb.Spec.AddressIpVersion == "DualStack" {
+		// As with the real API, a new instance starts with an Intranet IPv6 address
+		lb.Ipv6AddressType = tea.String("Intranet")
+	}
+	if nlb.Spec.ResourceGroupId != "" {
+		lb.ResourceGroupId = tea.String(nlb.Spec.ResourceGroupId)
+	}
+	if nlb.Spec.BandwidthPackageId != "" {
+		lb.BandwidthPackageId = tea.String(nlb.Spec.BandwidthPackageId)
+	}
+	if nlb.Spec.DeletionProtection != nil {
+		lb.DeletionProtectionConfig = &nlbsdk.GetLoadBalancerAttributeResponseBodyDeletionProtectionConfig{
+			Enabled: tea.Bool(nlb.Spec.DeletionProtection.Enabled),
+			Reason:  tea.String(nlb.Spec.DeletionProtection.Reason),
+		}
+	}
+	if nlb.Spec.ModificationProtection != nil {
+		lb.ModificationProtectionConfig = &nlbsdk.GetLoadBalancerAttributeResponseBodyModificationProtectionConfig{
+			Status: tea.String(nlb.Spec.ModificationProtection.Status),
+			Reason: tea.String(nlb.Spec.ModificationProtection.Reason),
+		}
+	}
+	f.LoadBalancers[lbId] = lb
+
+	lbTags := make(map[string]string)
+	for _, tag := range append(slices.Clone(tags), provenanceTags...) {
+		lbTags[tag.Key] = tag.Value
+	}
+	f.Tags[lbId] = lbTags
+	return lbId, nil
+}
+
+func (f *NLBClient) DeleteLoadBalancer(ctx context.Context, lbId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DeleteLoadBalancer"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		// Deleting a missing instance succeeds, as with the real API
+		return nil
+	}
+	if lb.DeletionProtectionConfig != nil && tea.BoolValue(lb.DeletionProtectionConfig.Enabled) {
+		return fmt.Errorf("failed to delete load balancer: deletion protection is enabled on %s", lbId)
+	}
+	delete(f.LoadBalancers, lbId)
+	delete(f.Tags, lbId)
+	delete(f.Listeners, lbId)
+	return nil
+}
+
+func (f *NLBClient) GetLoadBalancer(ctx context.Context, lbId string) (*nlbsdk.GetLoadBalancerAttributeResponseBody, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetLoadBalancer"); err != nil {
+		return nil, err
+	}
+
+	lb, ok := f.LoadBalancers[lbId]
+	if !ok {
+		return nil, nil
+	}
+	// Hand out a copy so callers cannot change the stored instance
+	cp := *lb
+	return &cp, nil
+}
+
+func (f *NLBClient) GetLoadBalancerCached(ctx context.Context, lbId string) (*nlbsdk.GetLoadBalancerAttributeResponseBody, error) {
+	return f.GetLoadBalancer(ctx, lbId)
+}
+
+func (f *NLBClient) InvalidateLoadBalancer(lbId string) {}
+
+func (f *NLBClient) FindLoadBalancerByName(ctx context.Context, vpcId, name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("FindLoadBalancerByName"); err != nil {
+		return "", err
+	}
+
+	for id, lb := range f.LoadBalancers {
+		if tea.StringValue(lb.VpcId) == vpcId && tea.StringValue(lb.LoadBalancerName) == name {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+func (f *NLBClient) ListLoadBalancers(ctx context.Context, filter provider.LoadBalancerFilter) ([]provider.LoadBalancerSummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListLoadBalancers"); err != nil {
+		return nil, err
+	}
+
+	var summaries []provider.LoadBalancerSummary
+	for id, lb := range f.LoadBalancers {
+		if len(filter.LoadBalancerIds) > 0 && !slices.Contains(filter.LoadBalancerIds, id) {
+			continue
+		}
+		if filter.ResourceGroupId != "" && tea.StringValue(lb.ResourceGroupId) != filter.ResourceGroupId {
+			continue
+		}
+		matches := true
+		for k, v := range filter.Tags {
+			if f.Tags[id][k] != v {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		tags := make(map[string]string, len(f.Tags[id]))
+		for k, v := range f.Tags[id] {
+			tags[k] = v
+		}
+		summaries = append(summaries, provider.LoadBalancerSummary{
+			LoadBalancerId:     id,
+			LoadBalancerName:   tea.StringValue(lb.LoadBalancerName),
+			LoadBalancerStatus: tea.StringValue(lb.LoadBalancerStatus),
+			VpcId:              tea.StringValue(lb.VpcId),
+			ResourceGroupId:    tea.StringValue(lb.ResourceGroupId),
+			Tags:               tags,
+		})
+	}
+	return summaries, nil
+}
+
+func (f *NLBClient) DescribeZones(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DescribeZones"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Zones), nil
+}
+
+func (f *NLBClient) GetJobStatus(ctx context.Context, jobId string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetJobStatus"); err != nil {
+		return "", err
+	}
+
+	status, ok := f.JobStatuses[jobId]
+	if !ok {
+		return "", fmt.Errorf("failed to get status of job %s: %w", jobId, provider.ErrResourceNotFound)
+	}
+	return status, nil
+}
+
+func (f *NLBClient) UpdateLoadBalancerAttribute(ctx context.Context, lbId string, update *provider.LoadBalancerAttributeUpdate) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateLoadBalancerAttribute"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	if update.LoadBalancerName != nil {
+		lb.LoadBalancerName = update.LoadBalancerName
+	}
+	if update.Cps != nil {
+		lb.Cps = update.Cps
+	}
+	if update.CrossZoneEnabled != nil {
+		lb.CrossZoneEnabled = update.CrossZoneEnabled
+	}
+	return nil
+}
+
+func (f *NLBClient) UpdateLoadBalancerProtection(ctx context.Context, lbId string, enabled bool, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateLoadBalancerProtection"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.DeletionProtectionConfig = &nlbsdk.GetLoadBalancerAttributeResponseBodyDeletionProtectionConfig{
+		Enabled: tea.Bool(enabled),
+		Reason:  tea.String(reason),
+	}
+	return nil
+}
+
+func (f *NLBClient) UpdateLoadBalancerModificationProtection(ctx context.Context, lbId, status, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateLoadBalancerModificationProtection"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.ModificationProtectionConfig = &nlbsdk.GetLoadBalancerAttributeResponseBodyModificationProtectionConfig{
+		Status: tea.String(status),
+		Reason: tea.String(reason),
+	}
+	return nil
+}
+
+func (f *NLBClient) UpdateLoadBalancerZones(ctx context.Context, lbId string, mappings []nlbv1.ZoneMapping) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateLoadBalancerZones"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.ZoneMappings = zoneMappings(mappings)
+	return nil
+}
+
+func (f *NLBClient) MoveResourceGroup(ctx context.Context, lbId, resourceGroupId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("MoveResourceGroup"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.ResourceGroupId = tea.String(resourceGroupId)
+	return nil
+}
+
+func (f *NLBClient) JoinSecurityGroup(ctx context.Context, lbId string, securityGroupIds []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("JoinSecurityGroup"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	for _, sgId := range securityGroupIds {
+		if !slices.Contains(tea.StringSliceValue(lb.SecurityGroupIds), sgId) {
+			lb.SecurityGroupIds = append(lb.SecurityGroupIds, tea.String(sgId))
+		}
+	}
+	return nil
+}
+
+func (f *NLBClient) LeaveSecurityGroup(ctx context.Context, lbId string, securityGroupIds []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("LeaveSecurityGroup"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.SecurityGroupIds = slices.DeleteFunc(slices.Clone(lb.SecurityGroupIds), func(sgId *string) bool {
+		return slices.Contains(securityGroupIds, tea.StringValue(sgId))
+	})
+	return nil
+}
+
+func (f *NLBClient) AttachCommonBandwidthPackage(ctx context.Context, lbId, bandwidthPackageId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("AttachCommonBandwidthPackage"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.BandwidthPackageId = tea.String(bandwidthPackageId)
+	return nil
+}
+
+func (f *NLBClient) DetachCommonBandwidthPackage(ctx context.Context, lbId, bandwidthPackageId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DetachCommonBandwidthPackage"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	if tea.StringValue(lb.BandwidthPackageId) == bandwidthPackageId {
+		lb.BandwidthPackageId = nil
+	}
+	return nil
+}
+
+func (f *NLBClient) UpdateLoadBalancerIpv6AddressType(ctx context.Context, lbId, ipv6AddressType string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UpdateLoadBalancerIpv6AddressType"); err != nil {
+		return err
+	}
+
+	lb, err := f.get(lbId)
+	if err != nil {
+		return err
+	}
+	lb.Ipv6AddressType = tea.String(ipv6AddressType)
+	return nil
+}
+
+func (f *NLBClient) ListTagResources(ctx context.Context, lbId string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListTagResources"); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(f.Tags[lbId]))
+	for k, v := range f.Tags[lbId] {
+		tags[k] = v
+	}
+	return tags, nil
+}
+
+func (f *NLBClient) TagResources(ctx context.Context, lbId string, tags []nlbv1.Tag) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("TagResources"); err != nil {
+		return err
+	}
+
+	if _, err := f.get(lbId); err != nil {
+		return err
+	}
+	if f.Tags[lbId] == nil {
+		f.Tags[lbId] = make(map[string]string)
+	}
+	for _, tag := range tags {
+		f.Tags[lbId][tag.Key] = tag.Value
+	}
+	return nil
+}
+
+func (f *NLBClient) UntagResources(ctx context.Context, lbId string, tagKeys []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("UntagResources"); err != nil {
+		return err
+	}
+
+	if _, err := f.get(lbId); err != nil {
+		return err
+	}
+	for _, key := range tagKeys {
+		delete(f.Tags[lbId], key)
+	}
+	return nil
+}
+
+func (f *NLBClient) ListLoadBalancerListeners(ctx context.Context, nlbId string) ([]provider.ListenerAttribute, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListLoadBalancerListeners"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.Listeners[nlbId]), nil
+}
+
+func (f *NLBClient) GetListenerBackendHealth(ctx context.Context, listenerId string) ([]provider.ServerGroupHealth, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetListenerBackendHealth"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(f.BackendHealth[listenerId]), nil
+}
+
+func (f *NLBClient) DeleteListener(ctx context.Context, listenerId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DeleteListener"); err != nil {
+		return err
+	}
+
+	for lbId, listeners := range f.Listeners {
+		f.Listeners[lbId] = slices.DeleteFunc(listeners, func(l provider.ListenerAttribute) bool {
+			return l.ListenerId == listenerId
+		})
+	}
+	delete(f.BackendHealth, listenerId)
+	return nil
+}
+
+// zoneMappings converts spec zone mappings to their API representation.
+func zoneMappings(mappings []nlbv1.ZoneMapping) []*nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappings {
+	out := make([]*nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappings, 0, len(mappings))
+	for _, m := range mappings {
+		out = append(out, &nlbsdk.GetLoadBalancerAttributeResponseBodyZoneMappings{
+			ZoneId:    tea.String(m.ZoneId),
+			VSwitchId: tea.String(m.VSwitchId),
+			Status:    tea.String(provider.ZoneStatusActive),
+		})
+	}
+	return out
+}
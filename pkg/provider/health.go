@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"k8s.io/klog/v2"
+)
+
+// apiCheckTimeout bounds a single connectivity check, so a hanging endpoint fails
+// the probe instead of blocking it.
+const apiCheckTimeout = 5 * time.Second
+
+// CheckAPI issues the cheapest authenticated NLB API call, a ListLoadBalancers
+// with a page size of 1, to verify the credentials and endpoint. Throttling proves
+// the API is reachable and authenticated, so it is not reported as a failure.
+func (c *NLBClient) CheckAPI(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, apiCheckTimeout)
+	defer cancel()
+
+	req := &nlbsdk.ListLoadBalancersRequest{
+		MaxResults: tea.Int32(1),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.ListLoadBalancers)
+	if err != nil {
+		if IsThrottlingError(err) || IsLocalRateLimited(err) {
+			return nil
+		}
+		return fmt.Errorf("NLB API check failed: %w", err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from ListLoadBalancers API")
+	}
+	return nil
+}
+
+// CheckRegion verifies the credentials and that the client's endpoint serves
+// regionId, see CheckRegionZones.
+func (c *NLBClient) CheckRegion(ctx context.Context, regionId string) error {
+	return CheckRegionZones(ctx, c, regionId)
+}
+
+// CheckRegionZones verifies that the endpoint of c serves regionId, by listing
+// the NLB zones with DescribeZones: an endpoint of another region answers with
+// zones of that region. Throttling is not reported as a failure, as for CheckAPI.
+func CheckRegionZones(ctx context.Context, c NLBClientInterface, regionId string) error {
+	ctx, cancel := context.WithTimeout(ctx, apiCheckTimeout)
+	defer cancel()
+
+	zones, err := c.DescribeZones(ctx)
+	if err != nil {
+		if IsThrottlingError(err) || IsLocalRateLimited(err) {
+			return nil
+		}
+		return fmt.Errorf("NLB API check failed: %w", err)
+	}
+	for _, zone := range zones {
+		if !zoneInRegion(zone, regionId) {
+			return fmt.Errorf("NLB API endpoint serves zone %s, which is not in region %s", zone, regionId)
+		}
+	}
+	return nil
+}
+
+// zoneInRegion reports whether zone belongs to regionId. Zone IDs append a
+// letter to the region ID, after a dash unless the region ID ends in a digit:
+// cn-hangzhou-h, but ap-southeast-1a.
+func zoneInRegion(zone, regionId string) bool {
+	if zone == regionId {
+		return true
+	}
+	if !strings.HasPrefix(zone, regionId) {
+		return false
+	}
+	return len(strings.TrimPrefix(strings.TrimPrefix(zone, regionId), "-")) == 1
+}
+
+// APIReadyChecker returns a readiness check backed by CheckAPI. The result of a
+// check is reused for ttl so frequent probes do not hammer the API.
+func (c *NLBClient) APIReadyChecker(ttl time.Duration) func(*http.Request) error {
+	var (
+		mu        sync.Mutex
+		checkedAt time.Time
+		lastErr   error
+	)
+	return func(r *http.Request) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return lastErr
+		}
+		lastErr = c.CheckAPI(r.Context())
+		checkedAt = time.Now()
+		if lastErr != nil {
+			klog.Errorf("Readiness check of NLB API failed: %v", lastErr)
+		}
+		return lastErr
+	}
+}
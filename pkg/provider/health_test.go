@@ -0,0 +1,35 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/provider/fake"
+)
+
+func TestCheckRegionZones(t *testing.T) {
+	tests := []struct {
+		name     string
+		regionId string
+		zones    []string
+		wantErr  bool
+	}{
+		{name: "dash before the zone letter", regionId: "cn-hangzhou", zones: []string{"cn-hangzhou-h", "cn-hangzhou-i"}},
+		{name: "region ending in a digit", regionId: "ap-southeast-1", zones: []string{"ap-southeast-1a", "ap-southeast-1b"}},
+		{name: "zone of another region", regionId: "cn-hangzhou", zones: []string{"cn-hangzhou-h", "cn-shanghai-b"}, wantErr: true},
+		{name: "zone of a region sharing the prefix", regionId: "ap-southeast-1", zones: []string{"ap-southeast-11a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nlbClient := fake.NewNLBClient()
+			nlbClient.Zones = tt.zones
+
+			err := provider.CheckRegionZones(context.Background(), nlbClient, tt.regionId)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("CheckRegionZones(%s) with zones %v: err = %v, want error %v", tt.regionId, tt.zones, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// NLBClientInterface is the subset of NLBClient used by the NLB reconciler, so
+// its logic can run against a fake (see package provider/fake) in tests.
+type NLBClientInterface interface {
+	// Load balancer lifecycle
+	CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB, tags, provenanceTags []nlbv1.Tag) (string, error)
+	DeleteLoadBalancer(ctx context.Context, lbId string) error
+	GetLoadBalancer(ctx context.Context, lbId string) (*nlbsdk.GetLoadBalancerAttributeResponseBody, error)
+	GetLoadBalancerCached(ctx context.Context, lbId string) (*nlbsdk.GetLoadBalancerAttributeResponseBody, error)
+	InvalidateLoadBalancer(lbId string)
+	FindLoadBalancerByName(ctx context.Context, vpcId, name string) (string, error)
+	ListLoadBalancers(ctx context.Context, filter LoadBalancerFilter) ([]LoadBalancerSummary, error)
+	DescribeZones(ctx context.Context) ([]string, error)
+	GetJobStatus(ctx context.Context, jobId string) (string, error)
+
+	// Load balancer attributes
+	UpdateLoadBalancerAttribute(ctx context.Context, lbId string, update *LoadBalancerAttributeUpdate) error
+	UpdateLoadBalancerProtection(ctx context.Context, lbId string, enabled bool, reason string) error
+	UpdateLoadBalancerModificationProtection(ctx context.Context, lbId, status, reason string) error
+	UpdateLoadBalancerZones(ctx context.Context, lbId string, zoneMappings []nlbv1.ZoneMapping) error
+	MoveResourceGroup(ctx context.Context, lbId, resourceGroupId string) error
+	JoinSecurityGroup(ctx context.Context, lbId string, securityGroupIds []string) error
+	LeaveSecurityGroup(ctx context.Context, lbId string, securityGroupIds []string) error
+	AttachCommonBandwidthPackage(ctx context.Context, lbId, bandwidthPackageId string) error
+	DetachCommonBandwidthPackage(ctx context.Context, lbId, bandwidthPackageId string) error
+	UpdateLoadBalancerIpv6AddressType(ctx context.Context, lbId, ipv6AddressType string) error
+
+	// Tags
+	ListTagResources(ctx context.Context, lbId string) (map[string]string, error)
+	TagResources(ctx context.Context, lbId string, tags []nlbv1.Tag) error
+	UntagResources(ctx context.Context, lbId string, tagKeys []string) error
+
+	// Listeners of the load balancer
+	ListLoadBalancerListeners(ctx context.Context, nlbId string) ([]ListenerAttribute, error)
+	GetListenerBackendHealth(ctx context.Context, listenerId string) ([]ServerGroupHealth, error)
+	DeleteListener(ctx context.Context, listenerId string) error
+}
+
+var _ NLBClientInterface = &NLBClient{}
+
+// ListenerClientInterface is the subset of NLBClient used by the Listener
+// reconciler, so its logic can run against a fake (see package provider/fake) in
+// tests.
+type ListenerClientInterface interface {
+	// Listener lifecycle
+	CreateNLBListener(ctx context.Context, nlbId, sgId string, port int32, protocol string, opts ListenerOptions) (string, error)
+	GetListenerAttribute(ctx context.Context, listenerId string) (*ListenerAttribute, error)
+	ListListeners(ctx context.Context, nlbId string, port int32) (string, error)
+	DeleteNLBListener(ctx context.Context, listenerId string) error
+	StartListener(ctx context.Context, listenerId string) error
+	StopListener(ctx context.Context, listenerId string) error
+
+	// Listener attributes
+	UpdateListenerServerGroup(ctx context.Context, listenerId, sgId string) error
+	UpdateListenerDescription(ctx context.Context, listenerId, description string) error
+	UpdateListenerCps(ctx context.Context, listenerId string, cps int32) error
+	UpdateListenerIdleTimeout(ctx context.Context, listenerId, protocol string, idleTimeout int32) error
+	UpdateListenerProxyProtocol(ctx context.Context, listenerId string, pp ListenerProxyProtocol) error
+
+	// TLS settings of TCPSSL listeners
+	UpdateListenerAlpn(ctx context.Context, listenerId string, alpn ListenerAlpn) error
+	UpdateListenerSecurityPolicy(ctx context.Context, listenerId, policyId string) error
+	UpdateListenerCA(ctx context.Context, listenerId string, ca ListenerCA) error
+	ListAdditionalCertificates(ctx context.Context, listenerId string) ([]string, error)
+	AssociateAdditionalCertificates(ctx context.Context, listenerId string, certIds []string) error
+	DissociateAdditionalCertificates(ctx context.Context, listenerId string, certIds []string) error
+}
+
+var _ ListenerClientInterface = &NLBClient{}
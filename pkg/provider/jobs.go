@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// Job statuses returned by GetJobStatus.
+const (
+	JobStatusSucceeded  = "Succeeded"
+	JobStatusFailed     = "Failed"
+	JobStatusProcessing = "Processing"
+)
+
+// JobObserver is notified around every async job an NLBClient waits for, so the
+// caller can persist the jobs in flight and await them after a restart.
+type JobObserver struct {
+	// Started is called before waiting for jobId, started by the API call operation
+	Started func(jobId, operation string)
+	// Finished is called once jobId succeeded or failed. It is not called when the
+	// wait times out or is cancelled, leaving the job to be awaited later
+	Finished func(jobId string)
+}
+
+type jobObserverKey struct{}
+
+// jobScope is the per-context state of a JobObserver.
+type jobScope struct {
+	observer JobObserver
+	// lastAction is the last API called with the context, the one that started
+	// the job waitJobFinish is called for
+	lastAction string
+}
+
+// WithJobObserver returns a context notifying observer of the async jobs waited
+// for by NLBClient methods called with it.
+func WithJobObserver(ctx context.Context, observer JobObserver) context.Context {
+	return context.WithValue(ctx, jobObserverKey{}, &jobScope{observer: observer})
+}
+
+// ForkJobObserver returns a context notifying the JobObserver of ctx, if any,
+// with its own record of the last API called. NLBClient calls issued
+// concurrently with the same observer must each use a forked context, so a job
+// is not attributed to an API called by another goroutine.
+func ForkJobObserver(ctx context.Context) context.Context {
+	scope, ok := ctx.Value(jobObserverKey{}).(*jobScope)
+	if !ok {
+		return ctx
+	}
+	return WithJobObserver(ctx, scope.observer)
+}
+
+// recordAction remembers the API called with ctx for the JobObserver, if any.
+func recordAction(ctx context.Context, req any) {
+	scope, ok := ctx.Value(jobObserverKey{}).(*jobScope)
+	if !ok {
+		return
+	}
+	action := fmt.Sprintf("%T", req)
+	action = action[strings.LastIndex(action, ".")+1:]
+	scope.lastAction = strings.TrimSuffix(action, "Request")
+}
+
+// observeJob notifies the JobObserver of ctx that jobId is being waited for and
+// returns the function to call once the job succeeded or failed.
+func observeJob(ctx context.Context, jobId string) func() {
+	scope, ok := ctx.Value(jobObserverKey{}).(*jobScope)
+	if !ok {
+		return func() {}
+	}
+	if scope.observer.Started != nil {
+		scope.observer.Started(jobId, scope.lastAction)
+	}
+	return func() {
+		if scope.observer.Finished != nil {
+			scope.observer.Finished(jobId)
+		}
+	}
+}
+
+// GetJobStatus returns the status of an async job: Succeeded, Failed or
+// Processing. A job unknown to the API is reported as ErrResourceNotFound.
+func (c *NLBClient) GetJobStatus(ctx context.Context, jobId string) (string, error) {
+	req := &nlbsdk.GetJobStatusRequest{
+		JobId: tea.String(jobId),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.GetJobStatus)
+	if err != nil {
+		return "", fmt.Errorf("failed to get status of job %s: %w", jobId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return "", fmt.Errorf("invalid response from GetJobStatus API")
+	}
+	return tea.StringValue(resp.Body.Status), nil
+}
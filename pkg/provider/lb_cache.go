@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"k8s.io/klog/v2"
+)
+
+// lbAttributeCache keeps recent GetLoadBalancerAttribute results keyed by
+// load balancer ID.
+type lbAttributeCache struct {
+	mu      sync.Mutex
+	entries map[string]lbAttributeCacheEntry
+}
+
+type lbAttributeCacheEntry struct {
+	lb      *nlbsdk.GetLoadBalancerAttributeResponseBody
+	expires time.Time
+}
+
+func newLBAttributeCache() *lbAttributeCache {
+	return &lbAttributeCache{entries: make(map[string]lbAttributeCacheEntry)}
+}
+
+// GetLoadBalancerCached is GetLoadBalancer coalescing repeated reads of the same
+// instance across reconciles: an Active instance is served from the cache for
+// AttributeCacheTTL. Instances in any other state are always read from the API,
+// since callers poll them for state changes, and every mutating NLBClient call on
+// an instance drops its entry. The result is shared and must not be modified.
+func (c *NLBClient) GetLoadBalancerCached(ctx context.Context, lbId string) (*nlbsdk.GetLoadBalancerAttributeResponseBody, error) {
+	if c.AttributeCacheTTL <= 0 || c.lbCache == nil {
+		return c.GetLoadBalancer(ctx, lbId)
+	}
+
+	c.lbCache.mu.Lock()
+	entry, ok := c.lbCache.entries[lbId]
+	c.lbCache.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		klog.V(5).Infof("Using cached attributes of load balancer %s", lbId)
+		return entry.lb, nil
+	}
+
+	lb, err := c.GetLoadBalancer(ctx, lbId)
+	if err != nil || lb == nil || tea.StringValue(lb.LoadBalancerStatus) != LoadBalancerStatusActive {
+		c.InvalidateLoadBalancer(lbId)
+		return lb, err
+	}
+
+	c.lbCache.mu.Lock()
+	c.lbCache.entries[lbId] = lbAttributeCacheEntry{lb: lb, expires: time.Now().Add(c.AttributeCacheTTL)}
+	c.lbCache.mu.Unlock()
+	return lb, nil
+}
+
+// InvalidateLoadBalancer drops the cached attributes of lbId, so the next
+// GetLoadBalancerCached reads them from the API.
+func (c *NLBClient) InvalidateLoadBalancer(lbId string) {
+	if c.lbCache == nil {
+		return
+	}
+	c.lbCache.mu.Lock()
+	delete(c.lbCache.entries, lbId)
+	c.lbCache.mu.Unlock()
+}
@@ -9,18 +9,37 @@ import (
 	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
 	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
 	"github.com/alibabacloud-go/tea/tea"
+	"github.com/aliyun/credentials-go/credentials"
 	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+	"github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/version"
 )
 
 const (
 	LoadBalancerStatusActive       = "Active"
 	LoadBalancerStatusProvisioning = "Provisioning"
+	LoadBalancerStatusDeleting     = "Deleting"
+	LoadBalancerStatusConfiguring  = "Configuring"
+
+	// ZoneStatusActive is the status of a zone that is serving traffic. Other
+	// statuses such as Stopped or Shifted mean the zone's addresses are out of
+	// DNS resolution.
+	ZoneStatusActive = "Active"
+
+	// AddressIpVersionDualStack is the AddressIpVersion of instances with IPv6 addresses
+	AddressIpVersionDualStack = "DualStack"
+
+	// tagResourceTypeLoadBalancer is the ResourceType used by the tag APIs for NLB instances
+	tagResourceTypeLoadBalancer = "loadbalancer"
 )
 
+// DefaultStableLoadBalancerStates are the load balancer statuses in which an
+// update is considered applied when no other set is configured.
+var DefaultStableLoadBalancerStates = []string{LoadBalancerStatusActive}
+
 // NLBClient provides methods to interact with Alibaba Cloud NLB OpenAPI
 type NLBClient struct {
 	client *nlbsdk.Client
@@ -32,14 +51,103 @@ type NLBClient struct {
 	// CreateListenerLimiter applies a local interface-level token-bucket rate limit
 	// to CreateNLBListener calls. When nil, no local limiting is applied.
 	CreateListenerLimiter *rate.Limiter
+
+	// APILimiter is a token bucket gating every OpenAPI request of the client,
+	// shared by all reconcile workers, so a large fleet stays within the regional
+	// API quota. Requests wait for a token until their context is done. When nil,
+	// no client-wide limiting is applied.
+	APILimiter *rate.Limiter
+
+	// RequestTimeout bounds a single OpenAPI request. When zero, a request is
+	// only bounded by the caller's context.
+	RequestTimeout time.Duration
+
+	// MaxRetries is the number of times a request failing with a retryable error
+	// code is retried with exponential backoff. Zero disables client-side retry.
+	MaxRetries int
+
+	// RetryableErrorCodes overrides DefaultRetryableErrorCodes when non-empty.
+	RetryableErrorCodes []string
+
+	// LoadBalancerOperationTimeout bounds how long load balancer level async jobs
+	// (delete, attribute update, security group changes) are waited for.
+	// When zero, JobPollTimeout is used.
+	LoadBalancerOperationTimeout time.Duration
+
+	// ListenerOperationTimeout bounds how long listener level async jobs are waited for.
+	// When zero, JobPollTimeout is used.
+	ListenerOperationTimeout time.Duration
+
+	// JobPollInterval and JobPollTimeout control GetJobStatus polling in waitJobFinish.
+	// When zero, defaultJobPollInterval / defaultJobPollTimeout are used.
+	JobPollInterval time.Duration
+	JobPollTimeout  time.Duration
+
+	// AttributeCacheTTL is how long GetLoadBalancerCached reuses the attributes of
+	// an Active load balancer. Zero disables the cache.
+	AttributeCacheTTL time.Duration
+
+	// ShutdownGracePeriod is how long waiting for an async job continues after the
+	// caller's context is cancelled, so a change in flight at shutdown can finish.
+	// Zero abandons the wait as soon as the context is cancelled.
+	ShutdownGracePeriod time.Duration
+
+	lbCache *lbAttributeCache
+}
+
+const (
+	defaultJobPollInterval = 3 * time.Second
+	defaultJobPollTimeout  = 3 * time.Minute
+)
+
+// durationOrDefault returns d, or def when d is not positive.
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
 }
 
-// NewNLBClient creates a new NLBClient
-func NewNLBClient(endpoint, accessKeyId, accessKeySecret, regionId string) (*NLBClient, error) {
+func (c *NLBClient) jobPollTimeout() time.Duration {
+	return durationOrDefault(c.JobPollTimeout, defaultJobPollTimeout)
+}
+
+func (c *NLBClient) loadBalancerOperationTimeout() time.Duration {
+	return durationOrDefault(c.LoadBalancerOperationTimeout, c.jobPollTimeout())
+}
+
+func (c *NLBClient) listenerOperationTimeout() time.Duration {
+	return durationOrDefault(c.ListenerOperationTimeout, c.jobPollTimeout())
+}
+
+// NewNLBClient creates a new NLBClient. userAgent is sent with every request,
+// see UserAgent.
+func NewNLBClient(endpoint, regionId string, credConfig CredentialConfig, userAgent string) (*NLBClient, error) {
+	cred, err := newCredential(credConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newNLBClient(endpoint, regionId, cred, userAgent)
+}
+
+// UserAgent returns the user agent identifying operator API calls in the cloud
+// audit logs: the operator name and version, followed by suffix if set.
+func UserAgent(suffix string) string {
+	ua := "alibabacloud-nlb-operator/" + version.Version
+	if suffix != "" {
+		ua += " " + suffix
+	}
+	return ua
+}
+
+// newNLBClient creates an NLB client for regionId using an existing credential provider
+func newNLBClient(endpoint, regionId string, cred credentials.Credential, userAgent string) (*NLBClient, error) {
 	config := &openapi.Config{
-		AccessKeyId:     tea.String(accessKeyId),
-		AccessKeySecret: tea.String(accessKeySecret),
-		RegionId:        tea.String(regionId),
+		Credential: cred,
+		RegionId:   tea.String(regionId),
+	}
+	if userAgent != "" {
+		config.UserAgent = tea.String(userAgent)
 	}
 	if endpoint != "" {
 		config.Endpoint = tea.String(endpoint)
@@ -47,14 +155,16 @@ func NewNLBClient(endpoint, accessKeyId, accessKeySecret, regionId string) (*NLB
 
 	client, err := nlbsdk.NewClient(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create NLB client: %v", err)
+		return nil, fmt.Errorf("failed to create NLB client: %w", err)
 	}
+	klog.Infof("Created NLB client for region %s using endpoint %s", regionId, tea.StringValue(client.Endpoint))
 
-	return &NLBClient{client: client}, nil
+	return &NLBClient{client: client, lbCache: newLBAttributeCache()}, nil
 }
 
-// CreateLoadBalancer creates a new NLB instance
-func (c *NLBClient) CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB) (string, error) {
+// CreateLoadBalancer creates a new NLB instance tagged with tags and
+// provenanceTags; provenance tags take precedence over user tags with the same key.
+func (c *NLBClient) CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB, tags, provenanceTags []nlbv1.Tag) (string, error) {
 	req := &nlbsdk.CreateLoadBalancerRequest{
 		LoadBalancerName: tea.String(nlb.Spec.LoadBalancerName),
 		AddressType:      tea.String(nlb.Spec.AddressType),
@@ -90,6 +200,9 @@ func (c *NLBClient) CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB) (str
 		if zm.PrivateIPv4Address != "" {
 			mapping.PrivateIPv4Address = tea.String(zm.PrivateIPv4Address)
 		}
+		if zm.Ipv6Address != "" {
+			mapping.Ipv6Address = tea.String(zm.Ipv6Address)
+		}
 		req.ZoneMappings = append(req.ZoneMappings, mapping)
 	}
 
@@ -113,21 +226,33 @@ func (c *NLBClient) CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB) (str
 		}
 	}
 
-	// Tags
-	if len(nlb.Spec.Tags) > 0 {
-		var tags []*nlbsdk.CreateLoadBalancerRequestTag
-		for _, t := range nlb.Spec.Tags {
-			tags = append(tags, &nlbsdk.CreateLoadBalancerRequestTag{
-				Key:   tea.String(t.Key),
-				Value: tea.String(t.Value),
-			})
+	if nlb.Spec.BillingConfig != nil {
+		req.LoadBalancerBillingConfig = &nlbsdk.CreateLoadBalancerRequestLoadBalancerBillingConfig{
+			PayType: tea.String(nlb.Spec.BillingConfig.PayType),
 		}
-		req.Tag = tags
 	}
 
-	resp, err := c.client.CreateLoadBalancer(req)
+	// Tags
+	reserved := make(map[string]bool, len(provenanceTags))
+	for _, t := range provenanceTags {
+		reserved[t.Key] = true
+		req.Tag = append(req.Tag, &nlbsdk.CreateLoadBalancerRequestTag{
+			Key:   tea.String(t.Key),
+			Value: tea.String(t.Value),
+		})
+	}
+	for _, t := range tags {
+		if reserved[t.Key] {
+			continue
+		}
+		req.Tag = append(req.Tag, &nlbsdk.CreateLoadBalancerRequestTag{
+			Key:   tea.String(t.Key),
+			Value: tea.String(t.Value),
+		})
+	}
+	resp, err := doRequest(ctx, c, req, c.client.CreateLoadBalancer)
 	if err != nil {
-		return "", fmt.Errorf("failed to create load balancer: %v", err)
+		return "", fmt.Errorf("failed to create load balancer: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil || resp.Body.LoadbalancerId == nil {
@@ -142,12 +267,14 @@ func (c *NLBClient) CreateLoadBalancer(ctx context.Context, nlb *nlbv1.NLB) (str
 
 // DeleteLoadBalancer deletes an NLB instance
 func (c *NLBClient) DeleteLoadBalancer(ctx context.Context, lbId string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
 	// Try to check if the load balancer exists
 	// If we get temporary errors (like GetXipFailed), we'll still try to delete
 	lb, err := c.GetLoadBalancer(ctx, lbId)
 	if err != nil {
 		// If it's a temporary error, log it but continue with deletion
-		if !strings.Contains(err.Error(), "ResourceNotFound") {
+		if !IsNotFoundError(err) {
 			klog.Warningf("Failed to check load balancer existence (will try to delete anyway): %v", err)
 		}
 	}
@@ -158,11 +285,16 @@ func (c *NLBClient) DeleteLoadBalancer(ctx context.Context, lbId string) error {
 		return nil
 	}
 
+	// A previous delete is still in progress, only wait for it
+	if lb != nil && tea.StringValue(lb.LoadBalancerStatus) == LoadBalancerStatusDeleting {
+		return c.WaitLoadBalancerDeleted(ctx, lbId)
+	}
+
 	// Try to disable deletion protection
 	// If the resource is not found or there's a temporary error, we'll ignore it
 	protErr := c.UpdateLoadBalancerProtection(ctx, lbId, false, "")
 	if protErr != nil {
-		if strings.Contains(protErr.Error(), "ResourceNotFound") {
+		if IsNotFoundError(protErr) {
 			klog.Infof("Load balancer %s not found when disabling protection, assuming already deleted", lbId)
 			return nil
 		}
@@ -173,15 +305,14 @@ func (c *NLBClient) DeleteLoadBalancer(ctx context.Context, lbId string) error {
 	req := &nlbsdk.DeleteLoadBalancerRequest{
 		LoadBalancerId: tea.String(lbId),
 	}
-
-	resp, err := c.client.DeleteLoadBalancer(req)
+	resp, err := doRequest(ctx, c, req, c.client.DeleteLoadBalancer)
 	if err != nil {
 		// If resource not found, consider it as already deleted
-		if strings.Contains(err.Error(), "ResourceNotFound") {
+		if IsNotFoundError(err) {
 			klog.Infof("Load balancer %s not found, assuming already deleted", lbId)
 			return nil
 		}
-		return fmt.Errorf("failed to delete load balancer: %v", err)
+		return fmt.Errorf("failed to delete load balancer: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil {
@@ -192,9 +323,35 @@ func (c *NLBClient) DeleteLoadBalancer(ctx context.Context, lbId string) error {
 
 	// Wait for the job to complete
 	if resp.Body.JobId != nil {
-		return c.waitJobFinish(tea.StringValue(resp.Body.JobId))
+		if err := c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout()); err != nil {
+			return err
+		}
 	}
 
+	// A finished job does not guarantee the instance is gone yet
+	return c.WaitLoadBalancerDeleted(ctx, lbId)
+}
+
+// WaitLoadBalancerDeleted polls GetLoadBalancer until the instance no longer exists,
+// bounded by the load balancer operation timeout.
+func (c *NLBClient) WaitLoadBalancerDeleted(ctx context.Context, lbId string) error {
+	interval := durationOrDefault(c.JobPollInterval, defaultJobPollInterval)
+	err := wait.PollUntilContextTimeout(ctx, interval, c.loadBalancerOperationTimeout(), true, func(ctx context.Context) (bool, error) {
+		lb, err := c.GetLoadBalancer(ctx, lbId)
+		if err != nil {
+			return false, err
+		}
+		if lb == nil {
+			klog.V(5).Infof("Load balancer %s is deleted", lbId)
+			return true, nil
+		}
+		klog.V(5).Infof("Waiting for load balancer %s to be deleted, current status: %s",
+			lbId, tea.StringValue(lb.LoadBalancerStatus))
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for load balancer %s to be deleted: %w", lbId, err)
+	}
 	return nil
 }
 
@@ -203,15 +360,14 @@ func (c *NLBClient) GetLoadBalancer(ctx context.Context, lbId string) (*nlbsdk.G
 	req := &nlbsdk.GetLoadBalancerAttributeRequest{
 		LoadBalancerId: tea.String(lbId),
 	}
-
-	resp, err := c.client.GetLoadBalancerAttribute(req)
+	resp, err := doRequest(ctx, c, req, c.client.GetLoadBalancerAttribute)
 	if err != nil {
 		// Resource not found is not an error, return nil
-		if strings.Contains(err.Error(), "ResourceNotFound") {
+		if IsNotFoundError(err) {
 			return nil, nil
 		}
 		// For GetXipFailed or other temporary errors, return the error for retry
-		return nil, fmt.Errorf("failed to get load balancer: %v", err)
+		return nil, fmt.Errorf("failed to get load balancer: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil {
@@ -221,8 +377,226 @@ func (c *NLBClient) GetLoadBalancer(ctx context.Context, lbId string) (*nlbsdk.G
 	return resp.Body, nil
 }
 
+// LoadBalancerAttributeUpdate holds the mutable NLB instance attributes for
+// UpdateLoadBalancerAttribute. Nil fields are left unchanged.
+type LoadBalancerAttributeUpdate struct {
+	LoadBalancerName *string
+	// Cps is the maximum new connections per second of each zone, 0 means unlimited
+	Cps *int32
+	// CrossZoneEnabled toggles cross-zone load balancing
+	CrossZoneEnabled *bool
+}
+
+// UpdateLoadBalancerAttribute updates mutable attributes of an NLB instance
+func (c *NLBClient) UpdateLoadBalancerAttribute(ctx context.Context, lbId string, update *LoadBalancerAttributeUpdate) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	req := &nlbsdk.UpdateLoadBalancerAttributeRequest{
+		LoadBalancerId:   tea.String(lbId),
+		LoadBalancerName: update.LoadBalancerName,
+		Cps:              update.Cps,
+		CrossZoneEnabled: update.CrossZoneEnabled,
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateLoadBalancerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update load balancer attribute: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateLoadBalancerAttribute API")
+	}
+
+	klog.V(5).Infof("Successfully updated NLB attribute: %s, RequestId: %s", lbId, tea.StringValue(resp.Body.RequestId))
+
+	// Wait for the job to complete
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout())
+	}
+
+	return nil
+}
+
+// UpdateLoadBalancerZones replaces the zone mappings of an NLB instance with the given set.
+// Zones not present in zoneMappings are removed, new ones are added. The API takes
+// no IPv6 address, so the Ipv6Address of added zones is not applied.
+func (c *NLBClient) UpdateLoadBalancerZones(ctx context.Context, lbId string, zoneMappings []nlbv1.ZoneMapping) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	req := &nlbsdk.UpdateLoadBalancerZonesRequest{
+		LoadBalancerId: tea.String(lbId),
+		ZoneMappings:   []*nlbsdk.UpdateLoadBalancerZonesRequestZoneMappings{},
+	}
+
+	for _, zm := range zoneMappings {
+		mapping := &nlbsdk.UpdateLoadBalancerZonesRequestZoneMappings{
+			VSwitchId: tea.String(zm.VSwitchId),
+			ZoneId:    tea.String(zm.ZoneId),
+		}
+		if zm.AllocationId != "" {
+			mapping.AllocationId = tea.String(zm.AllocationId)
+		}
+		if zm.PrivateIPv4Address != "" {
+			mapping.PrivateIPv4Address = tea.String(zm.PrivateIPv4Address)
+		}
+		req.ZoneMappings = append(req.ZoneMappings, mapping)
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateLoadBalancerZones)
+	if err != nil {
+		return fmt.Errorf("failed to update load balancer zones: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateLoadBalancerZones API")
+	}
+
+	klog.Infof("Successfully updated NLB zones: %s, RequestId: %s", lbId, tea.StringValue(resp.Body.RequestId))
+
+	// Wait for the job to complete
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout())
+	}
+
+	return nil
+}
+
+// DescribeZones returns the IDs of the zones in the client's region that support NLB.
+func (c *NLBClient) DescribeZones(ctx context.Context) ([]string, error) {
+	req := &nlbsdk.DescribeZonesRequest{}
+	resp, err := doRequest(ctx, c, req, c.client.DescribeZones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe zones: %w", err)
+	}
+	if resp == nil || resp.Body == nil {
+		return nil, fmt.Errorf("invalid response from DescribeZones API")
+	}
+
+	zones := make([]string, 0, len(resp.Body.Zones))
+	for _, z := range resp.Body.Zones {
+		if z != nil && z.ZoneId != nil {
+			zones = append(zones, tea.StringValue(z.ZoneId))
+		}
+	}
+	return zones, nil
+}
+
+// LoadBalancerFilter narrows ListLoadBalancers. Zero fields match every instance.
+type LoadBalancerFilter struct {
+	// Tags must all be present on the instance with the given values
+	Tags map[string]string
+	// ResourceGroupId restricts the results to a resource group
+	ResourceGroupId string
+	// LoadBalancerIds restricts the results to the given instances
+	LoadBalancerIds []string
+}
+
+// LoadBalancerSummary is a thin abstraction over the instance fields returned by
+// ListLoadBalancers.
+type LoadBalancerSummary struct {
+	LoadBalancerId     string
+	LoadBalancerName   string
+	LoadBalancerStatus string
+	VpcId              string
+	ResourceGroupId    string
+	Tags               map[string]string
+}
+
+// ListLoadBalancers returns every NLB instance in the client's region matching
+// filter, following NextToken pagination.
+func (c *NLBClient) ListLoadBalancers(ctx context.Context, filter LoadBalancerFilter) ([]LoadBalancerSummary, error) {
+	req := &nlbsdk.ListLoadBalancersRequest{
+		MaxResults: tea.Int32(100),
+	}
+	if filter.ResourceGroupId != "" {
+		req.ResourceGroupId = tea.String(filter.ResourceGroupId)
+	}
+	if len(filter.LoadBalancerIds) > 0 {
+		req.LoadBalancerIds = tea.StringSlice(filter.LoadBalancerIds)
+	}
+	for k, v := range filter.Tags {
+		req.Tag = append(req.Tag, &nlbsdk.ListLoadBalancersRequestTag{
+			Key:   tea.String(k),
+			Value: tea.String(v),
+		})
+	}
+
+	var result []LoadBalancerSummary
+	for {
+		resp, err := doRequest(ctx, c, req, c.client.ListLoadBalancers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list load balancers: %w", err)
+		}
+		if resp == nil || resp.Body == nil {
+			return nil, fmt.Errorf("invalid response from ListLoadBalancers API")
+		}
+		for _, lb := range resp.Body.LoadBalancers {
+			if lb == nil {
+				continue
+			}
+			summary := LoadBalancerSummary{
+				LoadBalancerId:     tea.StringValue(lb.LoadBalancerId),
+				LoadBalancerName:   tea.StringValue(lb.LoadBalancerName),
+				LoadBalancerStatus: tea.StringValue(lb.LoadBalancerStatus),
+				VpcId:              tea.StringValue(lb.VpcId),
+				ResourceGroupId:    tea.StringValue(lb.ResourceGroupId),
+				Tags:               make(map[string]string, len(lb.Tags)),
+			}
+			for _, t := range lb.Tags {
+				if t != nil {
+					summary.Tags[tea.StringValue(t.Key)] = tea.StringValue(t.Value)
+				}
+			}
+			result = append(result, summary)
+		}
+		next := tea.StringValue(resp.Body.NextToken)
+		if next == "" {
+			return result, nil
+		}
+		req.NextToken = tea.String(next)
+	}
+}
+
+// FindLoadBalancerByName looks up an NLB instance ID by VPC and name.
+// Returns "" when no matching instance exists.
+func (c *NLBClient) FindLoadBalancerByName(ctx context.Context, vpcId, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	req := &nlbsdk.ListLoadBalancersRequest{
+		LoadBalancerNames: tea.StringSlice([]string{name}),
+	}
+	if vpcId != "" {
+		req.VpcIds = tea.StringSlice([]string{vpcId})
+	}
+
+	for {
+		resp, err := doRequest(ctx, c, req, c.client.ListLoadBalancers)
+		if err != nil {
+			return "", fmt.Errorf("failed to list load balancers by name %s: %w", name, err)
+		}
+		if resp == nil || resp.Body == nil {
+			return "", fmt.Errorf("invalid response from ListLoadBalancers API")
+		}
+		for _, lb := range resp.Body.LoadBalancers {
+			if lb == nil || tea.StringValue(lb.LoadBalancerName) != name {
+				continue
+			}
+			if vpcId != "" && tea.StringValue(lb.VpcId) != vpcId {
+				continue
+			}
+			return tea.StringValue(lb.LoadBalancerId), nil
+		}
+		next := tea.StringValue(resp.Body.NextToken)
+		if next == "" {
+			return "", nil
+		}
+		req.NextToken = tea.String(next)
+	}
+}
+
 // UpdateLoadBalancerProtection updates deletion protection for NLB
 func (c *NLBClient) UpdateLoadBalancerProtection(ctx context.Context, lbId string, enabled bool, reason string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
 	req := &nlbsdk.UpdateLoadBalancerProtectionRequest{
 		LoadBalancerId:            tea.String(lbId),
 		DeletionProtectionEnabled: tea.Bool(enabled),
@@ -231,10 +605,9 @@ func (c *NLBClient) UpdateLoadBalancerProtection(ctx context.Context, lbId strin
 	if reason != "" {
 		req.DeletionProtectionReason = tea.String(reason)
 	}
-
-	resp, err := c.client.UpdateLoadBalancerProtection(req)
+	resp, err := doRequest(ctx, c, req, c.client.UpdateLoadBalancerProtection)
 	if err != nil {
-		return fmt.Errorf("failed to update load balancer protection: %v", err)
+		return fmt.Errorf("failed to update load balancer protection: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil {
@@ -245,8 +618,35 @@ func (c *NLBClient) UpdateLoadBalancerProtection(ctx context.Context, lbId strin
 	return nil
 }
 
+// UpdateLoadBalancerModificationProtection updates modification protection for NLB
+func (c *NLBClient) UpdateLoadBalancerModificationProtection(ctx context.Context, lbId, status, reason string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	req := &nlbsdk.UpdateLoadBalancerProtectionRequest{
+		LoadBalancerId:               tea.String(lbId),
+		ModificationProtectionStatus: tea.String(status),
+	}
+
+	if reason != "" {
+		req.ModificationProtectionReason = tea.String(reason)
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateLoadBalancerProtection)
+	if err != nil {
+		return fmt.Errorf("failed to update load balancer modification protection: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateLoadBalancerProtection API")
+	}
+
+	klog.V(5).Infof("Successfully updated NLB modification protection: %s, RequestId: %s", lbId, tea.StringValue(resp.Body.RequestId))
+	return nil
+}
+
 // JoinSecurityGroup adds security groups to NLB
 func (c *NLBClient) JoinSecurityGroup(ctx context.Context, lbId string, securityGroupIds []string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
 	if len(securityGroupIds) == 0 {
 		return nil
 	}
@@ -255,10 +655,9 @@ func (c *NLBClient) JoinSecurityGroup(ctx context.Context, lbId string, security
 		LoadBalancerId:   tea.String(lbId),
 		SecurityGroupIds: tea.StringSlice(securityGroupIds),
 	}
-
-	resp, err := c.client.LoadBalancerJoinSecurityGroup(req)
+	resp, err := doRequest(ctx, c, req, c.client.LoadBalancerJoinSecurityGroup)
 	if err != nil {
-		return fmt.Errorf("failed to join security group: %v", err)
+		return fmt.Errorf("failed to join security group: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil {
@@ -269,9 +668,267 @@ func (c *NLBClient) JoinSecurityGroup(ctx context.Context, lbId string, security
 
 	// Wait for the job to complete
 	if resp.Body.JobId != nil {
-		return c.waitJobFinish(tea.StringValue(resp.Body.JobId))
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout())
+	}
+
+	return nil
+}
+
+// LeaveSecurityGroup removes security groups from NLB
+func (c *NLBClient) LeaveSecurityGroup(ctx context.Context, lbId string, securityGroupIds []string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	if len(securityGroupIds) == 0 {
+		return nil
+	}
+
+	req := &nlbsdk.LoadBalancerLeaveSecurityGroupRequest{
+		LoadBalancerId:   tea.String(lbId),
+		SecurityGroupIds: tea.StringSlice(securityGroupIds),
+	}
+
+	resp, err := doRequest(ctx, c, req, c.client.LoadBalancerLeaveSecurityGroup)
+	if err != nil {
+		return fmt.Errorf("failed to leave security group: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from LoadBalancerLeaveSecurityGroup API")
+	}
+
+	klog.V(5).Infof("Successfully left security groups for NLB: %s, RequestId: %s", lbId, tea.StringValue(resp.Body.RequestId))
+
+	// Wait for the job to complete
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout())
+	}
+
+	return nil
+}
+
+// AttachCommonBandwidthPackage attaches an Internet shared bandwidth package to the NLB
+func (c *NLBClient) AttachCommonBandwidthPackage(ctx context.Context, lbId, bandwidthPackageId string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	req := &nlbsdk.AttachCommonBandwidthPackageToLoadBalancerRequest{
+		LoadBalancerId:     tea.String(lbId),
+		BandwidthPackageId: tea.String(bandwidthPackageId),
+	}
+
+	resp, err := doRequest(ctx, c, req, c.client.AttachCommonBandwidthPackageToLoadBalancer)
+	if err != nil {
+		return fmt.Errorf("failed to attach bandwidth package: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from AttachCommonBandwidthPackageToLoadBalancer API")
+	}
+
+	klog.V(5).Infof("Successfully attached bandwidth package %s to NLB: %s, RequestId: %s", bandwidthPackageId, lbId, tea.StringValue(resp.Body.RequestId))
+
+	// Wait for the job to complete
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout())
+	}
+
+	return nil
+}
+
+// UpdateLoadBalancerIpv6AddressType switches the IPv6 address of a DualStack NLB
+// between Internet and Intranet. CreateLoadBalancer cannot set it, so a new
+// instance always starts with an Intranet IPv6 address.
+func (c *NLBClient) UpdateLoadBalancerIpv6AddressType(ctx context.Context, lbId, ipv6AddressType string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	var requestId string
+	switch ipv6AddressType {
+	case "Internet":
+		req := &nlbsdk.EnableLoadBalancerIpv6InternetRequest{
+			LoadBalancerId: tea.String(lbId),
+		}
+		resp, err := doRequest(ctx, c, req, c.client.EnableLoadBalancerIpv6Internet)
+		if err != nil {
+			return fmt.Errorf("failed to enable IPv6 Internet access: %w", err)
+		}
+		if resp == nil || resp.Body == nil {
+			return fmt.Errorf("invalid response from EnableLoadBalancerIpv6Internet API")
+		}
+		requestId = tea.StringValue(resp.Body.RequestId)
+	case "Intranet":
+		req := &nlbsdk.DisableLoadBalancerIpv6InternetRequest{
+			LoadBalancerId: tea.String(lbId),
+		}
+		resp, err := doRequest(ctx, c, req, c.client.DisableLoadBalancerIpv6Internet)
+		if err != nil {
+			return fmt.Errorf("failed to disable IPv6 Internet access: %w", err)
+		}
+		if resp == nil || resp.Body == nil {
+			return fmt.Errorf("invalid response from DisableLoadBalancerIpv6Internet API")
+		}
+		requestId = tea.StringValue(resp.Body.RequestId)
+	default:
+		return fmt.Errorf("unsupported IPv6 address type %q", ipv6AddressType)
+	}
+
+	klog.V(5).Infof("Successfully changed IPv6 address type of NLB %s to %s, RequestId: %s", lbId, ipv6AddressType, requestId)
+	return nil
+}
+
+// DetachCommonBandwidthPackage detaches an Internet shared bandwidth package from the NLB
+func (c *NLBClient) DetachCommonBandwidthPackage(ctx context.Context, lbId, bandwidthPackageId string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	req := &nlbsdk.DetachCommonBandwidthPackageFromLoadBalancerRequest{
+		LoadBalancerId:     tea.String(lbId),
+		BandwidthPackageId: tea.String(bandwidthPackageId),
+	}
+
+	resp, err := doRequest(ctx, c, req, c.client.DetachCommonBandwidthPackageFromLoadBalancer)
+	if err != nil {
+		return fmt.Errorf("failed to detach bandwidth package: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from DetachCommonBandwidthPackageFromLoadBalancer API")
+	}
+
+	klog.V(5).Infof("Successfully detached bandwidth package %s from NLB: %s, RequestId: %s", bandwidthPackageId, lbId, tea.StringValue(resp.Body.RequestId))
+
+	// Wait for the job to complete
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.loadBalancerOperationTimeout())
+	}
+
+	return nil
+}
+
+// ListTagResources returns the user tags currently attached to an NLB instance.
+// System tags (acs:/aliyun prefixed keys) are skipped since they cannot be modified.
+func (c *NLBClient) ListTagResources(ctx context.Context, lbId string) (map[string]string, error) {
+	req := &nlbsdk.ListTagResourcesRequest{
+		ResourceType: tea.String(tagResourceTypeLoadBalancer),
+		ResourceId:   tea.StringSlice([]string{lbId}),
+	}
+
+	tags := make(map[string]string)
+	for {
+		resp, err := doRequest(ctx, c, req, c.client.ListTagResources)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tag resources: %w", err)
+		}
+		if resp == nil || resp.Body == nil {
+			return nil, fmt.Errorf("invalid response from ListTagResources API")
+		}
+		for _, t := range resp.Body.TagResources {
+			if t == nil || tea.StringValue(t.ResourceId) != lbId {
+				continue
+			}
+			key := tea.StringValue(t.TagKey)
+			if strings.HasPrefix(key, "acs:") || strings.HasPrefix(key, "aliyun") {
+				continue
+			}
+			tags[key] = tea.StringValue(t.TagValue)
+		}
+		next := tea.StringValue(resp.Body.NextToken)
+		if next == "" {
+			return tags, nil
+		}
+		req.NextToken = tea.String(next)
+	}
+}
+
+// TagResources adds or overwrites tags on an NLB instance
+func (c *NLBClient) TagResources(ctx context.Context, lbId string, tags []nlbv1.Tag) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	if len(tags) == 0 {
+		return nil
 	}
 
+	req := &nlbsdk.TagResourcesRequest{
+		ResourceType: tea.String(tagResourceTypeLoadBalancer),
+		ResourceId:   tea.StringSlice([]string{lbId}),
+	}
+	for _, t := range tags {
+		req.Tag = append(req.Tag, &nlbsdk.TagResourcesRequestTag{
+			Key:   tea.String(t.Key),
+			Value: tea.String(t.Value),
+		})
+	}
+	resp, err := doRequest(ctx, c, req, c.client.TagResources)
+	if err != nil {
+		return fmt.Errorf("failed to tag resources: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from TagResources API")
+	}
+
+	klog.V(5).Infof("Successfully tagged NLB: %s, RequestId: %s", lbId, tea.StringValue(resp.Body.RequestId))
+	return nil
+}
+
+// UntagResources removes the given tag keys from an NLB instance
+func (c *NLBClient) UntagResources(ctx context.Context, lbId string, tagKeys []string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	if len(tagKeys) == 0 {
+		return nil
+	}
+
+	req := &nlbsdk.UntagResourcesRequest{
+		ResourceType: tea.String(tagResourceTypeLoadBalancer),
+		ResourceId:   tea.StringSlice([]string{lbId}),
+		TagKey:       tea.StringSlice(tagKeys),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UntagResources)
+	if err != nil {
+		return fmt.Errorf("failed to untag resources: %w", err)
+	}
+
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UntagResources API")
+	}
+
+	klog.V(5).Infof("Successfully untagged NLB: %s, RequestId: %s", lbId, tea.StringValue(resp.Body.RequestId))
+	return nil
+}
+
+// MoveResourceGroup moves an NLB instance to another resource group. The move is
+// applied asynchronously, so GetLoadBalancer is polled until it reports the new
+// group, bounded by the load balancer operation timeout.
+func (c *NLBClient) MoveResourceGroup(ctx context.Context, lbId, resourceGroupId string) error {
+	defer c.InvalidateLoadBalancer(lbId)
+
+	req := &nlbsdk.MoveResourceGroupRequest{
+		ResourceType:       tea.String(tagResourceTypeLoadBalancer),
+		ResourceId:         tea.String(lbId),
+		NewResourceGroupId: tea.String(resourceGroupId),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.MoveResourceGroup)
+	if err != nil {
+		return fmt.Errorf("failed to move load balancer %s to resource group %s: %w", lbId, resourceGroupId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from MoveResourceGroup API")
+	}
+	klog.Infof("Successfully requested move of NLB: %s to resource group: %s, RequestId: %s",
+		lbId, resourceGroupId, tea.StringValue(resp.Body.RequestId))
+
+	interval := durationOrDefault(c.JobPollInterval, defaultJobPollInterval)
+	err = wait.PollUntilContextTimeout(ctx, interval, c.loadBalancerOperationTimeout(), true, func(ctx context.Context) (bool, error) {
+		lb, err := c.GetLoadBalancer(ctx, lbId)
+		if err != nil {
+			return false, err
+		}
+		if lb == nil {
+			return false, fmt.Errorf("load balancer %s disappeared while moving resource group", lbId)
+		}
+		return tea.StringValue(lb.ResourceGroupId) == resourceGroupId, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for load balancer %s to move to resource group %s: %w", lbId, resourceGroupId, err)
+	}
 	return nil
 }
 
@@ -312,9 +969,9 @@ func (c *NLBClient) CreateListener(ctx context.Context, lbId string, listener *n
 		req.ProxyProtocolEnabled = listener.ProxyProtocolEnabled
 	}
 
-	resp, err := c.client.CreateListener(req)
+	resp, err := doRequest(ctx, c, req, c.client.CreateListener)
 	if err != nil {
-		return "", fmt.Errorf("failed to create listener: %v", err)
+		return "", fmt.Errorf("failed to create listener: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil || resp.Body.ListenerId == nil {
@@ -332,15 +989,14 @@ func (c *NLBClient) DeleteListener(ctx context.Context, listenerId string) error
 	req := &nlbsdk.DeleteListenerRequest{
 		ListenerId: tea.String(listenerId),
 	}
-
-	resp, err := c.client.DeleteListener(req)
+	resp, err := doRequest(ctx, c, req, c.client.DeleteListener)
 	if err != nil {
 		// If resource not found, consider it as already deleted
-		if strings.Contains(err.Error(), "ResourceNotFound") {
+		if IsNotFoundError(err) {
 			klog.Infof("Listener %s not found, assuming already deleted", listenerId)
 			return nil
 		}
-		return fmt.Errorf("failed to delete listener: %v", err)
+		return fmt.Errorf("failed to delete listener: %w", err)
 	}
 
 	if resp == nil || resp.Body == nil {
@@ -351,22 +1007,28 @@ func (c *NLBClient) DeleteListener(ctx context.Context, listenerId string) error
 
 	// Wait for the job to complete
 	if resp.Body.JobId != nil {
-		return c.waitJobFinish(tea.StringValue(resp.Body.JobId))
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
 	}
 
 	return nil
 }
 
-// waitJobFinish waits for an async job to complete
-func (c *NLBClient) waitJobFinish(jobId string) error {
-	return wait.PollImmediate(3*time.Second, 3*time.Minute, func() (bool, error) {
+// waitJobFinish waits up to timeout for an async job to complete. Once ctx is
+// cancelled the wait goes on for at most ShutdownGracePeriod. The JobObserver of
+// ctx, if any, is notified before the wait and once the job succeeded or failed.
+func (c *NLBClient) waitJobFinish(ctx context.Context, jobId string, timeout time.Duration) error {
+	defer trackJob(jobId)()
+	finished := observeJob(ctx, jobId)
+	jobCtx, cancel := c.jobContext(ctx)
+	defer cancel()
+
+	err := wait.PollUntilContextTimeout(jobCtx, durationOrDefault(c.JobPollInterval, defaultJobPollInterval), timeout, true, func(ctx context.Context) (bool, error) {
 		req := &nlbsdk.GetJobStatusRequest{
 			JobId: tea.String(jobId),
 		}
-
-		resp, err := c.client.GetJobStatus(req)
+		resp, err := doRequest(ctx, c, req, c.client.GetJobStatus)
 		if err != nil {
-			return false, fmt.Errorf("failed to get job status: %v", err)
+			return false, fmt.Errorf("failed to get job status: %w", err)
 		}
 
 		if resp == nil || resp.Body == nil {
@@ -375,37 +1037,35 @@ func (c *NLBClient) waitJobFinish(jobId string) error {
 
 		status := tea.StringValue(resp.Body.Status)
 		switch status {
-		case "Succeeded":
+		case JobStatusSucceeded:
 			klog.V(5).Infof("Job %s succeeded", jobId)
+			finished()
 			return true, nil
-		case "Failed":
+		case JobStatusFailed:
+			finished()
 			return false, fmt.Errorf("job %s failed", jobId)
 		default:
 			klog.V(5).Infof("Job %s status: %s", jobId, status)
 			return false, nil
 		}
 	})
+	if err != nil && ctx.Err() != nil {
+		klog.Warningf("Stopped waiting for job %s after the context was cancelled, the operation may be incomplete: %v", jobId, err)
+	}
+	return err
 }
 
-// WaitLoadBalancerActive waits for the load balancer to become active
-func (c *NLBClient) WaitLoadBalancerActive(ctx context.Context, lbId string) error {
-	return wait.PollImmediate(10*time.Second, 5*time.Minute, func() (bool, error) {
-		lb, err := c.GetLoadBalancer(ctx, lbId)
-		if err != nil {
-			return false, err
-		}
-
-		if lb == nil {
-			return false, fmt.Errorf("load balancer %s not found", lbId)
-		}
-
-		status := tea.StringValue(lb.LoadBalancerStatus)
-		if status == LoadBalancerStatusActive {
-			klog.V(5).Infof("Load balancer %s is active", lbId)
-			return true, nil
+// IsLoadBalancerStable reports whether status is one of stable, or Active when
+// stable is empty. Configuring and Provisioning are transient: the instance is
+// still applying a change and rejects further updates.
+func IsLoadBalancerStable(status string, stable []string) bool {
+	if len(stable) == 0 {
+		stable = DefaultStableLoadBalancerStates
+	}
+	for _, s := range stable {
+		if strings.EqualFold(status, s) {
+			return true
 		}
-
-		klog.V(5).Infof("Waiting for load balancer %s to be active, current status: %s", lbId, status)
-		return false, nil
-	})
+	}
+	return false
 }
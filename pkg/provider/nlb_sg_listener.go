@@ -36,56 +36,152 @@ type ServerGroupAttribute struct {
 	ServerGroupName   string
 	ServerGroupStatus string
 	VpcId             string
+	Scheduler         string
+
+	ConnectionDrainEnabled  bool
+	ConnectionDrainTimeout  int32
+	PreserveClientIpEnabled bool
 }
 
 // ListenerAttribute is a thin abstraction over the cloud listener attributes
 // that are relevant for the controller.
 type ListenerAttribute struct {
-	ListenerId       string
-	ListenerStatus   string
-	ListenerPort     int32
-	ListenerProtocol string
-	LoadBalancerId   string
-	ServerGroupId    string
+	ListenerId          string
+	ListenerStatus      string
+	ListenerPort        int32
+	ListenerProtocol    string
+	LoadBalancerId      string
+	ServerGroupId       string
+	AlpnEnabled         bool
+	AlpnPolicy          string
+	ListenerDescription string
+	// Cps is the new connections per second limit in each zone, 0 when unlimited
+	Cps int32
+	// IdleTimeout is the idle connection timeout in seconds
+	IdleTimeout int32
+	// SecurityPolicyId is the TLS security policy of a TCPSSL listener
+	SecurityPolicyId string
+	// CaEnabled and CaCertificateIds are the mutual TLS settings of a TCPSSL listener
+	CaEnabled        bool
+	CaCertificateIds []string
+	// ProxyProtocolEnabled and ProxyProtocolV2 are the proxy protocol settings
+	ProxyProtocolEnabled bool
+	ProxyProtocolV2      nlbv1.ProxyProtocolV2Config
+}
+
+// ListenerAlpn is the ALPN configuration of a TCPSSL listener. Policy is only
+// sent when Enabled is true.
+type ListenerAlpn struct {
+	Enabled bool
+	Policy  string
+}
+
+// ListenerCA is the mutual TLS configuration of a TCPSSL listener. Enabling it
+// requires at least one CA certificate; empty CertificateIds leaves the CA
+// certificates of the listener unchanged.
+type ListenerCA struct {
+	Enabled        bool
+	CertificateIds []string
+}
+
+// ListenerProxyProtocol is the proxy protocol configuration of a listener. V2
+// needs Enabled; nil V2 leaves the v2 header fields unchanged.
+type ListenerProxyProtocol struct {
+	Enabled bool
+	V2      *nlbv1.ProxyProtocolV2Config
+}
+
+// ListenerOptions are the optional settings of a new listener. Nil fields are
+// left to the cloud defaults.
+type ListenerOptions struct {
+	Description   string
+	Cps           *int32
+	IdleTimeout   *int32
+	ProxyProtocol *ListenerProxyProtocol
+	// SecurityPolicyId, Alpn and CA are only accepted for TCPSSL listeners
+	SecurityPolicyId string
+	Alpn             *ListenerAlpn
+	CA               *ListenerCA
+}
+
+// ListenerIdleTimeoutRange returns the idle timeout range in seconds allowed for
+// listeners of protocol. UDP listeners are limited to a much shorter timeout than
+// TCP and TCPSSL listeners.
+func ListenerIdleTimeoutRange(protocol string) (min, max int32) {
+	if protocol == "UDP" {
+		return nlbv1.ListenerIdleTimeoutMin, nlbv1.UDPListenerIdleTimeoutMax
+	}
+	return nlbv1.ListenerIdleTimeoutMin, nlbv1.ListenerIdleTimeoutMax
 }
 
 // IsNotFoundError returns true when the underlying Aliyun OpenAPI error indicates
 // that the requested resource does not exist.
 func IsNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := err.Error()
-	return strings.Contains(msg, "ResourceNotFound") ||
-		strings.Contains(msg, "ServerNotFound") ||
-		strings.Contains(msg, "InvalidListenerId.NotFound") ||
-		strings.Contains(msg, "InvalidServerGroupId.NotFound") ||
-		strings.Contains(msg, "NotFound")
+	return errors.Is(err, ErrResourceNotFound)
 }
 
 // IsThrottlingError returns true when the underlying Aliyun OpenAPI error
 // indicates the request was throttled.
 func IsThrottlingError(err error) bool {
-	if err == nil {
+	return errors.Is(err, ErrThrottling)
+}
+
+// terminalErrorCodes are OpenAPI error code prefixes for requests that will keep
+// failing until the request itself changes (bad parameters, missing VPC/vSwitch,
+// exhausted quota, denied permission).
+var terminalErrorCodes = []string{
+	"IllegalParam",
+	"InvalidParam",
+	"MissingParam",
+	"QuotaExceeded",
+	"ResourceQuotaLimit",
+	"Forbidden",
+	"OperationDenied",
+	"ResourceNotFound.Vpc",
+	"ResourceNotFound.VSwitch",
+	"ResourceNotFound.ResourceGroup",
+	"ResourceNotFound.BandwidthPackage",
+	"NoPermission",
+}
+
+// IsTerminalError returns true when the underlying Aliyun OpenAPI error will not
+// resolve by retrying the same request, so callers should stop requeueing.
+func IsTerminalError(err error) bool {
+	if err == nil || IsThrottlingError(err) || IsLocalRateLimited(err) {
 		return false
 	}
-	msg := err.Error()
-	return strings.Contains(msg, "Throttling") ||
-		strings.Contains(msg, "RequestLimitExceeded") ||
-		strings.Contains(msg, "ServiceUnavailable")
+	return hasErrorCode(err, terminalErrorCodes...)
+}
+
+// IsModificationProtectionError returns true when the underlying Aliyun OpenAPI error
+// indicates the operation was rejected because modification protection is enabled.
+// The protection is named by the part of the code after the category, e.g.
+// "OperationDenied.ModificationProtection", so every part of the code is matched.
+func IsModificationProtectionError(err error) bool {
+	for _, part := range strings.Split(ErrorCode(err), ".") {
+		if strings.HasPrefix(part, "ModificationProtection") || strings.HasPrefix(part, "ConsoleProtection") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIncorrectStatusError returns true when the underlying Aliyun OpenAPI error
+// indicates the resource is busy with another operation (e.g. the NLB is Configuring).
+func IsIncorrectStatusError(err error) bool {
+	return hasErrorCode(err, "IncorrectStatus", "Conflict.Lock", "OperationFailed.ResourceStatusNotSupport")
+}
+
+// IsGetXipFailedError returns true when the underlying Aliyun OpenAPI error is
+// GetXipFailed, raised transiently while the addresses of a new NLB are allocated.
+func IsGetXipFailedError(err error) bool {
+	return ErrorCode(err) == "GetXipFailed"
 }
 
 // IsResourceAlreadyExistsError returns true when the underlying Aliyun OpenAPI error
 // indicates that the resource already exists (used for optimistic create fallback).
 func IsResourceAlreadyExistsError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := err.Error()
-	return strings.Contains(msg, "ResourceAlreadyExists") ||
-		strings.Contains(msg, "ListenerAlreadyExists") ||
-		strings.Contains(msg, "DuplicateListener") ||
-		strings.Contains(msg, "ResourceInUse")
+	return hasErrorCode(err, "ResourceAlreadyExists", "ListenerAlreadyExists", "DuplicateListener", "ResourceInUse")
 }
 
 // CreateServerGroup creates a backend server group on Alibaba Cloud NLB.
@@ -127,14 +223,22 @@ func (c *NLBClient) CreateServerGroup(ctx context.Context, sg *nlbv1.ServerGroup
 		req.HealthCheckConfig = hc
 	}
 
+	if sg.Spec.ConnectionDrainEnabled {
+		req.ConnectionDrainEnabled = tea.Bool(true)
+		req.ConnectionDrainTimeout = tea.Int32(sg.Spec.ConnectionDrainTimeout)
+	}
+
+	if sg.Spec.PreserveClientIpEnabled != nil {
+		req.PreserveClientIpEnabled = sg.Spec.PreserveClientIpEnabled
+	}
+
 	// ClientToken bound to CR UID to ensure idempotent create even on retries.
 	if sg.UID != "" {
 		req.ClientToken = tea.String(fmt.Sprintf("sg-%s", string(sg.UID)))
 	}
-
-	resp, err := c.client.CreateServerGroup(req)
+	resp, err := doRequest(ctx, c, req, c.client.CreateServerGroup)
 	if err != nil {
-		return "", fmt.Errorf("failed to create server group: %v", err)
+		return "", fmt.Errorf("failed to create server group: %w", err)
 	}
 	if resp == nil || resp.Body == nil || resp.Body.ServerGroupId == nil {
 		return "", fmt.Errorf("invalid response from CreateServerGroup API")
@@ -156,12 +260,12 @@ func (c *NLBClient) GetServerGroupAttribute(ctx context.Context, sgId string) (*
 	req := &nlbsdk.ListServerGroupsRequest{
 		ServerGroupIds: tea.StringSlice([]string{sgId}),
 	}
-	resp, err := c.client.ListServerGroups(req)
+	resp, err := doRequest(ctx, c, req, c.client.ListServerGroups)
 	if err != nil {
 		if IsNotFoundError(err) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to list server groups by id %s: %v", sgId, err)
+		return nil, fmt.Errorf("failed to list server groups by id %s: %w", sgId, err)
 	}
 	if resp == nil || resp.Body == nil {
 		return nil, fmt.Errorf("invalid response from ListServerGroups API")
@@ -176,12 +280,86 @@ func (c *NLBClient) GetServerGroupAttribute(ctx context.Context, sgId string) (*
 				ServerGroupName:   tea.StringValue(sg.ServerGroupName),
 				ServerGroupStatus: tea.StringValue(sg.ServerGroupStatus),
 				VpcId:             tea.StringValue(sg.VpcId),
+				Scheduler:         tea.StringValue(sg.Scheduler),
+
+				ConnectionDrainEnabled:  tea.BoolValue(sg.ConnectionDrainEnabled),
+				ConnectionDrainTimeout:  tea.Int32Value(sg.ConnectionDrainTimeout),
+				PreserveClientIpEnabled: tea.BoolValue(sg.PreserveClientIpEnabled),
 			}, nil
 		}
 	}
 	return nil, nil
 }
 
+// UpdateServerGroupConnectionDrain updates the connection drain settings of a server group.
+func (c *NLBClient) UpdateServerGroupConnectionDrain(ctx context.Context, sgId string, enabled bool, timeout int32) error {
+	req := &nlbsdk.UpdateServerGroupAttributeRequest{
+		ServerGroupId:          tea.String(sgId),
+		ConnectionDrainEnabled: tea.Bool(enabled),
+	}
+	if enabled {
+		req.ConnectionDrainTimeout = tea.Int32(timeout)
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateServerGroupAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update server group %s: %w", sgId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateServerGroupAttribute API")
+	}
+
+	klog.V(5).Infof("Successfully updated connection drain of ServerGroup: %s, RequestId: %s", sgId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateServerGroupPreserveClientIp updates whether a server group preserves the client source IP.
+func (c *NLBClient) UpdateServerGroupPreserveClientIp(ctx context.Context, sgId string, enabled bool) error {
+	req := &nlbsdk.UpdateServerGroupAttributeRequest{
+		ServerGroupId:           tea.String(sgId),
+		PreserveClientIpEnabled: tea.Bool(enabled),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateServerGroupAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update server group %s: %w", sgId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateServerGroupAttribute API")
+	}
+
+	klog.V(5).Infof("Successfully updated preserve client IP of ServerGroup: %s, RequestId: %s", sgId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateServerGroupScheduler updates the scheduling algorithm of a server group.
+func (c *NLBClient) UpdateServerGroupScheduler(ctx context.Context, sgId, scheduler string) error {
+	req := &nlbsdk.UpdateServerGroupAttributeRequest{
+		ServerGroupId: tea.String(sgId),
+		Scheduler:     tea.String(scheduler),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateServerGroupAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update server group %s: %w", sgId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateServerGroupAttribute API")
+	}
+
+	klog.V(5).Infof("Successfully updated scheduler of ServerGroup: %s, RequestId: %s", sgId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
 // DeleteServerGroup deletes a backend server group by ID.
 // Returns nil if the server group does not exist (already deleted).
 func (c *NLBClient) DeleteServerGroup(ctx context.Context, sgId string) error {
@@ -191,13 +369,13 @@ func (c *NLBClient) DeleteServerGroup(ctx context.Context, sgId string) error {
 	req := &nlbsdk.DeleteServerGroupRequest{
 		ServerGroupId: tea.String(sgId),
 	}
-	resp, err := c.client.DeleteServerGroup(req)
+	resp, err := doRequest(ctx, c, req, c.client.DeleteServerGroup)
 	if err != nil {
 		if IsNotFoundError(err) {
 			klog.Infof("ServerGroup %s not found, assuming already deleted", sgId)
 			return nil
 		}
-		return fmt.Errorf("failed to delete server group %s: %v", sgId, err)
+		return fmt.Errorf("failed to delete server group %s: %w", sgId, err)
 	}
 	if resp == nil || resp.Body == nil {
 		return fmt.Errorf("invalid response from DeleteServerGroup API")
@@ -221,12 +399,12 @@ func (c *NLBClient) ListServerGroups(ctx context.Context, vpcId, name string) (s
 	}
 
 	for {
-		resp, err := c.client.ListServerGroups(req)
+		resp, err := doRequest(ctx, c, req, c.client.ListServerGroups)
 		if err != nil {
 			if IsNotFoundError(err) {
 				return "", nil
 			}
-			return "", fmt.Errorf("failed to list server groups by name %s: %v", name, err)
+			return "", fmt.Errorf("failed to list server groups by name %s: %w", name, err)
 		}
 		if resp == nil || resp.Body == nil {
 			return "", fmt.Errorf("invalid response from ListServerGroups API")
@@ -251,20 +429,118 @@ func (c *NLBClient) ListServerGroups(ctx context.Context, vpcId, name string) (s
 	}
 }
 
-// CreateNLBListener creates a TCP/UDP/TCPSSL listener bound to the given NLB and ServerGroup.
-func (c *NLBClient) CreateNLBListener(ctx context.Context, nlbId, sgId string, port int32, protocol string) (string, error) {
+// CreateNLBListener creates a TCP/UDP/TCPSSL listener bound to the given NLB and ServerGroup
+// with the optional settings of opts.
+func (c *NLBClient) CreateNLBListener(ctx context.Context, nlbId, sgId string, port int32, protocol string, opts ListenerOptions) (string, error) {
 	if c.CreateListenerLimiter != nil && !c.CreateListenerLimiter.Allow() {
 		return "", ErrCreateListenerRateLimited
 	}
 	if nlbId == "" || sgId == "" {
 		return "", fmt.Errorf("nlbId and serverGroupId are required to create listener")
 	}
+	req, err := newCreateListenerRequest(nlbId, sgId, port, protocol, opts)
+	if err != nil {
+		return "", err
+	}
+	if err := c.ValidateSecurityPolicy(ctx, opts.SecurityPolicyId); err != nil {
+		return "", err
+	}
+	resp, err := doRequest(ctx, c, req, c.client.CreateListener)
+	if err != nil {
+		return "", fmt.Errorf("failed to create listener (nlb=%s, port=%d, protocol=%s): %w",
+			nlbId, port, protocol, err)
+	}
+	if resp == nil || resp.Body == nil || resp.Body.ListenerId == nil {
+		return "", fmt.Errorf("invalid response from CreateListener API")
+	}
+
+	listenerId := tea.StringValue(resp.Body.ListenerId)
+	klog.Infof("Successfully created NLB Listener: %s (nlb=%s, port=%d, protocol=%s), RequestId: %s",
+		listenerId, nlbId, port, protocol, tea.StringValue(resp.Body.RequestId))
+	return listenerId, nil
+}
+
+// tlsListenerOptions returns the names of the TLS-only settings in opts, which
+// TCP and UDP listeners reject.
+func tlsListenerOptions(opts ListenerOptions) []string {
+	var fields []string
+	if opts.SecurityPolicyId != "" {
+		fields = append(fields, "securityPolicyId")
+	}
+	if opts.Alpn != nil {
+		fields = append(fields, "alpn")
+	}
+	if opts.CA != nil {
+		fields = append(fields, "ca")
+	}
+	return fields
+}
+
+// newCreateListenerRequest builds the CreateListener request for a listener of
+// protocol. Settings the protocol does not support are rejected rather than
+// dropped, so a TCP or UDP listener never silently loses part of its spec.
+func newCreateListenerRequest(nlbId, sgId string, port int32, protocol string, opts ListenerOptions) (*nlbsdk.CreateListenerRequest, error) {
+	if protocol != "TCPSSL" {
+		if fields := tlsListenerOptions(opts); len(fields) > 0 {
+			return nil, fmt.Errorf("%s can only be set on TCPSSL listeners, listener on port %d uses %s",
+				strings.Join(fields, ", "), port, protocol)
+		}
+	}
+	if opts.CA != nil && opts.CA.Enabled && len(opts.CA.CertificateIds) == 0 {
+		return nil, fmt.Errorf("mutual TLS on port %d requires at least one CA certificate", port)
+	}
+	if opts.IdleTimeout != nil {
+		if min, max := ListenerIdleTimeoutRange(protocol); *opts.IdleTimeout < min || *opts.IdleTimeout > max {
+			return nil, fmt.Errorf("idle timeout %d of %s listener on port %d must be between %d and %d seconds",
+				*opts.IdleTimeout, protocol, port, min, max)
+		}
+	}
+	if pp := opts.ProxyProtocol; pp != nil && pp.V2 != nil && !pp.Enabled {
+		return nil, fmt.Errorf("proxy protocol v2 options on port %d require proxy protocol to be enabled", port)
+	}
+
 	req := &nlbsdk.CreateListenerRequest{
 		LoadBalancerId:   tea.String(nlbId),
 		ListenerProtocol: tea.String(protocol),
 		ListenerPort:     tea.Int32(port),
 		ServerGroupId:    tea.String(sgId),
 	}
+	if opts.Description != "" {
+		req.ListenerDescription = tea.String(opts.Description)
+	}
+	if opts.Cps != nil {
+		req.Cps = tea.Int32(*opts.Cps)
+	}
+	if opts.IdleTimeout != nil {
+		req.IdleTimeout = tea.Int32(*opts.IdleTimeout)
+	}
+	if pp := opts.ProxyProtocol; pp != nil {
+		req.ProxyProtocolEnabled = tea.Bool(pp.Enabled)
+		if pp.V2 != nil {
+			req.ProxyProtocolV2Config = &nlbsdk.CreateListenerRequestProxyProtocolV2Config{
+				Ppv2VpcIdEnabled:            tea.Bool(pp.V2.VpcIdEnabled),
+				Ppv2PrivateLinkEpIdEnabled:  tea.Bool(pp.V2.PrivateLinkEpIdEnabled),
+				Ppv2PrivateLinkEpsIdEnabled: tea.Bool(pp.V2.PrivateLinkEpsIdEnabled),
+			}
+		}
+	}
+	if protocol == "TCPSSL" {
+		if opts.SecurityPolicyId != "" {
+			req.SecurityPolicyId = tea.String(opts.SecurityPolicyId)
+		}
+		if opts.Alpn != nil {
+			req.AlpnEnabled = tea.Bool(opts.Alpn.Enabled)
+			if opts.Alpn.Enabled {
+				req.AlpnPolicy = tea.String(opts.Alpn.Policy)
+			}
+		}
+		if opts.CA != nil {
+			req.CaEnabled = tea.Bool(opts.CA.Enabled)
+			if len(opts.CA.CertificateIds) > 0 {
+				req.CaCertificateIds = tea.StringSlice(opts.CA.CertificateIds)
+			}
+		}
+	}
 
 	// ClientToken bound to business key (NLB ID + Port + Protocol) for idempotent create.
 	// Do NOT bind to CR UID as CR may be recreated.
@@ -275,20 +551,7 @@ func (c *NLBClient) CreateNLBListener(ctx context.Context, nlbId, sgId string, p
 	}
 	req.ClientToken = tea.String(clientToken)
 	req.DryRun = tea.Bool(false)
-
-	resp, err := c.client.CreateListener(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to create listener (nlb=%s, port=%d, protocol=%s): %v",
-			nlbId, port, protocol, err)
-	}
-	if resp == nil || resp.Body == nil || resp.Body.ListenerId == nil {
-		return "", fmt.Errorf("invalid response from CreateListener API")
-	}
-
-	listenerId := tea.StringValue(resp.Body.ListenerId)
-	klog.Infof("Successfully created NLB Listener: %s (nlb=%s, port=%d, protocol=%s), RequestId: %s",
-		listenerId, nlbId, port, protocol, tea.StringValue(resp.Body.RequestId))
-	return listenerId, nil
+	return req, nil
 }
 
 // GetListenerAttribute fetches a listener's current attributes by ID.
@@ -305,24 +568,42 @@ func (c *NLBClient) GetListenerAttribute(ctx context.Context, listenerId string)
 	req := &nlbsdk.GetListenerAttributeRequest{
 		ListenerId: tea.String(listenerId),
 	}
-	resp, err := c.client.GetListenerAttribute(req)
+	resp, err := doRequest(ctx, c, req, c.client.GetListenerAttribute)
 	if err != nil {
 		if IsNotFoundError(err) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to get listener %s: %v", listenerId, err)
+		return nil, fmt.Errorf("failed to get listener %s: %w", listenerId, err)
 	}
 	if resp == nil || resp.Body == nil {
 		return nil, fmt.Errorf("invalid response from GetListenerAttribute API")
 	}
 	body := resp.Body
+	var ppv2 nlbv1.ProxyProtocolV2Config
+	if v2 := body.ProxyProtocolV2Config; v2 != nil {
+		ppv2 = nlbv1.ProxyProtocolV2Config{
+			VpcIdEnabled:            tea.BoolValue(v2.Ppv2VpcIdEnabled),
+			PrivateLinkEpIdEnabled:  tea.BoolValue(v2.Ppv2PrivateLinkEpIdEnabled),
+			PrivateLinkEpsIdEnabled: tea.BoolValue(v2.Ppv2PrivateLinkEpsIdEnabled),
+		}
+	}
 	return &ListenerAttribute{
-		ListenerId:       tea.StringValue(body.ListenerId),
-		ListenerStatus:   tea.StringValue(body.ListenerStatus),
-		ListenerPort:     tea.Int32Value(body.ListenerPort),
-		ListenerProtocol: tea.StringValue(body.ListenerProtocol),
-		LoadBalancerId:   tea.StringValue(body.LoadBalancerId),
-		ServerGroupId:    tea.StringValue(body.ServerGroupId),
+		ListenerId:           tea.StringValue(body.ListenerId),
+		ListenerStatus:       tea.StringValue(body.ListenerStatus),
+		ListenerPort:         tea.Int32Value(body.ListenerPort),
+		ListenerProtocol:     tea.StringValue(body.ListenerProtocol),
+		LoadBalancerId:       tea.StringValue(body.LoadBalancerId),
+		ServerGroupId:        tea.StringValue(body.ServerGroupId),
+		AlpnEnabled:          tea.BoolValue(body.AlpnEnabled),
+		AlpnPolicy:           tea.StringValue(body.AlpnPolicy),
+		ListenerDescription:  tea.StringValue(body.ListenerDescription),
+		Cps:                  tea.Int32Value(body.Cps),
+		IdleTimeout:          tea.Int32Value(body.IdleTimeout),
+		SecurityPolicyId:     tea.StringValue(body.SecurityPolicyId),
+		CaEnabled:            tea.BoolValue(body.CaEnabled),
+		CaCertificateIds:     tea.StringSliceValue(body.CaCertificateIds),
+		ProxyProtocolEnabled: tea.BoolValue(body.ProxyProtocolEnabled),
+		ProxyProtocolV2:      ppv2,
 	}, nil
 }
 
@@ -334,13 +615,13 @@ func (c *NLBClient) DeleteNLBListener(ctx context.Context, listenerId string) er
 	req := &nlbsdk.DeleteListenerRequest{
 		ListenerId: tea.String(listenerId),
 	}
-	resp, err := c.client.DeleteListener(req)
+	resp, err := doRequest(ctx, c, req, c.client.DeleteListener)
 	if err != nil {
 		if IsNotFoundError(err) {
 			klog.Infof("Listener %s not found, assuming already deleted", listenerId)
 			return nil
 		}
-		return fmt.Errorf("failed to delete listener %s: %v", listenerId, err)
+		return fmt.Errorf("failed to delete listener %s: %w", listenerId, err)
 	}
 	if resp == nil || resp.Body == nil {
 		return fmt.Errorf("invalid response from DeleteListener API")
@@ -350,26 +631,269 @@ func (c *NLBClient) DeleteNLBListener(ctx context.Context, listenerId string) er
 	return nil
 }
 
+// StartListener starts a stopped listener and waits for the async job to finish.
+func (c *NLBClient) StartListener(ctx context.Context, listenerId string) error {
+	req := &nlbsdk.StartListenerRequest{
+		ListenerId: tea.String(listenerId),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.StartListener)
+	if err != nil {
+		return fmt.Errorf("failed to start listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from StartListener API")
+	}
+	klog.Infof("Successfully called StartListener: %s, RequestId: %s",
+		listenerId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// StopListener stops a running listener without deleting it and waits for the
+// async job to finish.
+func (c *NLBClient) StopListener(ctx context.Context, listenerId string) error {
+	req := &nlbsdk.StopListenerRequest{
+		ListenerId: tea.String(listenerId),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.StopListener)
+	if err != nil {
+		return fmt.Errorf("failed to stop listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from StopListener API")
+	}
+	klog.Infof("Successfully called StopListener: %s, RequestId: %s",
+		listenerId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerServerGroup points an existing listener at another server group
+// and waits for the async job to finish.
+func (c *NLBClient) UpdateListenerServerGroup(ctx context.Context, listenerId, sgId string) error {
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId:    tea.String(listenerId),
+		ServerGroupId: tea.String(sgId),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to switch listener %s to server group %s: %w", listenerId, sgId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully switched listener: %s to server group: %s, RequestId: %s",
+		listenerId, sgId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerDescription sets the description of an existing listener and
+// waits for the async job to finish.
+func (c *NLBClient) UpdateListenerDescription(ctx context.Context, listenerId, description string) error {
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId:          tea.String(listenerId),
+		ListenerDescription: tea.String(description),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update description of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated description of listener: %s to %q, RequestId: %s",
+		listenerId, description, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerAlpn updates the ALPN configuration of an existing TCPSSL listener
+// and waits for the async job to finish.
+func (c *NLBClient) UpdateListenerAlpn(ctx context.Context, listenerId string, alpn ListenerAlpn) error {
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId:  tea.String(listenerId),
+		AlpnEnabled: tea.Bool(alpn.Enabled),
+	}
+	if alpn.Enabled {
+		req.AlpnPolicy = tea.String(alpn.Policy)
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update ALPN of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated ALPN of listener: %s (enabled=%t, policy=%s), RequestId: %s",
+		listenerId, alpn.Enabled, alpn.Policy, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerCps sets the new connections per second limit of an existing
+// listener, 0 for unlimited, and waits for the async job to finish.
+func (c *NLBClient) UpdateListenerCps(ctx context.Context, listenerId string, cps int32) error {
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId: tea.String(listenerId),
+		Cps:        tea.Int32(cps),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update CPS limit of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated CPS limit of listener: %s to %d, RequestId: %s",
+		listenerId, cps, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerIdleTimeout sets the idle connection timeout in seconds of an
+// existing listener of protocol and waits for the async job to finish.
+func (c *NLBClient) UpdateListenerIdleTimeout(ctx context.Context, listenerId, protocol string, idleTimeout int32) error {
+	if min, max := ListenerIdleTimeoutRange(protocol); idleTimeout < min || idleTimeout > max {
+		return fmt.Errorf("idle timeout %d of %s listener %s must be between %d and %d seconds",
+			idleTimeout, protocol, listenerId, min, max)
+	}
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId:  tea.String(listenerId),
+		IdleTimeout: tea.Int32(idleTimeout),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update idle timeout of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated idle timeout of listener: %s to %ds, RequestId: %s",
+		listenerId, idleTimeout, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerCA updates the mutual TLS configuration of an existing TCPSSL
+// listener and waits for the async job to finish.
+func (c *NLBClient) UpdateListenerCA(ctx context.Context, listenerId string, ca ListenerCA) error {
+	if ca.Enabled && len(ca.CertificateIds) == 0 {
+		return fmt.Errorf("mutual TLS on listener %s requires at least one CA certificate", listenerId)
+	}
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId: tea.String(listenerId),
+		CaEnabled:  tea.Bool(ca.Enabled),
+	}
+	if len(ca.CertificateIds) > 0 {
+		req.CaCertificateIds = tea.StringSlice(ca.CertificateIds)
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update mutual TLS of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated mutual TLS of listener: %s (enabled=%t, caCertificates=%v), RequestId: %s",
+		listenerId, ca.Enabled, ca.CertificateIds, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// UpdateListenerProxyProtocol updates the proxy protocol configuration of an
+// existing listener and waits for the async job to finish.
+func (c *NLBClient) UpdateListenerProxyProtocol(ctx context.Context, listenerId string, pp ListenerProxyProtocol) error {
+	if pp.V2 != nil && !pp.Enabled {
+		return fmt.Errorf("proxy protocol v2 options of listener %s require proxy protocol to be enabled", listenerId)
+	}
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId:           tea.String(listenerId),
+		ProxyProtocolEnabled: tea.Bool(pp.Enabled),
+	}
+	if pp.V2 != nil {
+		req.ProxyProtocolV2Config = &nlbsdk.UpdateListenerAttributeRequestProxyProtocolV2Config{
+			Ppv2VpcIdEnabled:            tea.Bool(pp.V2.VpcIdEnabled),
+			Ppv2PrivateLinkEpIdEnabled:  tea.Bool(pp.V2.PrivateLinkEpIdEnabled),
+			Ppv2PrivateLinkEpsIdEnabled: tea.Bool(pp.V2.PrivateLinkEpsIdEnabled),
+		}
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update proxy protocol of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated proxy protocol of listener: %s (enabled=%t), RequestId: %s",
+		listenerId, pp.Enabled, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
 // ListListeners looks up a listener ID by NLB and listener port (idempotency check).
 // Returns "" when no matching listener exists.
 func (c *NLBClient) ListListeners(ctx context.Context, nlbId string, port int32) (string, error) {
+	listeners, err := c.ListLoadBalancerListeners(ctx, nlbId)
+	if err != nil {
+		return "", err
+	}
+	for _, lsn := range listeners {
+		if lsn.ListenerPort == port {
+			return lsn.ListenerId, nil
+		}
+	}
+	return "", nil
+}
+
+// ListLoadBalancerListeners returns every listener on the given NLB, including
+// listeners created outside the operator.
+func (c *NLBClient) ListLoadBalancerListeners(ctx context.Context, nlbId string) ([]ListenerAttribute, error) {
 	if nlbId == "" {
-		return "", nil
+		return nil, nil
 	}
 	req := &nlbsdk.ListListenersRequest{
 		LoadBalancerIds: tea.StringSlice([]string{nlbId}),
 	}
 
+	var listeners []ListenerAttribute
 	for {
-		resp, err := c.client.ListListeners(req)
+		resp, err := doRequest(ctx, c, req, c.client.ListListeners)
 		if err != nil {
 			if IsNotFoundError(err) {
-				return "", nil
+				return nil, nil
 			}
-			return "", fmt.Errorf("failed to list listeners on nlb %s: %v", nlbId, err)
+			return nil, fmt.Errorf("failed to list listeners on nlb %s: %w", nlbId, err)
 		}
 		if resp == nil || resp.Body == nil {
-			return "", fmt.Errorf("invalid response from ListListeners API")
+			return nil, fmt.Errorf("invalid response from ListListeners API")
 		}
 		for _, lsn := range resp.Body.Listeners {
 			if lsn == nil {
@@ -378,14 +902,106 @@ func (c *NLBClient) ListListeners(ctx context.Context, nlbId string, port int32)
 			if tea.StringValue(lsn.LoadBalancerId) != nlbId {
 				continue
 			}
-			if tea.Int32Value(lsn.ListenerPort) == port {
-				return tea.StringValue(lsn.ListenerId), nil
+			listeners = append(listeners, ListenerAttribute{
+				ListenerId:          tea.StringValue(lsn.ListenerId),
+				ListenerStatus:      tea.StringValue(lsn.ListenerStatus),
+				ListenerPort:        tea.Int32Value(lsn.ListenerPort),
+				ListenerProtocol:    tea.StringValue(lsn.ListenerProtocol),
+				LoadBalancerId:      tea.StringValue(lsn.LoadBalancerId),
+				ServerGroupId:       tea.StringValue(lsn.ServerGroupId),
+				ListenerDescription: tea.StringValue(lsn.ListenerDescription),
+				Cps:                 tea.Int32Value(lsn.Cps),
+			})
+		}
+		next := tea.StringValue(resp.Body.NextToken)
+		if next == "" {
+			return listeners, nil
+		}
+		req.NextToken = tea.String(next)
+	}
+}
+
+// ListAdditionalCertificates returns the IDs of the non-default server certificates
+// (SNI certificates) currently associated with a TCPSSL listener.
+func (c *NLBClient) ListAdditionalCertificates(ctx context.Context, listenerId string) ([]string, error) {
+	req := &nlbsdk.ListListenerCertificatesRequest{
+		ListenerId: tea.String(listenerId),
+		CertType:   tea.String("Server"),
+	}
+
+	var certIds []string
+	for {
+		resp, err := doRequest(ctx, c, req, c.client.ListListenerCertificates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list certificates of listener %s: %w", listenerId, err)
+		}
+		if resp == nil || resp.Body == nil {
+			return nil, fmt.Errorf("invalid response from ListListenerCertificates API")
+		}
+		for _, cert := range resp.Body.Certificates {
+			if cert == nil || tea.BoolValue(cert.IsDefault) {
+				continue
 			}
+			certIds = append(certIds, tea.StringValue(cert.CertificateId))
 		}
 		next := tea.StringValue(resp.Body.NextToken)
 		if next == "" {
-			return "", nil
+			return certIds, nil
 		}
 		req.NextToken = tea.String(next)
 	}
 }
+
+// AssociateAdditionalCertificates attaches SNI certificates to a TCPSSL listener.
+func (c *NLBClient) AssociateAdditionalCertificates(ctx context.Context, listenerId string, certIds []string) error {
+	if len(certIds) == 0 {
+		return nil
+	}
+
+	req := &nlbsdk.AssociateAdditionalCertificatesWithListenerRequest{
+		ListenerId:               tea.String(listenerId),
+		AdditionalCertificateIds: tea.StringSlice(certIds),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.AssociateAdditionalCertificatesWithListener)
+	if err != nil {
+		return fmt.Errorf("failed to associate additional certificates with listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from AssociateAdditionalCertificatesWithListener API")
+	}
+
+	klog.V(5).Infof("Successfully associated certificates %v with listener: %s, RequestId: %s",
+		certIds, listenerId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
+
+// DissociateAdditionalCertificates detaches SNI certificates from a TCPSSL listener.
+func (c *NLBClient) DissociateAdditionalCertificates(ctx context.Context, listenerId string, certIds []string) error {
+	if len(certIds) == 0 {
+		return nil
+	}
+
+	req := &nlbsdk.DisassociateAdditionalCertificatesWithListenerRequest{
+		ListenerId:               tea.String(listenerId),
+		AdditionalCertificateIds: tea.StringSlice(certIds),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.DisassociateAdditionalCertificatesWithListener)
+	if err != nil {
+		return fmt.Errorf("failed to dissociate additional certificates from listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from DisassociateAdditionalCertificatesWithListener API")
+	}
+
+	klog.V(5).Infof("Successfully dissociated certificates %v from listener: %s, RequestId: %s",
+		certIds, listenerId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alibabacloud-go/tea/tea"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+func TestNewCreateListenerRequestProtocolFields(t *testing.T) {
+	int32Ptr := func(v int32) *int32 { return &v }
+	tlsOpts := ListenerOptions{
+		SecurityPolicyId: "tls_cipher_policy_1_2",
+		Alpn:             &ListenerAlpn{Enabled: true, Policy: nlbv1.AlpnPolicyHTTP2Preferred},
+		CA:               &ListenerCA{Enabled: true, CertificateIds: []string{"ca-1"}},
+	}
+
+	tests := []struct {
+		name     string
+		protocol string
+		opts     ListenerOptions
+		wantErr  string
+	}{
+		{
+			name:     "TCP common settings",
+			protocol: "TCP",
+			opts:     ListenerOptions{Cps: int32Ptr(100), IdleTimeout: int32Ptr(900), ProxyProtocol: &ListenerProxyProtocol{Enabled: true}},
+		},
+		{
+			name:     "UDP common settings",
+			protocol: "UDP",
+			opts: ListenerOptions{Cps: int32Ptr(100), IdleTimeout: int32Ptr(20),
+				ProxyProtocol: &ListenerProxyProtocol{Enabled: true, V2: &nlbv1.ProxyProtocolV2Config{VpcIdEnabled: true}}},
+		},
+		{name: "TCPSSL TLS settings", protocol: "TCPSSL", opts: tlsOpts},
+		{name: "TCP security policy", protocol: "TCP", opts: ListenerOptions{SecurityPolicyId: "tls_cipher_policy_1_2"}, wantErr: "securityPolicyId can only be set on TCPSSL"},
+		{name: "UDP ALPN", protocol: "UDP", opts: ListenerOptions{Alpn: tlsOpts.Alpn}, wantErr: "alpn can only be set on TCPSSL"},
+		{name: "UDP mutual TLS", protocol: "UDP", opts: ListenerOptions{CA: tlsOpts.CA}, wantErr: "ca can only be set on TCPSSL"},
+		{name: "TCP all TLS settings", protocol: "TCP", opts: tlsOpts, wantErr: "securityPolicyId, alpn, ca can only be set on TCPSSL"},
+		{name: "UDP idle timeout above UDP range", protocol: "UDP", opts: ListenerOptions{IdleTimeout: int32Ptr(21)}, wantErr: "between 1 and 20 seconds"},
+		{name: "TCP idle timeout above range", protocol: "TCP", opts: ListenerOptions{IdleTimeout: int32Ptr(901)}, wantErr: "between 1 and 900 seconds"},
+		{
+			name:     "proxy protocol v2 without proxy protocol",
+			protocol: "TCP",
+			opts:     ListenerOptions{ProxyProtocol: &ListenerProxyProtocol{V2: &nlbv1.ProxyProtocolV2Config{VpcIdEnabled: true}}},
+			wantErr:  "require proxy protocol to be enabled",
+		},
+		{name: "mutual TLS without CA certificate", protocol: "TCPSSL", opts: ListenerOptions{CA: &ListenerCA{Enabled: true}}, wantErr: "at least one CA certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newCreateListenerRequest("nlb-1", "sgp-1", 443, tt.protocol, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := tea.StringValue(req.ListenerProtocol); got != tt.protocol {
+				t.Errorf("ListenerProtocol = %q, want %q", got, tt.protocol)
+			}
+			if tt.opts.Cps != nil && tea.Int32Value(req.Cps) != *tt.opts.Cps {
+				t.Errorf("Cps = %d, want %d", tea.Int32Value(req.Cps), *tt.opts.Cps)
+			}
+			if tt.opts.IdleTimeout != nil && tea.Int32Value(req.IdleTimeout) != *tt.opts.IdleTimeout {
+				t.Errorf("IdleTimeout = %d, want %d", tea.Int32Value(req.IdleTimeout), *tt.opts.IdleTimeout)
+			}
+			if pp := tt.opts.ProxyProtocol; pp != nil {
+				if tea.BoolValue(req.ProxyProtocolEnabled) != pp.Enabled {
+					t.Errorf("ProxyProtocolEnabled = %t, want %t", tea.BoolValue(req.ProxyProtocolEnabled), pp.Enabled)
+				}
+				if (pp.V2 != nil) != (req.ProxyProtocolV2Config != nil) {
+					t.Errorf("ProxyProtocolV2Config = %v, want set %t", req.ProxyProtocolV2Config, pp.V2 != nil)
+				}
+			}
+
+			hasTLS := req.SecurityPolicyId != nil || req.AlpnEnabled != nil || req.AlpnPolicy != nil ||
+				req.CaEnabled != nil || req.CaCertificateIds != nil
+			if wantTLS := tt.protocol == "TCPSSL"; hasTLS != wantTLS {
+				t.Errorf("request carries TLS settings = %t, want %t", hasTLS, wantTLS)
+			}
+			if tt.protocol == "TCPSSL" {
+				if got := tea.StringValue(req.SecurityPolicyId); got != tt.opts.SecurityPolicyId {
+					t.Errorf("SecurityPolicyId = %q, want %q", got, tt.opts.SecurityPolicyId)
+				}
+				if got := tea.StringSliceValue(req.CaCertificateIds); len(got) != 1 || got[0] != "ca-1" {
+					t.Errorf("CaCertificateIds = %v, want [ca-1]", got)
+				}
+			}
+		})
+	}
+}
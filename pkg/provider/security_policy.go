@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	nlbsdk "github.com/alibabacloud-go/nlb-20220430/v4/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"k8s.io/klog/v2"
+)
+
+// SystemSecurityPolicies are the built-in TLS security policies of NLB. Any
+// other SecurityPolicyId must name a custom policy of the account.
+var SystemSecurityPolicies = []string{
+	"tls_cipher_policy_1_0",
+	"tls_cipher_policy_1_1",
+	"tls_cipher_policy_1_2",
+	"tls_cipher_policy_1_2_strict",
+	"tls_cipher_policy_1_2_strict_with_1_3",
+}
+
+// IsSystemSecurityPolicy reports whether id is a built-in TLS security policy.
+func IsSystemSecurityPolicy(id string) bool {
+	for _, p := range SystemSecurityPolicies {
+		if p == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityPolicy is a thin abstraction over a custom TLS security policy.
+type SecurityPolicy struct {
+	SecurityPolicyId     string
+	SecurityPolicyName   string
+	SecurityPolicyStatus string
+	TlsVersions          []string
+	Ciphers              []string
+}
+
+// ValidateSecurityPolicy checks that id is a built-in TLS security policy or an
+// existing custom policy, so a typo fails with a clear message instead of a
+// parameter error from CreateListener.
+func (c *NLBClient) ValidateSecurityPolicy(ctx context.Context, id string) error {
+	if id == "" || IsSystemSecurityPolicy(id) {
+		return nil
+	}
+	policy, err := c.GetSecurityPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("security policy %s is neither a built-in policy (%v) nor an existing custom policy",
+			id, SystemSecurityPolicies)
+	}
+	return nil
+}
+
+// GetSecurityPolicy returns the custom TLS security policy with the given ID, or
+// nil if it does not exist.
+func (c *NLBClient) GetSecurityPolicy(ctx context.Context, id string) (*SecurityPolicy, error) {
+	req := &nlbsdk.ListSecurityPolicyRequest{
+		SecurityPolicyIds: tea.StringSlice([]string{id}),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.ListSecurityPolicy)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe security policy %s: %w", id, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return nil, fmt.Errorf("invalid response from ListSecurityPolicy API")
+	}
+	for _, p := range resp.Body.SecurityPolicies {
+		if p == nil || tea.StringValue(p.SecurityPolicyId) != id {
+			continue
+		}
+		return &SecurityPolicy{
+			SecurityPolicyId:     id,
+			SecurityPolicyName:   tea.StringValue(p.SecurityPolicyName),
+			SecurityPolicyStatus: tea.StringValue(p.SecurityPolicyStatus),
+			TlsVersions:          splitList(tea.StringValue(p.TlsVersion)),
+			Ciphers:              splitList(tea.StringValue(p.Ciphers)),
+		}, nil
+	}
+	return nil, nil
+}
+
+// splitList splits a comma-separated list as returned by ListSecurityPolicy,
+// dropping blanks around and between the items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// CreateSecurityPolicy creates a custom TLS security policy allowing the given
+// TLS versions (e.g. TLSv1.2) and cipher suites, and waits for the async job to
+// finish. It returns the ID of the new policy.
+func (c *NLBClient) CreateSecurityPolicy(ctx context.Context, name string, tlsVersions, ciphers []string) (string, error) {
+	if len(tlsVersions) == 0 || len(ciphers) == 0 {
+		return "", fmt.Errorf("security policy %s requires at least one TLS version and cipher", name)
+	}
+	req := &nlbsdk.CreateSecurityPolicyRequest{
+		SecurityPolicyName: tea.String(name),
+		TlsVersions:        tea.StringSlice(tlsVersions),
+		Ciphers:            tea.StringSlice(ciphers),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.CreateSecurityPolicy)
+	if err != nil {
+		return "", fmt.Errorf("failed to create security policy %s: %w", name, err)
+	}
+	if resp == nil || resp.Body == nil || resp.Body.SecurityPolicyId == nil {
+		return "", fmt.Errorf("invalid response from CreateSecurityPolicy API")
+	}
+	id := tea.StringValue(resp.Body.SecurityPolicyId)
+	klog.Infof("Successfully created security policy: %s (%s), RequestId: %s", id, name, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		if err := c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout()); err != nil {
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+// UpdateListenerSecurityPolicy switches an existing TCPSSL listener to the TLS
+// security policy policyId after validating the policy, and waits for the async
+// job to finish.
+func (c *NLBClient) UpdateListenerSecurityPolicy(ctx context.Context, listenerId, policyId string) error {
+	if err := c.ValidateSecurityPolicy(ctx, policyId); err != nil {
+		return err
+	}
+
+	req := &nlbsdk.UpdateListenerAttributeRequest{
+		ListenerId:       tea.String(listenerId),
+		SecurityPolicyId: tea.String(policyId),
+	}
+	resp, err := doRequest(ctx, c, req, c.client.UpdateListenerAttribute)
+	if err != nil {
+		return fmt.Errorf("failed to update security policy of listener %s: %w", listenerId, err)
+	}
+	if resp == nil || resp.Body == nil {
+		return fmt.Errorf("invalid response from UpdateListenerAttribute API")
+	}
+	klog.Infof("Successfully updated security policy of listener: %s to %s, RequestId: %s",
+		listenerId, policyId, tea.StringValue(resp.Body.RequestId))
+
+	if resp.Body.JobId != nil {
+		return c.waitJobFinish(ctx, tea.StringValue(resp.Body.JobId), c.listenerOperationTimeout())
+	}
+	return nil
+}
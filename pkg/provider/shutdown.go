@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// inflightJobs tracks the async jobs currently waited for by any NLBClient, so
+// the ones abandoned at shutdown can be reported.
+var inflightJobs = struct {
+	mu   sync.Mutex
+	jobs map[string]time.Time
+}{jobs: make(map[string]time.Time)}
+
+func trackJob(jobId string) func() {
+	inflightJobs.mu.Lock()
+	inflightJobs.jobs[jobId] = time.Now()
+	inflightJobs.mu.Unlock()
+	return func() {
+		inflightJobs.mu.Lock()
+		delete(inflightJobs.jobs, jobId)
+		inflightJobs.mu.Unlock()
+	}
+}
+
+// InflightJobs returns the IDs of the async jobs still being waited for, sorted.
+// Called after the manager stopped, they are the operations left incomplete.
+func InflightJobs() []string {
+	inflightJobs.mu.Lock()
+	defer inflightJobs.mu.Unlock()
+	ids := make([]string, 0, len(inflightJobs.jobs))
+	for id := range inflightJobs.jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// jobContext returns the context to wait for an async job in. Cancelling ctx,
+// e.g. on SIGTERM, does not abandon the job right away: the wait continues for up
+// to ShutdownGracePeriod so the change reaches a consistent state first.
+func (c *NLBClient) jobContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.ShutdownGracePeriod <= 0 {
+		return ctx, func() {}
+	}
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-jobCtx.Done():
+			return
+		}
+		timer := time.NewTimer(c.ShutdownGracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-jobCtx.Done():
+		}
+	}()
+	return jobCtx, cancel
+}
@@ -0,0 +1,7 @@
+// Package version holds the build information of the operator.
+package version
+
+// Version is the operator version, set at build time with
+//
+//	-ldflags "-X github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/version.Version=<version>"
+var Version = "dev"
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// +kubebuilder:webhook:path=/validate-nlboperator-alibabacloud-com-v1-listener,mutating=false,failurePolicy=fail,sideEffects=None,groups=nlboperator.alibabacloud.com,resources=listeners,verbs=create;update,versions=v1,name=vlistener.nlboperator.alibabacloud.com,admissionReviewVersions=v1
+
+// ListenerValidator rejects Listener CRs breaking the rules of ValidateListener or
+// whose port is already used by another Listener of the same NLB, since an NLB
+// cannot have two listeners on one port.
+type ListenerValidator struct {
+	client.Reader
+}
+
+var _ admission.CustomValidator = &ListenerValidator{}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *ListenerValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	lsn, ok := obj.(*nlbv1.Listener)
+	if !ok {
+		return nil, fmt.Errorf("expected a Listener object but got %T", obj)
+	}
+	if err := ValidateListener(lsn); err != nil {
+		return nil, err
+	}
+	return nil, v.validatePort(ctx, lsn)
+}
+
+// ValidateUpdate implements admission.CustomValidator. Port conflicts are only
+// checked on a change of the NLB reference or port, so existing conflicts do not
+// block other edits.
+func (v *ListenerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldLsn, ok := oldObj.(*nlbv1.Listener)
+	if !ok {
+		return nil, fmt.Errorf("expected a Listener object but got %T", oldObj)
+	}
+	lsn, ok := newObj.(*nlbv1.Listener)
+	if !ok {
+		return nil, fmt.Errorf("expected a Listener object but got %T", newObj)
+	}
+	if err := ValidateListener(lsn); err != nil {
+		return nil, err
+	}
+	if oldLsn.Spec.LoadBalancerRef == lsn.Spec.LoadBalancerRef && oldLsn.Spec.ListenerPort == lsn.Spec.ListenerPort {
+		return nil, nil
+	}
+	return nil, v.validatePort(ctx, lsn)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *ListenerValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ListenerValidator) validatePort(ctx context.Context, lsn *nlbv1.Listener) error {
+	list := &nlbv1.ListenerList{}
+	if err := v.List(ctx, list, client.InNamespace(lsn.Namespace)); err != nil {
+		return fmt.Errorf("failed to list Listeners: %w", err)
+	}
+	return PortConflicts(lsn, list.Items)
+}
+
+// SetupWithManager registers the validating webhook with the manager's webhook server.
+func (v *ListenerValidator) SetupWithManager(mgr ctrl.Manager) error {
+	if v.Reader == nil {
+		v.Reader = mgr.GetClient()
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nlbv1.Listener{}).
+		WithValidator(v).
+		Complete()
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+func TestListenerValidatorPortConflicts(t *testing.T) {
+	existing := func(name, namespace, nlbRef string, port int32) *nlbv1.Listener {
+		lsn := testListener("TCP")
+		lsn.Name = name
+		lsn.Namespace = namespace
+		lsn.Spec.LoadBalancerRef = nlbRef
+		lsn.Spec.ListenerPort = port
+		return lsn
+	}
+	deleting := existing("old", "default", "nlb", 443)
+	now := metav1.Now()
+	deleting.Finalizers = []string{nlbv1.ListenerFinalizer}
+	deleting.DeletionTimestamp = &now
+
+	tests := []struct {
+		name     string
+		existing []client.Object
+		wantErr  bool
+	}{
+		{name: "no other listeners"},
+		{name: "same port on the same NLB", existing: []client.Object{existing("api", "default", "nlb", 443)}, wantErr: true},
+		{name: "other port on the same NLB", existing: []client.Object{existing("api", "default", "nlb", 80)}},
+		{name: "same port on another NLB", existing: []client.Object{existing("api", "default", "other", 443)}},
+		{name: "same port in another namespace", existing: []client.Object{existing("api", "other", "nlb", 443)}},
+		{name: "same port on a Listener being deleted", existing: []client.Object{deleting}},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := nlbv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ListenerValidator{
+				Reader: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(tt.existing...).Build(),
+			}
+			_, err := v.ValidateCreate(context.Background(), testListener("TCP"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListenerValidatorUpdateKeepsExistingConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := nlbv1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	other := testListener("TCP")
+	other.Name = "api"
+	v := &ListenerValidator{
+		Reader: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(other).Build(),
+	}
+
+	oldLsn := testListener("TCP")
+	newLsn := oldLsn.DeepCopy()
+	newLsn.Spec.Name = "renamed"
+	if _, err := v.ValidateUpdate(context.Background(), oldLsn, newLsn); err != nil {
+		t.Errorf("update not touching the port got error %v, want none", err)
+	}
+
+	oldLsn.Spec.ListenerPort = 80
+	if _, err := v.ValidateUpdate(context.Background(), oldLsn, newLsn); err == nil {
+		t.Error("update moving onto a used port got no error")
+	}
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// +kubebuilder:webhook:path=/mutate-nlboperator-alibabacloud-com-v1-nlb,mutating=true,failurePolicy=fail,sideEffects=None,groups=nlboperator.alibabacloud.com,resources=nlbs,verbs=create,versions=v1,name=mnlb.nlboperator.alibabacloud.com,admissionReviewVersions=v1
+
+// NLBDefaulter applies operator-wide policy defaults to NLB CRs on admission.
+type NLBDefaulter struct {
+	// DeletionProtectionNamespaces are glob patterns (path.Match syntax, e.g. "prod-*").
+	// NLBs created in a matching namespace without an explicit DeletionProtection
+	// get DeletionProtection.Enabled=true.
+	DeletionProtectionNamespaces []string
+}
+
+var _ admission.CustomDefaulter = &NLBDefaulter{}
+
+// ParseNamespacePatterns splits a comma separated list of namespace glob patterns
+// and validates each of them.
+func ParseNamespacePatterns(value string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern %q: %v", p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// Default implements admission.CustomDefaulter.
+func (d *NLBDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	nlb, ok := obj.(*nlbv1.NLB)
+	if !ok {
+		return fmt.Errorf("expected an NLB object but got %T", obj)
+	}
+
+	// An explicit setting always wins over the policy default.
+	if nlb.Spec.DeletionProtection != nil {
+		return nil
+	}
+	if !d.matchNamespace(nlb.Namespace) {
+		return nil
+	}
+
+	klog.FromContext(ctx).Info("Defaulting deletion protection by namespace policy",
+		"namespace", nlb.Namespace, "name", nlb.Name)
+	nlb.Spec.DeletionProtection = &nlbv1.DeletionProtectionConfig{
+		Enabled: true,
+		Reason:  "nlb-operator-namespace-policy",
+	}
+	return nil
+}
+
+func (d *NLBDefaulter) matchNamespace(namespace string) bool {
+	for _, p := range d.DeletionProtectionNamespaces {
+		if ok, _ := path.Match(p, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager registers the defaulting webhook with the manager's webhook server.
+func (d *NLBDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nlbv1.NLB{}).
+		WithDefaulter(d).
+		Complete()
+}
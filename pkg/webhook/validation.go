@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+// ValidateNLB checks the business rules of an NLB spec that do not need a cluster
+// or the cloud: the enum values and cross-field rules the CRD enforces on
+// admission. Unset fields with a CRD default are treated as the default.
+func ValidateNLB(nlb *nlbv1.NLB) error {
+	spec := &nlb.Spec
+	path := field.NewPath("spec")
+	var errs field.ErrorList
+
+	addressType := spec.AddressType
+	if addressType == "" {
+		addressType = "Internet"
+	}
+	if addressType != "Internet" && addressType != "Intranet" {
+		errs = append(errs, field.NotSupported(path.Child("addressType"), spec.AddressType, []string{"Internet", "Intranet"}))
+	}
+	ipVersion := spec.AddressIpVersion
+	if ipVersion == "" {
+		ipVersion = "ipv4"
+	}
+	if ipVersion != "ipv4" && ipVersion != "DualStack" {
+		errs = append(errs, field.NotSupported(path.Child("addressIpVersion"), spec.AddressIpVersion, []string{"ipv4", "DualStack"}))
+	}
+	if spec.Ipv6AddressType != "" && ipVersion != "DualStack" {
+		errs = append(errs, field.Invalid(path.Child("ipv6AddressType"), spec.Ipv6AddressType, "requires addressIpVersion DualStack"))
+	}
+
+	if spec.VpcId == "" {
+		errs = append(errs, field.Required(path.Child("vpcId"), ""))
+	}
+	if len(spec.ZoneMappings) < 2 {
+		errs = append(errs, field.Invalid(path.Child("zoneMappings"), len(spec.ZoneMappings), "at least 2 zones are required"))
+	}
+	zones := make(map[string]bool, len(spec.ZoneMappings))
+	for i, zm := range spec.ZoneMappings {
+		zmPath := path.Child("zoneMappings").Index(i)
+		if zones[zm.ZoneId] {
+			errs = append(errs, field.Duplicate(zmPath.Child("zoneId"), zm.ZoneId))
+		}
+		zones[zm.ZoneId] = true
+		if zm.Ipv6Address != "" && ipVersion != "DualStack" {
+			errs = append(errs, field.Invalid(zmPath.Child("ipv6Address"), zm.Ipv6Address, "requires addressIpVersion DualStack"))
+		}
+	}
+
+	if spec.BandwidthPackageId != "" && addressType != "Internet" {
+		errs = append(errs, field.Invalid(path.Child("bandwidthPackageId"), spec.BandwidthPackageId, "requires addressType Internet"))
+	}
+	if spec.Capacity != nil && spec.Capacity.Cps != nil && (*spec.Capacity.Cps < 0 || *spec.Capacity.Cps > 1000000) {
+		errs = append(errs, field.Invalid(path.Child("capacity", "cps"), *spec.Capacity.Cps, "must be between 0 and 1000000"))
+	}
+	if spec.BillingConfig != nil && spec.BillingConfig.PayType != "" && spec.BillingConfig.PayType != nlbv1.PayTypePostPay {
+		errs = append(errs, field.NotSupported(path.Child("billingConfig", "payType"), spec.BillingConfig.PayType, []string{nlbv1.PayTypePostPay}))
+	}
+
+	if w := spec.MaintenanceWindow; w != nil {
+		wPath := path.Child("maintenanceWindow")
+		for _, clock := range []struct{ name, value string }{{"start", w.Start}, {"end", w.End}} {
+			if _, err := time.Parse("15:04", clock.value); err != nil {
+				errs = append(errs, field.Invalid(wPath.Child(clock.name), clock.value, "must be a time of day in HH:MM"))
+			}
+		}
+		if w.TimeZone != "" {
+			if _, err := time.LoadLocation(w.TimeZone); err != nil {
+				errs = append(errs, field.Invalid(wPath.Child("timeZone"), w.TimeZone, "must be an IANA time zone, e.g. Asia/Shanghai"))
+			}
+		}
+	}
+
+	for i, t := range spec.Tags {
+		if strings.HasPrefix(t.Key, nlbv1.ProvenanceTagPrefix) {
+			errs = append(errs, field.Invalid(path.Child("tags").Index(i).Child("key"), t.Key,
+				fmt.Sprintf("keys prefixed with %s are reserved for the operator", nlbv1.ProvenanceTagPrefix)))
+		}
+	}
+	if spec.TagMode != "" && spec.TagMode != nlbv1.TagModeAdditive && spec.TagMode != nlbv1.TagModeExclusive {
+		errs = append(errs, field.NotSupported(path.Child("tagMode"), spec.TagMode, []string{nlbv1.TagModeAdditive, nlbv1.TagModeExclusive}))
+	}
+	return errs.ToAggregate()
+}
+
+// ValidateListener checks the business rules of a Listener spec that do not need
+// a cluster or the cloud. Port conflicts with other Listeners are checked
+// separately by PortConflicts.
+func ValidateListener(lsn *nlbv1.Listener) error {
+	spec := &lsn.Spec
+	path := field.NewPath("spec")
+	var errs field.ErrorList
+
+	if spec.LoadBalancerRef == "" {
+		errs = append(errs, field.Required(path.Child("loadBalancerRef"), ""))
+	}
+	if spec.ServerGroupRef == "" {
+		errs = append(errs, field.Required(path.Child("serverGroupRef"), ""))
+	}
+	if len(spec.Name) > 256 {
+		errs = append(errs, field.TooLong(path.Child("name"), spec.Name, 256))
+	}
+	if spec.ListenerPort < 1 || spec.ListenerPort > 65535 {
+		errs = append(errs, field.Invalid(path.Child("listenerPort"), spec.ListenerPort, "must be between 1 and 65535"))
+	}
+	switch spec.ListenerProtocol {
+	case "TCP", "UDP", "TCPSSL":
+	default:
+		errs = append(errs, field.NotSupported(path.Child("listenerProtocol"), spec.ListenerProtocol, []string{"TCP", "UDP", "TCPSSL"}))
+	}
+	if len(spec.AdditionalCertificates) > 0 && spec.ListenerProtocol != "TCPSSL" {
+		errs = append(errs, field.Invalid(path.Child("additionalCertificates"), len(spec.AdditionalCertificates),
+			"only supported for TCPSSL listeners"))
+	}
+	if (spec.AlpnEnabled != nil || spec.AlpnPolicy != "") && spec.ListenerProtocol != "TCPSSL" {
+		errs = append(errs, field.Invalid(path.Child("alpnEnabled"), spec.AlpnEnabled,
+			fmt.Sprintf("ALPN is only supported for TCPSSL listeners, not %s", spec.ListenerProtocol)))
+	}
+	if spec.AlpnEnabled != nil && *spec.AlpnEnabled && spec.AlpnPolicy == "" {
+		errs = append(errs, field.Required(path.Child("alpnPolicy"), "required when alpnEnabled is true"))
+	}
+	alpnPolicies := []string{nlbv1.AlpnPolicyHTTP1Only, nlbv1.AlpnPolicyHTTP2Only, nlbv1.AlpnPolicyHTTP2Preferred, nlbv1.AlpnPolicyHTTP2Optional}
+	if spec.AlpnPolicy != "" && !slices.Contains(alpnPolicies, spec.AlpnPolicy) {
+		errs = append(errs, field.NotSupported(path.Child("alpnPolicy"), spec.AlpnPolicy, alpnPolicies))
+	}
+	if spec.Cps != nil && (*spec.Cps < 0 || *spec.Cps > 1000000) {
+		errs = append(errs, field.Invalid(path.Child("cps"), *spec.Cps, "must be between 0 and 1000000"))
+	}
+	if spec.IdleTimeout != nil {
+		max := nlbv1.ListenerIdleTimeoutMax
+		if spec.ListenerProtocol == "UDP" {
+			max = nlbv1.UDPListenerIdleTimeoutMax
+		}
+		if *spec.IdleTimeout < nlbv1.ListenerIdleTimeoutMin || *spec.IdleTimeout > max {
+			errs = append(errs, field.Invalid(path.Child("idleTimeout"), *spec.IdleTimeout,
+				fmt.Sprintf("must be between %d and %d seconds for %s listeners", nlbv1.ListenerIdleTimeoutMin, max, spec.ListenerProtocol)))
+		}
+	}
+	if spec.SecurityPolicyId != "" && spec.ListenerProtocol != "TCPSSL" {
+		errs = append(errs, field.Invalid(path.Child("securityPolicyId"), spec.SecurityPolicyId,
+			fmt.Sprintf("TLS security policies are only supported for TCPSSL listeners, not %s", spec.ListenerProtocol)))
+	}
+	if (spec.CaEnabled != nil || len(spec.CaCertificateIds) > 0) && spec.ListenerProtocol != "TCPSSL" {
+		errs = append(errs, field.Invalid(path.Child("caEnabled"), spec.CaEnabled,
+			fmt.Sprintf("mutual TLS is only supported for TCPSSL listeners, not %s", spec.ListenerProtocol)))
+	}
+	if spec.CaEnabled != nil && *spec.CaEnabled && len(spec.CaCertificateIds) == 0 {
+		errs = append(errs, field.Required(path.Child("caCertificateIds"), "at least one CA certificate is required when caEnabled is true"))
+	}
+	if spec.ProxyProtocolV2Config != nil && (spec.ProxyProtocolEnabled == nil || !*spec.ProxyProtocolEnabled) {
+		errs = append(errs, field.Invalid(path.Child("proxyProtocolV2Config"), spec.ProxyProtocolV2Config, "requires proxyProtocolEnabled"))
+	}
+	if spec.AdminState != "" && spec.AdminState != "Running" && spec.AdminState != "Stopped" {
+		errs = append(errs, field.NotSupported(path.Child("adminState"), spec.AdminState, []string{"Running", "Stopped"}))
+	}
+	return errs.ToAggregate()
+}
+
+// PortConflicts returns an error for the first Listener in others, other than lsn
+// itself, that uses the same port of the same NLB.
+func PortConflicts(lsn *nlbv1.Listener, others []nlbv1.Listener) error {
+	for _, other := range others {
+		if other.Name == lsn.Name || other.DeletionTimestamp != nil {
+			continue
+		}
+		if other.Spec.LoadBalancerRef == lsn.Spec.LoadBalancerRef && other.Spec.ListenerPort == lsn.Spec.ListenerPort {
+			return fmt.Errorf("port %d of NLB %s is already used by Listener %s (%s)",
+				lsn.Spec.ListenerPort, lsn.Spec.LoadBalancerRef, other.Name, other.Spec.ListenerProtocol)
+		}
+	}
+	return nil
+}
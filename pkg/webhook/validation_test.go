@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nlbv1 "github.com/chrisliu1995/AlibabaCloud-NLB-Operator/pkg/apis/nlboperator/v1"
+)
+
+func testListener(protocol string) *nlbv1.Listener {
+	return &nlbv1.Listener{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: nlbv1.ListenerSpec{
+			LoadBalancerRef:  "nlb",
+			ServerGroupRef:   "sg",
+			ListenerPort:     443,
+			ListenerProtocol: protocol,
+		},
+	}
+}
+
+func TestValidateListenerIdleTimeout(t *testing.T) {
+	tests := []struct {
+		protocol    string
+		idleTimeout int32
+		wantErr     bool
+	}{
+		{"TCP", 1, false},
+		{"TCP", 900, false},
+		{"TCP", 0, true},
+		{"TCP", 901, true},
+		{"TCPSSL", 900, false},
+		{"UDP", 1, false},
+		{"UDP", 20, false},
+		{"UDP", 21, true},
+		{"UDP", 900, true},
+	}
+	for _, tt := range tests {
+		lsn := testListener(tt.protocol)
+		idleTimeout := tt.idleTimeout
+		lsn.Spec.IdleTimeout = &idleTimeout
+		err := ValidateListener(lsn)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s listener with idleTimeout %d: got error %v, want error %t", tt.protocol, tt.idleTimeout, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateListenerProtocolFields(t *testing.T) {
+	enabled := true
+	allProtocols := []string{"TCP", "UDP", "TCPSSL"}
+	tcpsslOnly := []string{"TCPSSL"}
+
+	tests := []struct {
+		name    string
+		set     func(spec *nlbv1.ListenerSpec)
+		allowed []string
+	}{
+		{"cps", func(spec *nlbv1.ListenerSpec) {
+			cps := int32(100)
+			spec.Cps = &cps
+		}, allProtocols},
+		{"idleTimeout", func(spec *nlbv1.ListenerSpec) {
+			idleTimeout := int32(20)
+			spec.IdleTimeout = &idleTimeout
+		}, allProtocols},
+		{"proxyProtocol", func(spec *nlbv1.ListenerSpec) {
+			spec.ProxyProtocolEnabled = &enabled
+			spec.ProxyProtocolV2Config = &nlbv1.ProxyProtocolV2Config{VpcIdEnabled: true}
+		}, allProtocols},
+		{"securityPolicyId", func(spec *nlbv1.ListenerSpec) {
+			spec.SecurityPolicyId = "tls_cipher_policy_1_2"
+		}, tcpsslOnly},
+		{"alpn", func(spec *nlbv1.ListenerSpec) {
+			spec.AlpnEnabled = &enabled
+			spec.AlpnPolicy = nlbv1.AlpnPolicyHTTP2Preferred
+		}, tcpsslOnly},
+		{"mutual TLS", func(spec *nlbv1.ListenerSpec) {
+			spec.CaEnabled = &enabled
+			spec.CaCertificateIds = []string{"ca-1"}
+		}, tcpsslOnly},
+		{"additionalCertificates", func(spec *nlbv1.ListenerSpec) {
+			spec.AdditionalCertificates = []nlbv1.AdditionalCert{{CertificateId: "cert-1"}}
+		}, tcpsslOnly},
+	}
+	for _, tt := range tests {
+		for _, protocol := range allProtocols {
+			lsn := testListener(protocol)
+			tt.set(&lsn.Spec)
+			err := ValidateListener(lsn)
+			if want := slices.Contains(tt.allowed, protocol); (err == nil) != want {
+				t.Errorf("%s on %s listener: got error %v, want allowed %t", tt.name, protocol, err, want)
+			}
+		}
+	}
+}